@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"sei-account-generator/pkg/wallet"
+)
+
+// balanceOptions holds the flags that select how balance commands reach the chain
+type balanceOptions struct {
+	node         string
+	grpc         string
+	grpcInsecure bool
+}
+
+// register adds the balance endpoint flags to fs
+func (o *balanceOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.node, "node", wallet.DefaultNodeURL, "Sei LCD/REST endpoint to query")
+	fs.StringVar(&o.grpc, "grpc", "", "query this gRPC endpoint (host:port) instead, falling back to -node if it is unreachable")
+	fs.BoolVar(&o.grpcInsecure, "grpc-insecure", false, "connect to -grpc without TLS, e.g. for a local node on localhost:9090")
+}
+
+// endpoint describes the endpoint queries are sent to, for status messages
+func (o *balanceOptions) endpoint() string {
+	if o.grpc != "" {
+		return "gRPC " + o.grpc
+	}
+	return o.node
+}
+
+// client returns the balance client selected by the flags and a function that
+// releases it, exiting on failure. With -grpc, queries go over gRPC and switch
+// to REST for the rest of the run if the gRPC endpoint cannot be reached.
+func (o *balanceOptions) client() (wallet.BalanceQuerier, func()) {
+	rest := wallet.NewBalanceClient(o.node)
+	if o.grpc == "" {
+		return rest, func() {}
+	}
+
+	grpcClient, err := wallet.NewGRPCBalanceClient(o.grpc, o.grpcInsecure)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	return &fallbackBalances{grpc: grpcClient, rest: rest, endpoint: o.grpc, node: o.node}, func() { grpcClient.Close() }
+}
+
+// fallbackBalances sends queries over gRPC until the endpoint turns out to be
+// unreachable or lacks the bank query service, then uses REST instead
+type fallbackBalances struct {
+	grpc     wallet.BalanceQuerier
+	rest     wallet.BalanceQuerier
+	endpoint string
+	node     string
+	useREST  bool
+}
+
+// BalancesContext implements wallet.BalanceQuerier
+func (f *fallbackBalances) BalancesContext(ctx context.Context, address string) ([]wallet.Coin, error) {
+	if !f.useREST {
+		coins, err := f.grpc.BalancesContext(ctx, address)
+		if !f.fallBack(err) {
+			return coins, err
+		}
+	}
+	return f.rest.BalancesContext(ctx, address)
+}
+
+// BalanceContext implements wallet.BalanceQuerier
+func (f *fallbackBalances) BalanceContext(ctx context.Context, address, denom string) (string, error) {
+	if !f.useREST {
+		amount, err := f.grpc.BalanceContext(ctx, address, denom)
+		if !f.fallBack(err) {
+			return amount, err
+		}
+	}
+	return f.rest.BalanceContext(ctx, address, denom)
+}
+
+// fallBack reports whether a gRPC query error means REST should be used from
+// now on. Other errors, such as an invalid address, are returned as they are.
+func (f *fallbackBalances) fallBack(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Unimplemented:
+		fmt.Fprintf(os.Stderr, "gRPC endpoint %s failed (%v); falling back to REST at %s\n", f.endpoint, err, f.node)
+		f.useREST = true
+		return true
+	}
+	return false
+}
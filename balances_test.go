@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"sei-account-generator/pkg/wallet"
+)
+
+// countingBalances answers every query with amount or err and counts the queries
+type countingBalances struct {
+	amount  string
+	err     error
+	queries int
+}
+
+func (c *countingBalances) BalancesContext(ctx context.Context, address string) ([]wallet.Coin, error) {
+	c.queries++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return []wallet.Coin{{Denom: wallet.BaseDenom, Amount: c.amount}}, nil
+}
+
+func (c *countingBalances) BalanceContext(ctx context.Context, address, denom string) (string, error) {
+	c.queries++
+	return c.amount, c.err
+}
+
+func TestFallbackBalancesSwitchesToREST(t *testing.T) {
+	for _, code := range []codes.Code{codes.Unavailable, codes.Unimplemented} {
+		grpc := &countingBalances{err: status.Error(code, "no bank service")}
+		rest := &countingBalances{amount: "42"}
+		client := &fallbackBalances{grpc: grpc, rest: rest, endpoint: "localhost:9090", node: wallet.DefaultNodeURL}
+
+		if amount, err := client.BalanceContext(context.Background(), "sei1a", wallet.BaseDenom); amount != "42" || err != nil {
+			t.Errorf("%v: BalanceContext() = %q, %v, want the REST answer 42", code, amount, err)
+		}
+		// Once switched, gRPC is not tried again
+		coins, err := client.BalancesContext(context.Background(), "sei1b")
+		if err != nil || len(coins) != 1 || coins[0].Amount != "42" {
+			t.Errorf("%v: BalancesContext() = %v, %v, want the REST answer", code, coins, err)
+		}
+		if grpc.queries != 1 || rest.queries != 2 {
+			t.Errorf("%v: %d gRPC and %d REST queries, want 1 and 2", code, grpc.queries, rest.queries)
+		}
+	}
+}
+
+func TestFallbackBalancesReturnsOtherErrors(t *testing.T) {
+	queryErr := status.Error(codes.InvalidArgument, "invalid address")
+	grpc := &countingBalances{err: queryErr}
+	rest := &countingBalances{amount: "42"}
+	client := &fallbackBalances{grpc: grpc, rest: rest, endpoint: "localhost:9090", node: wallet.DefaultNodeURL}
+
+	if _, err := client.BalanceContext(context.Background(), "sei1a", wallet.BaseDenom); err != queryErr {
+		t.Errorf("BalanceContext() error = %v, want %v", err, queryErr)
+	}
+	grpc.err, grpc.amount = nil, "7"
+	if amount, err := client.BalanceContext(context.Background(), "sei1a", wallet.BaseDenom); amount != "7" || err != nil {
+		t.Errorf("BalanceContext() after a query error = %q, %v, want the gRPC answer 7", amount, err)
+	}
+	if rest.queries != 0 {
+		t.Errorf("REST was queried %d times, want 0", rest.queries)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// checkpointSuffix is appended to the database path to name its checkpoint file
+const checkpointSuffix = ".checkpoint"
+
+// checkpointEvery is how many saved accounts pass between checkpoint updates
+const checkpointEvery = 100
+
+// generationCheckpoint records how far a generation run got, so a run that
+// crashed or was interrupted can be continued with -resume. The accounts
+// themselves are saved batch by batch; the checkpoint only keeps the
+// target and progress, and is removed once the target is reached.
+type generationCheckpoint struct {
+	path string
+	// Target is the -count the run was started with
+	Target int `json:"target"`
+	// Stored is the number of active accounts at the last update
+	Stored    int       `json:"stored"`
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// checkpointPath returns the checkpoint file for the database at dbPath
+func checkpointPath(dbPath string) string {
+	return dbPath + checkpointSuffix
+}
+
+// readCheckpoint loads the checkpoint at path, returning nil if there is none
+func readCheckpoint(path string) (*generationCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	checkpoint := &generationCheckpoint{path: path}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+	}
+	if checkpoint.Target <= 0 {
+		return nil, fmt.Errorf("checkpoint %s has an invalid target %d", path, checkpoint.Target)
+	}
+	return checkpoint, nil
+}
+
+// newCheckpoint starts a checkpoint at path for a run towards target
+func newCheckpoint(path string, target int) *generationCheckpoint {
+	return &generationCheckpoint{path: path, Target: target, StartedAt: time.Now().UTC()}
+}
+
+// update records that stored accounts are now in the store. The file is
+// replaced atomically, so a crash during the write leaves the previous one.
+func (c *generationCheckpoint) update(stored int, mode os.FileMode) error {
+	c.Stored = stored
+	c.UpdatedAt = time.Now().UTC()
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set checkpoint permissions: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// updateCheckpoint updates checkpoint, warning on stderr if that fails. A
+// missing checkpoint only loses the -resume shortcut, so generation goes on.
+func updateCheckpoint(checkpoint *generationCheckpoint, stored int, mode os.FileMode) {
+	if err := checkpoint.update(stored, mode); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// removeCheckpoint removes checkpoint, warning on stderr if that fails
+func removeCheckpoint(checkpoint *generationCheckpoint) {
+	if err := checkpoint.remove(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// remove deletes the checkpoint once its run has reached the target
+func (c *generationCheckpoint) remove() error {
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
+	return nil
+}
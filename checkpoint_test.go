@@ -0,0 +1,32 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sei-account-generator/pkg/wallet"
+)
+
+func TestResumeAfterCrash(t *testing.T) {
+	const target = 8
+	dir := t.TempDir()
+	path := checkpointPath(filepath.Join(dir, wallet.ProfileFileName("")))
+
+	// A run towards target that died after storing three accounts leaves
+	// those accounts and its last checkpoint behind
+	runGenerateIn(t, dir, "-count", "3")
+	if err := newCheckpoint(path, target).update(3, 0o600); err != nil {
+		t.Fatalf("update() error = %v", err)
+	}
+
+	// Resuming tops up to the recorded target from the accounts actually stored
+	runGenerateIn(t, dir, "-resume")
+	if got := storedAddresses(t, dir); len(got) != target {
+		t.Errorf("resumed run left %d accounts, want %d", len(got), target)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("checkpoint still exists after the target was reached (stat error = %v)", err)
+	}
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"sei-account-generator/pkg/wallet"
+)
+
+// command is a CLI subcommand such as "balances"
+type command struct {
+	name        string
+	description string
+	run         func(args []string)
+}
+
+// commands lists every subcommand in the order shown in the usage text.
+// It is populated in init to avoid an initialization cycle with printCommands.
+var commands []command
+
+func init() {
+	commands = []command{
+		{name: "list", description: "print a table of stored accounts without secrets", run: runList},
+		{name: "show", description: "print one stored account, hiding its secrets unless -secrets is given", run: runShow},
+		{name: "lookup", description: "find the stored account for a mnemonic read from stdin", run: runLookup},
+		{name: "import-mnemonics", description: "import one mnemonic per line from a file or stdin", run: runImportMnemonics},
+		{name: "delete", description: "archive a stored account, or remove it permanently with -purge", run: runDelete},
+		{name: "restore", description: "make an archived account active again", run: runRestore},
+		{name: "rotate", description: "replace a compromised account with a new one and archive the old", run: runRotate},
+		{name: "addresses", description: "derive extra receive or change addresses for a stored account", run: runAddresses},
+		{name: "xpub", description: "print the extended public key of a stored account for watch-only use", run: runXpub},
+		{name: "balances", description: "query the on-chain balance of every stored account", run: runBalances},
+		{name: "watch", description: "poll account balances continuously and highlight changes", run: runWatch},
+		{name: "export", description: "write the stored accounts to a JSON, CSV, NDJSON or encrypted file", run: runExport},
+		{name: "funding-script", description: "write a shell script that funds every stored account", run: runFundingScript},
+		{name: "grants", description: "print fee grant or authz granter/grantee pairs for the stored accounts", run: runGrants},
+		{name: "stats", description: "print account totals, label counts and the database size", run: runStats},
+		{name: "bench", description: "measure key generation throughput without storing anything", run: runBench},
+		{name: "shell", description: "run several commands against the store with one password prompt", run: runShell},
+		{name: "profiles", description: "list the named account databases in the storage directory", run: runProfiles},
+		{name: "rekey", description: "change the database encryption password", run: runRekey},
+		{name: "backup", description: "write an encrypted copy of the database to a new file", run: runBackup},
+		{name: "diff", description: "compare the addresses stored in two database files", run: runDiff},
+		{name: "merge", description: "copy the accounts of one database file into another", run: runMerge},
+		{name: "optimize", description: "rebuild indexes and optionally shrink the database file", run: runOptimize},
+		{name: "doctor", description: "check the database and stored accounts for corruption", run: runDoctor},
+		{name: "verify", description: "run the integrity, key and mnemonic checks and print one report", run: runVerify},
+		{name: "validator-key", description: "generate a validator consensus key as priv_validator_key.json", run: runValidatorKey},
+		{name: "export-keyring", description: "write the stored accounts into a Cosmos SDK keyring", run: runExportKeyring},
+		{name: "export-seid-import", description: "print the mnemonic or command that imports one account with seid keys add --recover", run: runExportSeidImport},
+	}
+}
+
+// findCommand returns the subcommand with the given name
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// printCommands writes the name and description of each subcommand to w
+func printCommands(w io.Writer) {
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", cmd.name, cmd.description)
+	}
+}
+
+// maxPasswordAttempts is how many times a prompted database password may be retried
+const maxPasswordAttempts = 3
+
+// storeOptions holds the flags shared by every command that opens the account store
+type storeOptions struct {
+	config         string
+	dir            string
+	profile        string
+	chain          string
+	kdfIter        int
+	cipherPageSize int
+	cipherCompat   int
+	logLevel       string
+	allowDefault   bool
+	allowForeign   bool
+	metricsAddr    string
+	fileMode       modeValue
+	dirMode        modeValue
+	allowShared    bool
+}
+
+// modeValue is a flag.Value holding an octal permission mode such as 0640
+type modeValue os.FileMode
+
+// String implements flag.Value
+func (m *modeValue) String() string {
+	return fmt.Sprintf("%#o", os.FileMode(*m))
+}
+
+// Set implements flag.Value
+func (m *modeValue) Set(value string) error {
+	mode, err := strconv.ParseUint(value, 8, 32)
+	if err != nil {
+		return fmt.Errorf("invalid octal mode %q", value)
+	}
+	*m = modeValue(mode)
+	return nil
+}
+
+// register adds the shared store flags to fs
+func (o *storeOptions) register(fs *flag.FlagSet) {
+	o.registerChain(fs)
+	fs.StringVar(&o.dir, "dir", "", "directory for the encrypted account database (default ~/"+DefaultStorageDirectory+")")
+	fs.StringVar(&o.profile, "profile", wallet.DefaultProfile, "named account database to use within the storage directory")
+	fs.IntVar(&o.kdfIter, "kdf-iter", 0, "PBKDF2 iterations for the database key (0 uses the SQLCipher default; must match the value used at creation)")
+	fs.BoolVar(&o.allowDefault, "allow-default-password", true, "allow the built-in default database password (set to false to refuse it)")
+	fs.BoolVar(&o.allowForeign, "allow-foreign-prefix", false, "store accounts whose address prefix belongs to another chain than -chain (advanced)")
+	fs.BoolVar(&o.allowShared, "allow-shared-mnemonic", false, "store accounts whose mnemonic is already stored for another account that is not their HD sibling, such as a wallet with another BIP39 passphrase")
+	fs.IntVar(&o.cipherPageSize, "cipher-page-size", wallet.DefaultCipherPageSize, "SQLCipher page size in bytes (must match the value used at creation)")
+	fs.IntVar(&o.cipherCompat, "cipher-compat", 0, "open a database created by SQLCipher 1, 2 or 3 with that version's defaults (0 for SQLCipher 4)")
+	o.fileMode, o.dirMode = modeValue(wallet.DefaultFileMode), modeValue(wallet.DefaultDirMode)
+	fs.Var(&o.fileMode, "file-mode", "octal permission mode of a new database and of exported files (0640 allows group read)")
+	fs.Var(&o.dirMode, "dir-mode", "octal permission mode of created directories (0750 allows group access)")
+	fs.StringVar(&o.metricsAddr, "metrics-addr", "", "serve Prometheus metrics for store operations, plus /healthz and /readyz, on this address, e.g. :9090 (off by default)")
+}
+
+// registerChain adds only the -config, -chain and -log-level flags to fs, for
+// commands that never open the store but still follow the config file
+func (o *storeOptions) registerChain(fs *flag.FlagSet) {
+	fs.StringVar(&o.config, "config", "", "config file with default settings (default ~/"+DefaultStorageDirectory+"/"+ConfigFileName+")")
+	fs.StringVar(&o.chain, "chain", wallet.DefaultChain, "chain to generate addresses for ("+strings.Join(wallet.ChainNames(), ", ")+")")
+	fs.StringVar(&o.logLevel, "log-level", "info", "minimum level of log messages written to stderr: debug, info, warn or error")
+}
+
+// parse parses args into fs and fills the store flags that were not given on
+// the command line from the config file, exiting on failure. The config is
+// returned so commands can apply their own settings from it.
+func (o *storeOptions) parse(fs *flag.FlagSet, args []string) fileConfig {
+	fs.Parse(args)
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(o.logLevel)); err != nil {
+		fmt.Printf("Error: unsupported -log-level %q (supported: debug, info, warn, error)\n", o.logLevel)
+		os.Exit(1)
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+
+	path, explicit := o.config, o.config != ""
+	if !explicit {
+		var err error
+		if path, err = defaultConfigPath(); err != nil {
+			fmt.Printf("Error resolving config file: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	cfg, err := loadConfig(path, explicit)
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	set := flagsSet(fs)
+	if !set["dir"] && cfg.Dir != "" {
+		o.dir = cfg.Dir
+	}
+	if !set["chain"] && cfg.Chain != "" {
+		o.chain = cfg.Chain
+	}
+	if !set["profile"] && cfg.Profile != "" {
+		o.profile = cfg.Profile
+	}
+	// SQLCipher 1 to 3 databases use 1024-byte pages unless created otherwise
+	if !set["cipher-page-size"] && o.cipherCompat >= 1 && o.cipherCompat <= 3 {
+		o.cipherPageSize = 1024
+	}
+	if err := wallet.ValidateProfileName(o.profile); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	return cfg
+}
+
+// configureChain applies the -chain selection to the SDK config, exiting on failure
+func (o *storeOptions) configureChain() wallet.ChainConfig {
+	chain, err := wallet.LookupChain(o.chain)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := wallet.ConfigureChain(chain.AccountPrefix); err != nil {
+		fmt.Printf("Error configuring chain: %v\n", err)
+		os.Exit(1)
+	}
+	return chain
+}
+
+// withAddressSuggestion adds the stored address closest to a mistyped one to
+// a failed lookup's error as a "did you mean" hint. Errors other than a
+// missing account or a malformed address are returned as they are.
+func withAddressSuggestion(store wallet.Store, address string, err error) error {
+	if !errors.Is(err, wallet.ErrAccountNotFound) && wallet.ValidateSeiAddress(address) == nil {
+		return err
+	}
+	if suggestion, ok, suggestErr := store.SuggestAddress(address); suggestErr == nil && ok {
+		return fmt.Errorf("%w (did you mean %s?)", err, suggestion)
+	}
+	return err
+}
+
+// maxCoinType is the largest BIP44 coin type. The path hardens it by adding
+// 2^31, so anything larger would overflow into another coin type.
+const maxCoinType = 1<<31 - 1
+
+// resolveCoinType returns the BIP44 coin type for a -coin-type flag: the flag
+// when given, else coin_type from the config file, else the chain's
+func resolveCoinType(coinTypeFlag int, cfg fileConfig, chain wallet.ChainConfig) (uint32, error) {
+	coinType := int64(chain.CoinType)
+	switch {
+	case coinTypeFlag >= 0:
+		coinType = int64(coinTypeFlag)
+	case cfg.CoinType != nil && *cfg.CoinType >= 0:
+		coinType = int64(*cfg.CoinType)
+	}
+	if coinType > maxCoinType {
+		return 0, fmt.Errorf("coin type %d is out of range: BIP44 coin types go up to %d", coinType, maxCoinType)
+	}
+	return uint32(coinType), nil
+}
+
+// openStore resolves the storage directory and database password and opens
+// the account store, exiting on failure. It also returns the storage directory.
+func (o *storeOptions) openStore() (*wallet.AccountStore, string) {
+	// Resolve the storage directory, defaulting to one under the home directory
+	storageDir, err := resolveStorageDir(o.dir)
+	if err != nil {
+		fmt.Printf("Error resolving storage directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	config := o.storeConfig()
+	config.SecretsPassphrase = resolveSecretsPassphrase()
+	// The health endpoints share the metrics listener, so they only exist with -metrics-addr
+	var health *serviceHealth
+	if o.metricsAddr != "" {
+		health = &serviceHealth{}
+		metrics, err := serveMetrics(o.metricsAddr, health)
+		if err != nil {
+			fmt.Printf("Error starting metrics listener: %v\n", err)
+			os.Exit(1)
+		}
+		config.Metrics = metrics
+	}
+
+	accountStore := unlockStore(o.dbPath(storageDir), DBPasswordEnvVar, func(password string) (*wallet.AccountStore, error) {
+		return wallet.NewAccountStoreWithConfig(storageDir, password, config)
+	})
+	health.setStore(accountStore)
+	return accountStore, storageDir
+}
+
+// openStoreFile opens the existing database at path with the cipher settings
+// from the flags, the password in envVar and secretsPassphrase, exiting on
+// failure. Commands that only read addresses pass no secrets passphrase, and
+// readOnly opens a file the command must not change, without migrating it.
+// Neither -dir nor -profile apply, and no metrics are served, so several files
+// can be open at once.
+func (o *storeOptions) openStoreFile(path, envVar, secretsPassphrase string, readOnly bool) *wallet.AccountStore {
+	if passwordPrompted(envVar) {
+		// Several databases may be unlocked in turn, so say which one the prompt is for
+		fmt.Fprintf(os.Stderr, "Opening %s\n", path)
+	}
+	config := o.storeConfig()
+	config.SecretsPassphrase = secretsPassphrase
+	config.ReadOnly = readOnly
+	return unlockStore(path, envVar, func(password string) (*wallet.AccountStore, error) {
+		return wallet.OpenAccountStoreFile(path, password, config)
+	})
+}
+
+// storeConfig returns the store settings selected by the flags. The secrets
+// passphrase is left for the caller to set where the command needs it.
+func (o *storeOptions) storeConfig() wallet.StoreConfig {
+	return wallet.StoreConfig{
+		Profile:             o.profile,
+		CipherPageSize:      o.cipherPageSize,
+		KDFIterations:       o.kdfIter,
+		CipherCompatibility: o.cipherCompat,
+		Logger:              slog.Default(),
+		// Refusal is opt-in for now so existing scripts keep working
+		RefuseDefaultPassword: !o.allowDefault,
+		FileMode:              os.FileMode(o.fileMode),
+		DirMode:               os.FileMode(o.dirMode),
+		AllowForeignPrefix:    o.allowForeign,
+		AllowSharedMnemonics:  o.allowShared,
+	}
+}
+
+// closeAndExit closes the given stores and exits with code. os.Exit skips
+// deferred calls, so commands holding an open store exit through here instead
+// to close it cleanly first.
+func closeAndExit(code int, stores ...io.Closer) {
+	for _, store := range stores {
+		if err := store.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error closing account store: %v\n", err)
+		}
+	}
+	os.Exit(code)
+}
+
+// unlockStore resolves the password of the database at dbPath from envVar or
+// the terminal and opens it with open, asking again after a wrong password
+// typed on the terminal. It exits on failure.
+func unlockStore(dbPath, envVar string, open func(password string) (*wallet.AccountStore, error)) *wallet.AccountStore {
+	for attempt := 1; ; attempt++ {
+		// Obtain the database key from the environment or an interactive prompt
+		password, err := resolveDBPassword(envVar, dbPath)
+		if err != nil {
+			fmt.Printf("Error reading database password: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Initialize account store for secure storage
+		accountStore, err := open(password)
+		if errors.Is(err, wallet.ErrWrongPassword) {
+			if passwordPrompted(envVar) && attempt < maxPasswordAttempts {
+				fmt.Fprintln(os.Stderr, "Incorrect database password, please try again.")
+				continue
+			}
+			fmt.Println("Error: incorrect database password (or different -kdf-iter / -cipher-page-size / -cipher-compat than at creation)")
+			os.Exit(1)
+		}
+		if errors.Is(err, wallet.ErrDefaultPassword) {
+			fmt.Printf("Error: the default database password is not allowed; set %s or run from a terminal\n", envVar)
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Printf("Error initializing account store: %v\n", err)
+			os.Exit(1)
+		}
+
+		return accountStore
+	}
+}
+
+// dbPath returns the path of the selected profile's database in storageDir
+func (o *storeOptions) dbPath(storageDir string) string {
+	return filepath.Join(storageDir, wallet.ProfileFileName(o.profile))
+}
+
+// resolveStorageDir returns the storage directory to use. An empty dir selects
+// the default under the user's home directory, and a leading ~ is expanded.
+func resolveStorageDir(dir string) (string, error) {
+	if dir == "" {
+		dir = filepath.Join("~", DefaultStorageDirectory)
+	}
+	return expandHome(dir)
+}
+
+// expandHome replaces a leading ~ in path with the user's home directory.
+// Both ~/ and, on Windows, ~\ are recognized.
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return filepath.Join(homeDir, strings.TrimPrefix(path, "~")), nil
+}
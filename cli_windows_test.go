@@ -0,0 +1,35 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandHomeWindowsPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("no home directory: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{`~\Jane Doe\sei accounts`, filepath.Join(home, "Jane Doe", "sei accounts")},
+		{`~/Jane Doe\sei accounts`, filepath.Join(home, "Jane Doe", "sei accounts")},
+		{`C:\Users\Jane Doe\sei accounts`, `C:\Users\Jane Doe\sei accounts`},
+		{`~Jane\sei accounts`, `~Jane\sei accounts`},
+	}
+	for _, tt := range tests {
+		got, err := expandHome(tt.path)
+		if err != nil {
+			t.Fatalf("expandHome(%q) error = %v", tt.path, err)
+		}
+		if got != tt.want {
+			t.Errorf("expandHome(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,1366 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+
+	"sei-account-generator/pkg/wallet"
+)
+
+// runList prints a table of stored accounts (label, address, creation time),
+// fetching them page by page in the requested order. With -archived it lists
+// the archived accounts instead, in insertion order.
+func runList(args []string) {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	var opts storeOptions
+	opts.register(fs)
+	sortFlag := fs.String("sort", wallet.SortByCreated, "sort order: address, created (newest first) or label")
+	limitFlag := fs.Int("limit", 0, "maximum number of accounts to show (0 shows all)")
+	archivedFlag := fs.Bool("archived", false, "list archived accounts instead, in the order they were added")
+	opts.parse(fs, args)
+
+	switch *sortFlag {
+	case wallet.SortByAddress, wallet.SortByCreated, wallet.SortByLabel:
+	default:
+		fmt.Printf("Error: unsupported -sort %q (supported: %s, %s, %s)\n", *sortFlag, wallet.SortByAddress, wallet.SortByCreated, wallet.SortByLabel)
+		os.Exit(1)
+	}
+	if *limitFlag < 0 {
+		fmt.Printf("Error: -limit must not be negative, got %d\n", *limitFlag)
+		os.Exit(1)
+	}
+
+	opts.configureChain()
+	store, _ := opts.openStore()
+	defer store.Close()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	if *archivedFlag {
+		fmt.Fprintln(w, "#\tLABEL\tADDRESS\tCREATED\tREPLACED BY")
+		archived, err := store.ListArchived()
+		if err != nil {
+			fmt.Printf("Error retrieving archived accounts: %v\n", err)
+			closeAndExit(1, store)
+		}
+		if *limitFlag > 0 && len(archived) > *limitFlag {
+			archived = archived[:*limitFlag]
+		}
+		for i, account := range archived {
+			printListRow(w, i+1, account, true)
+		}
+		w.Flush()
+		return
+	}
+
+	fmt.Fprintln(w, "#\tLABEL\tADDRESS\tCREATED")
+	shown := 0
+	for {
+		pageSize := wallet.MaxPageSize
+		if *limitFlag > 0 && *limitFlag-shown < pageSize {
+			pageSize = *limitFlag - shown
+		}
+
+		page, err := store.GetAccountsSortedPage(*sortFlag, pageSize, shown)
+		if err != nil {
+			fmt.Printf("Error retrieving accounts: %v\n", err)
+			closeAndExit(1, store)
+		}
+
+		for _, account := range page {
+			shown++
+			printListRow(w, shown, account, false)
+		}
+
+		if len(page) < pageSize || (*limitFlag > 0 && shown >= *limitFlag) {
+			break
+		}
+	}
+
+	w.Flush()
+}
+
+// printListRow writes the n-th row of the list table to w, adding the
+// successor column for archived accounts when withSuccessor is set
+func printListRow(w io.Writer, n int, account *wallet.Account, withSuccessor bool) {
+	created := ""
+	if !account.CreatedAt.IsZero() {
+		created = account.CreatedAt.Format(time.RFC3339)
+	}
+	fmt.Fprintf(w, "%d\t%s\t%s\t%s", n, account.Label, account.Address, created)
+	if withSuccessor {
+		fmt.Fprintf(w, "\t%s", account.Successor)
+	}
+	fmt.Fprintln(w)
+}
+
+// runDelete archives the stored account with the given address, or removes it
+// permanently with -purge
+func runDelete(args []string) {
+	fs := flag.NewFlagSet("delete", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s delete [flags] <address>\n\nFlags:\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	var opts storeOptions
+	opts.register(fs)
+	purgeFlag := fs.Bool("purge", false, "permanently remove the account and its keys instead of archiving it")
+	opts.parse(fs, args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	opts.configureChain()
+	store, _ := opts.openStore()
+	defer store.Close()
+
+	address := fs.Arg(0)
+	if *purgeFlag {
+		if err := store.PurgeAccount(address); err != nil {
+			fmt.Printf("Error purging account: %v\n", withAddressSuggestion(store, address, err))
+			closeAndExit(1, store)
+		}
+		fmt.Printf("Purged %s; its mnemonic and private key can only be recovered from a backup\n", address)
+		return
+	}
+
+	if err := store.DeleteAccount(address); err != nil {
+		fmt.Printf("Error archiving account: %v\n", withAddressSuggestion(store, address, err))
+		closeAndExit(1, store)
+	}
+	fmt.Printf("Archived %s; run restore to undo, or delete -purge to remove it for good\n", address)
+}
+
+// runRestore makes an archived account active again
+func runRestore(args []string) {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s restore [flags] <address>\n\nFlags:\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	var opts storeOptions
+	opts.register(fs)
+	opts.parse(fs, args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	opts.configureChain()
+	store, _ := opts.openStore()
+	defer store.Close()
+
+	if err := store.Restore(fs.Arg(0)); err != nil {
+		fmt.Printf("Error restoring account: %v\n", err)
+		closeAndExit(1, store)
+	}
+	fmt.Printf("Restored %s\n", fs.Arg(0))
+}
+
+// runRotate replaces a compromised account with a freshly generated one,
+// archiving the old account with a reference to its successor
+func runRotate(args []string) {
+	fs := flag.NewFlagSet("rotate", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s rotate [flags] <address>\n\nFlags:\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	var opts storeOptions
+	opts.register(fs)
+	labelFlag := fs.String("label", "", "label for the new account (default: the old account's label)")
+	opts.parse(fs, args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	opts.configureChain()
+	store, _ := opts.openStore()
+	defer store.Close()
+
+	successor, err := store.RotateAccount(fs.Arg(0), *labelFlag)
+	if err != nil {
+		fmt.Printf("Error rotating account: %v\n", withAddressSuggestion(store, fs.Arg(0), err))
+		closeAndExit(1, store)
+	}
+
+	fmt.Printf("Archived %s and replaced it with:\n", fs.Arg(0))
+	if successor.Label != "" {
+		fmt.Printf("Label: %s\n", successor.Label)
+	}
+	fmt.Printf("Address: %s\n", successor.Address)
+	fmt.Printf("Derivation Path: %s\n", successor.DerivationPath)
+	fmt.Printf("Move the remaining funds to the new address, and back up its mnemonic with: %s show -secrets %s\n", os.Args[0], successor.Address)
+}
+
+// runShow prints the stored account with the given address. The mnemonic and
+// private key are only printed with -secrets.
+func runShow(args []string) {
+	fs := flag.NewFlagSet("show", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s show [flags] <address>\n\nFlags:\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	var opts storeOptions
+	opts.register(fs)
+	secretsFlag := fs.Bool("secrets", false, "also print the mnemonic and private key")
+	opts.parse(fs, args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	opts.configureChain()
+	store, _ := opts.openStore()
+	defer store.Close()
+
+	account, err := store.GetAccountByAddress(fs.Arg(0))
+	if err != nil {
+		fmt.Printf("Error: %v\n", withAddressSuggestion(store, fs.Arg(0), err))
+		closeAndExit(1, store)
+	}
+
+	printAccountDetails(account, *secretsFlag)
+}
+
+// printAccountDetails prints one account for show, including its mnemonic and
+// private key only when secrets is set
+func printAccountDetails(account *wallet.Account, secrets bool) {
+	if account.Label != "" {
+		fmt.Printf("Label: %s\n", account.Label)
+	}
+	fmt.Printf("Address: %s\n", account.Address)
+	printKeyAlgorithm(account)
+	fmt.Printf("Public Key: %s\n", account.PubKey)
+	if account.DerivationPath != "" {
+		fmt.Printf("Derivation Path: %s\n", account.DerivationPath)
+	}
+	if !account.CreatedAt.IsZero() {
+		fmt.Printf("Created At: %s\n", account.CreatedAt.Format(time.RFC3339))
+	}
+
+	switch {
+	case !secrets:
+		fmt.Println("Mnemonic and private key hidden; pass -secrets to show them")
+	case account.Mnemonic != "":
+		fmt.Printf("Mnemonic: %s\n", account.Mnemonic)
+		fmt.Printf("Private Key: %s\n", account.PrivateKey)
+	default:
+		fmt.Println("Mnemonic: (none, imported from private key)")
+		fmt.Printf("Private Key: %s\n", account.PrivateKey)
+	}
+}
+
+// runLookup reads a mnemonic from stdin and prints the stored account it
+// belongs to, without echoing the phrase or any secrets
+func runLookup(args []string) {
+	fs := flag.NewFlagSet("lookup", flag.ExitOnError)
+	var opts storeOptions
+	opts.register(fs)
+	coinTypeFlag := fs.Int("coin-type", -1, "BIP44 coin type the account was derived with (default: coin_type from the config file, else the chain's)")
+	cfg := opts.parse(fs, args)
+
+	chain := opts.configureChain()
+	coinType, err := resolveCoinType(*coinTypeFlag, cfg, chain)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	store, _ := opts.openStore()
+	defer store.Close()
+
+	mnemonic, err := readLine(bufio.NewReader(os.Stdin), "Enter mnemonic:")
+	if err != nil {
+		fmt.Printf("Error reading mnemonic: %v\n", err)
+		closeAndExit(1, store)
+	}
+
+	account, err := store.FindByMnemonicForCoinType(mnemonic, coinType)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		closeAndExit(1, store)
+	}
+
+	if account.Label != "" {
+		fmt.Printf("Label: %s\n", account.Label)
+	}
+	fmt.Printf("Address: %s\n", account.Address)
+	if !account.CreatedAt.IsZero() {
+		fmt.Printf("Created At: %s\n", account.CreatedAt.Format(time.RFC3339))
+	}
+}
+
+// runBalances prints the usei balance of every stored account. Failures are
+// reported per account so one bad query doesn't abort the whole run.
+func runBalances(args []string) {
+	fs := flag.NewFlagSet("balances", flag.ExitOnError)
+	var opts storeOptions
+	opts.register(fs)
+	var balanceOpts balanceOptions
+	balanceOpts.register(fs)
+	opts.parse(fs, args)
+
+	opts.configureChain()
+	store, _ := opts.openStore()
+	defer store.Close()
+
+	accounts, err := store.GetAccounts()
+	if err != nil {
+		fmt.Printf("Error retrieving accounts: %v\n", err)
+		closeAndExit(1, store)
+	}
+
+	client, closeClient := balanceOpts.client()
+	defer closeClient()
+
+	failed := 0
+	for _, account := range accounts {
+		amount, err := client.BalanceContext(context.Background(), account.Address, wallet.BaseDenom)
+		if err != nil {
+			fmt.Printf("%s  error: %v\n", account.Address, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s  %s%s\n", account.Address, amount, wallet.BaseDenom)
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "%d of %d balance queries failed\n", failed, len(accounts))
+		closeAndExit(1, store)
+	}
+}
+
+// runExportKeyring writes the stored accounts into a Cosmos SDK keyring directory
+func runExportKeyring(args []string) {
+	fs := flag.NewFlagSet("export-keyring", flag.ExitOnError)
+	var opts storeOptions
+	opts.register(fs)
+	keyringDirFlag := fs.String("keyring-dir", "", "keyring root directory, as passed to seid --keyring-dir (required)")
+	backendFlag := fs.String("keyring-backend", keyring.BackendFile, "keyring backend: file, os or test")
+	opts.parse(fs, args)
+
+	if *keyringDirFlag == "" {
+		fmt.Println("Error: -keyring-dir is required")
+		os.Exit(1)
+	}
+	keyringDir, err := expandHome(*keyringDirFlag)
+	if err != nil {
+		fmt.Printf("Error resolving keyring directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	opts.configureChain()
+	store, _ := opts.openStore()
+	defer store.Close()
+
+	result, err := store.ExportToKeyring(keyringDir, *backendFlag)
+	if err != nil {
+		fmt.Printf("Error exporting to keyring: %v\n", err)
+		closeAndExit(1, store)
+	}
+
+	taken := 0
+	for _, skipped := range result.Skipped {
+		if skipped.Existing == skipped.Address {
+			fmt.Printf("Skipped %s: already in the keyring as %q\n", skipped.Address, skipped.Name)
+			continue
+		}
+		fmt.Printf("Skipped %s: key name %q is already used by %s\n", skipped.Address, skipped.Name, skipped.Existing)
+		taken++
+	}
+	fmt.Printf("Exported %d accounts to the %s keyring in %s\n", result.Exported, *backendFlag, keyringDir)
+	if taken > 0 {
+		// Those accounts are missing from the keyring
+		closeAndExit(1, store)
+	}
+}
+
+// runExportSeidImport prints what `seid keys add --recover` needs to import one
+// stored account: a ready-to-pipe command, or with -mnemonic-only just the
+// mnemonic it reads from stdin. The key name goes to stderr so stdout can be
+// piped or captured as is. The private key is never printed.
+func runExportSeidImport(args []string) {
+	fs := flag.NewFlagSet("export-seid-import", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s export-seid-import [flags] <address>\n\nFlags:\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	var opts storeOptions
+	opts.register(fs)
+	nameFlag := fs.String("name", "", "key name in the seid keyring (default: the account label, or the address without one)")
+	mnemonicOnlyFlag := fs.Bool("mnemonic-only", false, "print only the mnemonic, as seid keys add --recover reads it from stdin")
+	keyringDirFlag := fs.String("keyring-dir", "", "add --keyring-dir to the printed command")
+	backendFlag := fs.String("keyring-backend", "", "add --keyring-backend to the printed command: file, os or test")
+	opts.parse(fs, args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *mnemonicOnlyFlag && (*keyringDirFlag != "" || *backendFlag != "") {
+		fmt.Println("Error: -keyring-dir and -keyring-backend only apply to the printed command, not -mnemonic-only")
+		os.Exit(1)
+	}
+	keyringDir := *keyringDirFlag
+	if keyringDir != "" {
+		var err error
+		if keyringDir, err = expandHome(keyringDir); err != nil {
+			fmt.Printf("Error resolving keyring directory: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	opts.configureChain()
+	store, _ := opts.openStore()
+	account, err := store.GetAccountByAddress(fs.Arg(0))
+	if err != nil {
+		err = withAddressSuggestion(store, fs.Arg(0), err)
+	}
+	// os.Exit skips deferred calls, so close the store explicitly first
+	store.Close()
+	if err != nil {
+		fmt.Printf("Error retrieving account: %v\n", err)
+		os.Exit(1)
+	}
+
+	name := *nameFlag
+	if name == "" {
+		name = wallet.KeyringKeyName(account)
+	}
+
+	if *mnemonicOnlyFlag {
+		if err := wallet.CheckSeidRecoverable(account); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(account.Mnemonic)
+	} else {
+		command, err := wallet.SeidRecoverCommand(account, name, *backendFlag, keyringDir)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(command)
+	}
+	fmt.Fprintf(os.Stderr, "Key name: %s\n", name)
+}
+
+// runProfiles lists the profiles that have a database in the storage directory
+func runProfiles(args []string) {
+	fs := flag.NewFlagSet("profiles", flag.ExitOnError)
+	var opts storeOptions
+	opts.register(fs)
+	opts.parse(fs, args)
+
+	storageDir, err := resolveStorageDir(opts.dir)
+	if err != nil {
+		fmt.Printf("Error resolving storage directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	profiles, err := wallet.ListProfiles(storageDir)
+	if err != nil {
+		fmt.Printf("Error listing profiles: %v\n", err)
+		os.Exit(1)
+	}
+	if len(profiles) == 0 {
+		fmt.Printf("No account databases in %s\n", storageDir)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROFILE\tDATABASE")
+	for _, profile := range profiles {
+		fmt.Fprintf(w, "%s\t%s\n", profile, filepath.Join(storageDir, wallet.ProfileFileName(profile)))
+	}
+	w.Flush()
+}
+
+// runFundingScript writes a shell script with one funding command per stored account
+func runFundingScript(args []string) {
+	fs := flag.NewFlagSet("funding-script", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s funding-script [flags] <file>\n\nFlags:\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	var opts storeOptions
+	opts.register(fs)
+	templateFlag := fs.String("template", wallet.DefaultFundingTemplate, "command run for each account; "+wallet.FundingAddressPlaceholder+" is replaced by its address")
+	opts.parse(fs, args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	path, err := expandHome(fs.Arg(0))
+	if err != nil {
+		fmt.Printf("Error resolving script path: %v\n", err)
+		os.Exit(1)
+	}
+
+	opts.configureChain()
+	store, _ := opts.openStore()
+	defer store.Close()
+
+	if err := store.ExportFundingScript(path, *templateFlag); err != nil {
+		fmt.Printf("Error writing funding script: %v\n", err)
+		closeAndExit(1, store)
+	}
+
+	fmt.Printf("Funding script written to %s\n", path)
+}
+
+// Grant output formats and kinds
+const (
+	grantsJSON     = "json"
+	grantsScript   = "script"
+	grantsFeeGrant = "feegrant"
+	grantsAuthz    = "authz"
+)
+
+// grantTemplates maps each -kind of the grants command to its command template
+var grantTemplates = map[string]string{
+	grantsFeeGrant: wallet.FeeGrantTemplate,
+	grantsAuthz:    wallet.AuthzSendTemplate,
+}
+
+// runGrants writes the granter/grantee pairs for a fee grant or authz grant
+// from -granter to every stored account to stdout, as JSON or as a shell script
+// of seid commands. Only addresses are printed, never secrets.
+func runGrants(args []string) {
+	fs := flag.NewFlagSet("grants", flag.ExitOnError)
+	var opts storeOptions
+	opts.register(fs)
+	granterFlag := fs.String("granter", "", "address that grants the allowance to every stored account (required)")
+	formatFlag := fs.String("format", grantsJSON, "output format: json for a list of pairs, or script for seid commands")
+	kindFlag := fs.String("kind", grantsFeeGrant, "grant made by the script: feegrant or authz (a send authorization)")
+	templateFlag := fs.String("template", "", "command for each pair in the script, with "+wallet.GranterPlaceholder+" and "+wallet.GranteePlaceholder+" placeholders (default: the -kind command)")
+	opts.parse(fs, args)
+
+	if *granterFlag == "" {
+		fmt.Println("Error: -granter is required")
+		os.Exit(1)
+	}
+	if *formatFlag != grantsJSON && *formatFlag != grantsScript {
+		fmt.Printf("Error: unsupported -format %q (supported: %s, %s)\n", *formatFlag, grantsJSON, grantsScript)
+		os.Exit(1)
+	}
+	template, ok := grantTemplates[*kindFlag]
+	if !ok {
+		fmt.Printf("Error: unsupported -kind %q (supported: %s, %s)\n", *kindFlag, grantsFeeGrant, grantsAuthz)
+		os.Exit(1)
+	}
+	if *templateFlag != "" {
+		template = *templateFlag
+	}
+
+	opts.configureChain()
+	store, _ := opts.openStore()
+	defer store.Close()
+
+	accounts, err := store.GetAccounts()
+	if err != nil {
+		fmt.Printf("Error retrieving accounts: %v\n", err)
+		closeAndExit(1, store)
+	}
+	pairs, err := wallet.GrantPairs(*granterFlag, accounts)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		closeAndExit(1, store)
+	}
+
+	if *formatFlag == grantsJSON {
+		data, err := json.MarshalIndent(pairs, "", "  ")
+		if err != nil {
+			fmt.Printf("Error encoding grants as JSON: %v\n", err)
+			closeAndExit(1, store)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	commands, err := wallet.GrantCommands(template, pairs)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		closeAndExit(1, store)
+	}
+	fmt.Println("#!/bin/sh")
+	fmt.Printf("# Grants %d accounts from %s\n", len(commands), *granterFlag)
+	fmt.Print("set -e\n\n")
+	for _, command := range commands {
+		fmt.Println(command)
+	}
+}
+
+// runStats prints aggregate statistics about the store
+func runStats(args []string) {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	var opts storeOptions
+	opts.register(fs)
+	opts.parse(fs, args)
+
+	opts.configureChain()
+	store, storageDir := opts.openStore()
+	defer store.Close()
+
+	stats, err := store.Stats()
+	if err != nil {
+		fmt.Printf("Error computing statistics: %v\n", err)
+		closeAndExit(1, store)
+	}
+
+	fmt.Printf("Database: %s (%d bytes)\n", opts.dbPath(storageDir), stats.FileSize)
+	fmt.Printf("Accounts: %d (%d archived)\n", stats.Accounts, stats.Archived)
+	if !stats.Earliest.IsZero() {
+		fmt.Printf("Earliest: %s\n", stats.Earliest.Format(time.RFC3339))
+		fmt.Printf("Latest: %s\n", stats.Latest.Format(time.RFC3339))
+	}
+	if len(stats.Labels) == 0 {
+		return
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LABEL\tACCOUNTS")
+	for _, label := range stats.Labels {
+		name := label.Label
+		if name == "" {
+			name = "(none)"
+		}
+		fmt.Fprintf(w, "%s\t%d\n", name, label.Count)
+	}
+	w.Flush()
+}
+
+// Formats accepted by the export command's -format flag
+const (
+	exportJSON      = "json"
+	exportCSV       = "csv"
+	exportNDJSON    = "ndjson"
+	exportEncrypted = "encrypted"
+)
+
+// exportFormats lists the export formats in the order shown in errors
+var exportFormats = []string{exportJSON, exportCSV, exportNDJSON, exportEncrypted}
+
+// runImportMnemonics imports one mnemonic per line from a file, or from stdin
+// when no file or "-" is given, printing the lines that failed and a summary.
+// It exits with status 1 if any line failed.
+func runImportMnemonics(args []string) {
+	fs := flag.NewFlagSet("import-mnemonics", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s import-mnemonics [flags] [file]\n\nFlags:\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	var opts storeOptions
+	opts.register(fs)
+	allowWeakFlag := fs.Bool("allow-weak-mnemonic", false, "accept mnemonics whose words are all the same or consecutive")
+	coinTypeFlag := fs.Int("coin-type", -1, "BIP44 coin type to derive the accounts with (default: coin_type from the config file, else the chain's)")
+	cfg := opts.parse(fs, args)
+
+	if fs.NArg() > 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	input := io.Reader(os.Stdin)
+	if path := fs.Arg(0); path != "" && path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Printf("Error opening mnemonics file: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	chain := opts.configureChain()
+	coinType, err := resolveCoinType(*coinTypeFlag, cfg, chain)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	store, _ := opts.openStore()
+	defer store.Close()
+
+	result, err := wallet.ImportMnemonics(store, input, coinType, *allowWeakFlag)
+	if result != nil {
+		for _, failure := range result.Failed {
+			fmt.Printf("Error on %v\n", failure)
+		}
+		fmt.Printf("Imported %d accounts, skipped %d duplicates, %d failed\n", result.Imported, result.Skipped, len(result.Failed))
+		if result.SharedMnemonic > 0 {
+			fmt.Printf("Skipped %d mnemonics already stored for another account (pass -allow-shared-mnemonic to import them anyway)\n", result.SharedMnemonic)
+		}
+	}
+	if err != nil {
+		fmt.Printf("Error importing mnemonics: %v\n", err)
+		closeAndExit(1, store)
+	}
+	if len(result.Failed) > 0 {
+		closeAndExit(1, store)
+	}
+}
+
+// runExport writes the stored accounts to a file in the selected format, or
+// with -addresses-only just their addresses and public keys
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	var opts storeOptions
+	opts.register(fs)
+	formatFlag := fs.String("format", exportJSON, "export format: "+strings.Join(exportFormats, ", "))
+	outFlag := fs.String("out", "", "file to write (required, except for ndjson, which defaults to stdout)")
+	addressesOnlyFlag := fs.Bool("addresses-only", false, "export only addresses and public keys, never secrets (not with -format encrypted)")
+	opts.parse(fs, args)
+
+	if !slices.Contains(exportFormats, *formatFlag) {
+		fmt.Printf("Error: unsupported -format %q (supported: %s)\n", *formatFlag, strings.Join(exportFormats, ", "))
+		os.Exit(1)
+	}
+	toStdout := *outFlag == "" || *outFlag == "-"
+	if toStdout && *formatFlag != exportNDJSON {
+		fmt.Printf("Error: -out is required for -format %s\n", *formatFlag)
+		os.Exit(1)
+	}
+	if *addressesOnlyFlag && *formatFlag == exportEncrypted {
+		fmt.Println("Error: -addresses-only exports no secrets, so there is nothing to encrypt; use -format json")
+		os.Exit(1)
+	}
+
+	outPath := *outFlag
+	if !toStdout {
+		var err error
+		if outPath, err = expandHome(outPath); err != nil {
+			fmt.Printf("Error resolving output path: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var passphrase string
+	if *formatFlag == exportEncrypted {
+		var err error
+		if passphrase, err = resolveExportPassphrase(); err != nil {
+			fmt.Printf("Error reading export passphrase: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	opts.configureChain()
+	store, _ := opts.openStore()
+	defer store.Close()
+
+	var err error
+	switch {
+	case *formatFlag == exportNDJSON:
+		err = exportNDJSONTo(store, outPath, toStdout, *addressesOnlyFlag, os.FileMode(opts.fileMode))
+	case *formatFlag == exportEncrypted:
+		err = store.ExportAccountsEncrypted(outPath, passphrase)
+	case *formatFlag == exportCSV && *addressesOnlyFlag:
+		err = store.ExportAddressesCSV(outPath)
+	case *formatFlag == exportCSV:
+		err = store.ExportAccountsCSV(outPath)
+	case *addressesOnlyFlag:
+		err = store.ExportAddressesJSON(outPath)
+	default:
+		err = store.ExportAccountsJSON(outPath)
+	}
+	if err != nil {
+		fmt.Printf("Error exporting accounts: %v\n", err)
+		closeAndExit(1, store)
+	}
+
+	if toStdout {
+		return
+	}
+	fmt.Printf("Accounts exported as %s to %s\n", *formatFlag, outPath)
+	if !*addressesOnlyFlag && *formatFlag != exportEncrypted {
+		fmt.Fprintf(os.Stderr, "Warning: %s holds unencrypted mnemonics and private keys; keep it safe or use -format encrypted\n", outPath)
+	}
+}
+
+// exportNDJSONTo streams the accounts as NDJSON to stdout or to the file at
+// path, which is truncated if it exists and set to mode either way
+func exportNDJSONTo(store *wallet.AccountStore, path string, toStdout, addressesOnly bool, mode os.FileMode) error {
+	export := store.ExportAccountsNDJSON
+	if addressesOnly {
+		export = store.ExportAddressesNDJSON
+	}
+	if toStdout {
+		return export(os.Stdout)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	// OpenFile only applies mode to a new file
+	if err := file.Chmod(mode); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to set permissions of %s: %w", path, err)
+	}
+	if err := export(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// benchReport is the JSON form of a bench run; times are in milliseconds
+type benchReport struct {
+	Accounts          int     `json:"accounts"`
+	Workers           int     `json:"workers"`
+	CPUs              int     `json:"cpus"`
+	ElapsedMillis     float64 `json:"elapsed_ms"`
+	AccountsPerSecond float64 `json:"accounts_per_second"`
+	MinMillis         float64 `json:"min_ms"`
+	MaxMillis         float64 `json:"max_ms"`
+	AvgMillis         float64 `json:"avg_ms"`
+}
+
+// runBench measures how fast this machine generates accounts, using the
+// parallel generator without opening or writing the account store
+func runBench(args []string) {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	countFlag := fs.Int("count", 1000, "number of accounts to generate")
+	wordsFlag := fs.Int("words", wallet.DefaultMnemonicWords, "number of mnemonic words per account (12, 15, 18, 21 or 24)")
+	formatFlag := fs.String("format", FormatText, "output format: text or json")
+	fs.Parse(args)
+
+	if *countFlag <= 0 {
+		fmt.Println("Error: -count must be positive")
+		os.Exit(1)
+	}
+	if *formatFlag != FormatText && *formatFlag != FormatJSON {
+		fmt.Printf("Error: unsupported -format %q (supported: %s, %s)\n", *formatFlag, FormatText, FormatJSON)
+		os.Exit(1)
+	}
+
+	result, err := wallet.BenchmarkGeneration(*countFlag, wallet.DefaultCoinType, *wordsFlag)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *formatFlag == FormatJSON {
+		millis := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err := enc.Encode(benchReport{
+			Accounts:          result.Accounts,
+			Workers:           result.Workers,
+			CPUs:              runtime.NumCPU(),
+			ElapsedMillis:     millis(result.Elapsed),
+			AccountsPerSecond: result.AccountsPerSecond(),
+			MinMillis:         millis(result.Min),
+			MaxMillis:         millis(result.Max),
+			AvgMillis:         millis(result.Avg),
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding benchmark as JSON: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Printf("Generated %d accounts (%d-word mnemonics) in %s\n", result.Accounts, *wordsFlag, result.Elapsed.Round(time.Millisecond))
+	fmt.Printf("Workers: %d of %d CPUs\n", result.Workers, runtime.NumCPU())
+	fmt.Printf("Throughput: %.1f accounts/sec\n", result.AccountsPerSecond())
+	fmt.Printf("Per account: min %s, avg %s, max %s\n", result.Min.Round(time.Microsecond), result.Avg.Round(time.Microsecond), result.Max.Round(time.Microsecond))
+}
+
+// runRekey changes the database encryption password. The new password is read
+// from SEI_DB_NEW_PASSWORD, or prompted for twice on the terminal.
+func runRekey(args []string) {
+	fs := flag.NewFlagSet("rekey", flag.ExitOnError)
+	var opts storeOptions
+	opts.register(fs)
+	opts.parse(fs, args)
+
+	opts.configureChain()
+	store, _ := opts.openStore()
+	defer store.Close()
+
+	newPassword, err := resolveNewDBPassword()
+	if err != nil {
+		fmt.Printf("Error reading new database password: %v\n", err)
+		closeAndExit(1, store)
+	}
+
+	if err := store.Rekey(newPassword); err != nil {
+		fmt.Printf("Error changing database password: %v\n", err)
+		closeAndExit(1, store)
+	}
+
+	fmt.Println("Database password changed. Use the new password from now on.")
+}
+
+// runDoctor checks that the database opens and is intact, that every stored
+// account's keys are consistent and that key derivation still matches known
+// test vectors, to help diagnose a damaged wallet file
+func runDoctor(args []string) {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	var opts storeOptions
+	opts.register(fs)
+	opts.parse(fs, args)
+
+	opts.configureChain()
+	store, storageDir := opts.openStore()
+	defer store.Close()
+
+	fmt.Printf("Database: %s\n", opts.dbPath(storageDir))
+
+	version, err := store.SchemaVersion()
+	if err != nil {
+		fmt.Printf("Error reading schema version: %v\n", err)
+		closeAndExit(1, store)
+	}
+	fmt.Printf("Schema version: %d (latest %d)\n", version, wallet.LatestSchemaVersion)
+
+	if err := store.CheckIntegrity(); err != nil {
+		fmt.Printf("Integrity: FAILED\n%v\n", err)
+		closeAndExit(1, store)
+	}
+	fmt.Println("Integrity: ok")
+
+	failures, err := store.VerifyAll()
+	if err != nil {
+		fmt.Printf("Error verifying accounts: %v\n", err)
+		closeAndExit(1, store)
+	}
+	for _, failure := range failures {
+		fmt.Printf("Account %s: %v\n", failure.Address, failure.Err)
+	}
+	if len(failures) > 0 {
+		fmt.Printf("Accounts: %d failed verification\n", len(failures))
+		closeAndExit(1, store)
+	}
+	fmt.Println("Accounts: ok")
+
+	if err := wallet.VerifyDerivation(); err != nil {
+		fmt.Printf("Derivation: FAILED\n%v\n", err)
+		closeAndExit(1, store)
+	}
+	fmt.Println("Derivation: ok")
+
+	duplicates, err := store.FindDuplicateMnemonics()
+	if err != nil {
+		fmt.Printf("Error checking for duplicate mnemonics: %v\n", err)
+		closeAndExit(1, store)
+	}
+	for _, group := range duplicates {
+		fmt.Printf("Shared mnemonic: %s\n", strings.Join(group, ", "))
+	}
+	if len(duplicates) > 0 {
+		fmt.Printf("Mnemonics: %d shared by more than one account at the same path\n", len(duplicates))
+		closeAndExit(1, store)
+	}
+	fmt.Println("Mnemonics: ok")
+}
+
+// runOptimize rebuilds the database indexes and statistics and, with -vacuum,
+// compacts the file, reporting its size before and after
+func runOptimize(args []string) {
+	fs := flag.NewFlagSet("optimize", flag.ExitOnError)
+	var opts storeOptions
+	opts.register(fs)
+	vacuumFlag := fs.Bool("vacuum", true, "also rewrite the file to reclaim space left by deleted accounts")
+	opts.parse(fs, args)
+
+	opts.configureChain()
+	store, storageDir := opts.openStore()
+	defer store.Close()
+
+	dbPath := opts.dbPath(storageDir)
+	before, err := fileSize(dbPath)
+	if err != nil {
+		fmt.Printf("Error reading database size: %v\n", err)
+		closeAndExit(1, store)
+	}
+
+	if err := store.Optimize(*vacuumFlag); err != nil {
+		fmt.Printf("Error optimizing database: %v\n", err)
+		closeAndExit(1, store)
+	}
+
+	after, err := fileSize(dbPath)
+	if err != nil {
+		fmt.Printf("Error reading database size: %v\n", err)
+		closeAndExit(1, store)
+	}
+	fmt.Printf("Optimized %s: %d bytes before, %d bytes after\n", dbPath, before, after)
+}
+
+// fileSize returns the size of the file at path in bytes
+func fileSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// runBackup writes an encrypted copy of the database to the path given as the
+// only argument, or with -keep into that directory with rotation. It is safe
+// to run while other processes use the database.
+func runBackup(args []string) {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s backup [flags] <destination>\n\nFlags:\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	var opts storeOptions
+	opts.register(fs)
+	keepFlag := fs.Int("keep", 0, "treat <destination> as a directory of timestamped backups and keep the newest N")
+	opts.parse(fs, args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	rotate := flagsSet(fs)["keep"]
+	if rotate && *keepFlag < 1 {
+		fmt.Println("Error: -keep must be at least 1")
+		os.Exit(1)
+	}
+	dest, err := expandHome(fs.Arg(0))
+	if err != nil {
+		fmt.Printf("Error resolving destination: %v\n", err)
+		os.Exit(1)
+	}
+
+	opts.configureChain()
+	store, _ := opts.openStore()
+	defer store.Close()
+
+	if rotate {
+		if err := store.BackupRotate(dest, *keepFlag); err != nil {
+			fmt.Printf("Error backing up database: %v\n", err)
+			closeAndExit(1, store)
+		}
+		fmt.Printf("Backed up database to %s, keeping the newest %d backups\n", dest, *keepFlag)
+		return
+	}
+
+	if err := store.BackupTo(dest); err != nil {
+		fmt.Printf("Error backing up database: %v\n", err)
+		closeAndExit(1, store)
+	}
+
+	fmt.Printf("Backed up database to %s\n", dest)
+}
+
+// runDiff compares the addresses stored in two database files and prints
+// those only in the first, only in the second and in both. Only the address
+// column is read, so no secrets are decrypted during the comparison.
+func runDiff(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s diff [flags] <db-a> <db-b>\n\nFlags:\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	var opts storeOptions
+	opts.register(fs)
+	formatFlag := fs.String("format", FormatText, "output format: text or json")
+	opts.parse(fs, args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if set := flagsSet(fs); set["dir"] || set["profile"] || set["metrics-addr"] {
+		fmt.Println("Error: diff opens the two database files given, so -dir, -profile and -metrics-addr do not apply")
+		os.Exit(1)
+	}
+	if *formatFlag != FormatText && *formatFlag != FormatJSON {
+		fmt.Printf("Error: unsupported -format %q (supported: %s, %s)\n", *formatFlag, FormatText, FormatJSON)
+		os.Exit(1)
+	}
+
+	paths := make([]string, 2)
+	for i := range paths {
+		path, err := expandHome(fs.Arg(i))
+		if err != nil {
+			fmt.Printf("Error resolving database path: %v\n", err)
+			os.Exit(1)
+		}
+		paths[i] = path
+	}
+
+	opts.configureChain()
+	addresses := make([][]string, 2)
+	for i, path := range paths {
+		store := opts.openStoreFile(path, DBPasswordEnvVar, "", true)
+		stored, err := store.GetAddresses()
+		store.Close()
+		if err != nil {
+			fmt.Printf("Error reading addresses from %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		addresses[i] = stored
+	}
+
+	diff := wallet.DiffAddresses(addresses[0], addresses[1])
+	if *formatFlag == FormatJSON {
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			fmt.Printf("Error encoding diff as JSON: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	printAddressGroup("Only in "+paths[0], diff.OnlyA)
+	printAddressGroup("Only in "+paths[1], diff.OnlyB)
+	printAddressGroup("In both", diff.Both)
+}
+
+// runMerge copies the accounts of one database file into another, skipping
+// addresses the destination already has, and reports the outcome. The two
+// files may have different passwords. It exits with status 1 if any source
+// account failed verification.
+func runMerge(args []string) {
+	fs := flag.NewFlagSet("merge", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s merge [flags] <src> <dst>\n\nFlags:\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	var opts storeOptions
+	opts.register(fs)
+	srcEnvFlag := fs.String("src-password-env", DBPasswordEnvVar, "environment variable holding the source database password (prompted for when unset)")
+	dstEnvFlag := fs.String("dst-password-env", DBPasswordEnvVar, "environment variable holding the destination database password (prompted for when unset)")
+	srcSecretsEnvFlag := fs.String("src-secrets-env", SecretsPassphraseEnvVar, "environment variable holding the source database's secrets passphrase, if its secrets are encrypted")
+	dstSecretsEnvFlag := fs.String("dst-secrets-env", SecretsPassphraseEnvVar, "environment variable holding the destination database's secrets passphrase, if its secrets are encrypted")
+	opts.parse(fs, args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if set := flagsSet(fs); set["dir"] || set["profile"] || set["metrics-addr"] {
+		fmt.Println("Error: merge opens the two database files given, so -dir, -profile and -metrics-addr do not apply")
+		os.Exit(1)
+	}
+
+	srcPath, err := expandHome(fs.Arg(0))
+	if err != nil {
+		fmt.Printf("Error resolving source path: %v\n", err)
+		os.Exit(1)
+	}
+	dstPath, err := expandHome(fs.Arg(1))
+	if err != nil {
+		fmt.Printf("Error resolving destination path: %v\n", err)
+		os.Exit(1)
+	}
+	if srcAbs, dstAbs := absPath(srcPath), absPath(dstPath); srcAbs == dstAbs {
+		fmt.Println("Error: source and destination are the same database")
+		os.Exit(1)
+	}
+
+	opts.configureChain()
+	// The source is only read, so it is neither migrated nor sealed
+	src := opts.openStoreFile(srcPath, *srcEnvFlag, os.Getenv(*srcSecretsEnvFlag), true)
+	defer src.Close()
+	dst := opts.openStoreFile(dstPath, *dstEnvFlag, os.Getenv(*dstSecretsEnvFlag), false)
+	defer dst.Close()
+
+	result, err := dst.MergeFrom(src)
+	if err != nil {
+		fmt.Printf("Error merging accounts: %v\n", err)
+		closeAndExit(1, dst, src)
+	}
+
+	for _, failure := range result.Failed {
+		fmt.Printf("Failed verification: %s: %v\n", failure.Address, failure.Err)
+	}
+	fmt.Printf("Merged %d accounts from %s into %s, skipped %d already present, %d with a mnemonic already stored, %d failed verification\n",
+		result.Merged, srcPath, dstPath, result.Skipped, result.SharedMnemonic, len(result.Failed))
+	if result.Archived > 0 {
+		fmt.Printf("Left out %d archived accounts of %s; restore them there first to merge them\n", result.Archived, srcPath)
+	}
+	if len(result.Failed) > 0 {
+		closeAndExit(1, dst, src)
+	}
+}
+
+// absPath returns path made absolute, or path itself if that fails
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// printAddressGroup prints a diff heading with its address count, then the
+// addresses indented below it
+func printAddressGroup(heading string, addresses []string) {
+	fmt.Printf("%s (%d):\n", heading, len(addresses))
+	for _, address := range addresses {
+		fmt.Printf("  %s\n", address)
+	}
+}
+
+// runAddresses derives and prints additional receive or change addresses for
+// one stored account without storing them or printing any keys
+func runAddresses(args []string) {
+	fs := flag.NewFlagSet("addresses", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s addresses [flags] <address>\n\nFlags:\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	var opts storeOptions
+	opts.register(fs)
+	startFlag := fs.Int("start", 1, "first address index to derive (0 is the stored account itself; with -change the default is 0)")
+	countFlag := fs.Int("count", 5, "number of addresses to derive")
+	coinTypeFlag := fs.Int("coin-type", -1, "BIP44 coin type the account was derived with (default: the one in its stored derivation path, else coin_type from the config file, else the chain's)")
+	ledgerFlag := fs.Bool("ledger-path", false, "walk the account level like a Ledger (m/44'/{coin}'/N'/0/0) instead of the address index")
+	changeFlag := fs.Bool("change", false, "derive change addresses (m/44'/{coin}'/0'/1/i) instead of receive addresses")
+	cfg := opts.parse(fs, args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *changeFlag && *ledgerFlag {
+		fmt.Println("Error: -change cannot be combined with -ledger-path")
+		os.Exit(1)
+	}
+	if *changeFlag && !flagsSet(fs)["start"] {
+		// No change address is the stored account, so start with the first one
+		*startFlag = 0
+	}
+
+	chain := opts.configureChain()
+	coinType, err := resolveCoinType(*coinTypeFlag, cfg, chain)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	store, _ := opts.openStore()
+	defer store.Close()
+
+	if *coinTypeFlag < 0 {
+		// An account with a stored path was derived with its coin type
+		if account, err := store.GetAccountByAddress(fs.Arg(0)); err == nil {
+			if params, err := hd.NewParamsFromPath(account.DerivationPath); err == nil {
+				coinType = params.CoinType
+			}
+		}
+	}
+
+	derive := store.DeriveExtraAddresses
+	if *ledgerFlag {
+		derive = store.DeriveLedgerAddresses
+	}
+	if *changeFlag {
+		derive = store.DeriveChangeAddresses
+	}
+
+	derived, err := derive(fs.Arg(0), coinType, *startFlag, *countFlag)
+	if err != nil {
+		fmt.Printf("Error deriving addresses: %v\n", withAddressSuggestion(store, fs.Arg(0), err))
+		closeAndExit(1, store)
+	}
+
+	for _, d := range derived {
+		fmt.Printf("%s  %s\n", d.Path, d.Address)
+	}
+}
+
+// runXpub prints the account-level extended public key of one stored account,
+// from which a watch-only system can derive its receive addresses
+func runXpub(args []string) {
+	fs := flag.NewFlagSet("xpub", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s xpub [flags] <address>\n\nFlags:\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	var opts storeOptions
+	opts.register(fs)
+	coinTypeFlag := fs.Int("coin-type", -1, "BIP44 coin type the account was derived with (default: coin_type from the config file, else the chain's)")
+	cfg := opts.parse(fs, args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	chain := opts.configureChain()
+	coinType, err := resolveCoinType(*coinTypeFlag, cfg, chain)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	store, _ := opts.openStore()
+	defer store.Close()
+
+	account, err := store.GetAccountByAddress(fs.Arg(0))
+	if err != nil {
+		fmt.Printf("Error retrieving account: %v\n", withAddressSuggestion(store, fs.Arg(0), err))
+		closeAndExit(1, store)
+	}
+
+	xpub, err := account.ExtendedPubKeyForCoinType(coinType)
+	if err != nil {
+		fmt.Printf("Error deriving extended public key: %v\n", err)
+		closeAndExit(1, store)
+	}
+
+	fmt.Printf("m/44'/%d'/0'  %s\n", coinType, xpub)
+}
+
+// runValidatorKey generates an ed25519 consensus key for a validator and writes
+// it in the priv_validator_key.json format a node reads from its config directory
+func runValidatorKey(args []string) {
+	fs := flag.NewFlagSet("validator-key", flag.ExitOnError)
+	var opts storeOptions
+	opts.registerChain(fs)
+	outFlag := fs.String("out", wallet.PrivValidatorKeyFileName, "file to write the key to; it must not exist")
+	opts.parse(fs, args)
+
+	// The consensus address takes the chain's valcons prefix
+	opts.configureChain()
+
+	key, err := wallet.GenerateValidatorKey()
+	if err != nil {
+		fmt.Printf("Error generating validator key: %v\n", err)
+		os.Exit(1)
+	}
+
+	out, err := expandHome(*outFlag)
+	if err != nil {
+		fmt.Printf("Error resolving output path: %v\n", err)
+		os.Exit(1)
+	}
+	if err := key.WriteFile(out); err != nil {
+		fmt.Printf("Error writing validator key: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Consensus Address: %s\n", key.ConsAddress)
+	fmt.Printf("Consensus Address (hex): %s\n", key.PrivValidatorKey.Address)
+	fmt.Printf("Wrote %s; copy it to the node's config directory and keep a backup.\n", out)
+}
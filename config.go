@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// ConfigFileName is the name of the optional config file in the default storage directory
+const ConfigFileName = "config.toml"
+
+// fileConfig holds the defaults that can be set in the config file. Zero
+// values leave the built-in defaults in place.
+type fileConfig struct {
+	Dir      string `toml:"dir"`
+	Profile  string `toml:"profile"`
+	Chain    string `toml:"chain"`
+	Count    int    `toml:"count"`
+	CoinType *int   `toml:"coin_type"`
+}
+
+// loadConfig reads the config file at path. A missing file yields an empty
+// config unless the path was given explicitly with -config.
+func loadConfig(path string, explicit bool) (fileConfig, error) {
+	var cfg fileConfig
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := toml.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		// Name the offending keys rather than just reporting that some are unknown
+		var strictErr *toml.StrictMissingError
+		if errors.As(err, &strictErr) {
+			return cfg, fmt.Errorf("unknown settings in config file %s:\n%s", path, strictErr.String())
+		}
+		return cfg, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// defaultConfigPath returns the config file location in the default storage directory
+func defaultConfigPath() (string, error) {
+	return expandHome(filepath.Join("~", DefaultStorageDirectory, ConfigFileName))
+}
+
+// flagsSet returns the names of the flags given on the command line
+func flagsSet(fs *flag.FlagSet) map[string]bool {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	return set
+}
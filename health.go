@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"sei-account-generator/pkg/wallet"
+)
+
+// HTTP paths of the health endpoints served next to the metrics
+const (
+	healthzPath = "/healthz"
+	readyzPath  = "/readyz"
+)
+
+// healthCheckTimeout bounds the database ping of a single health request
+const healthCheckTimeout = 5 * time.Second
+
+// healthStore is the part of the store the health endpoints use
+type healthStore interface {
+	PingContext(ctx context.Context) error
+}
+
+// serviceHealth answers the health endpoints for the store this process
+// opens. Until the store is open, for instance while waiting for the password,
+// the process counts as alive but not ready. So does a store that is busy with
+// an exclusive operation such as a rekey (see wallet.ErrStoreBusy).
+type serviceHealth struct {
+	mu    sync.RWMutex
+	store healthStore
+}
+
+// setStore records the opened store. It does nothing on a nil receiver, so
+// callers need not check whether a metrics listener was started.
+func (h *serviceHealth) setStore(store *wallet.AccountStore) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.store = store
+}
+
+// ping reports whether the store has been opened and, if so, pings it
+func (h *serviceHealth) ping(ctx context.Context) (opened bool, err error) {
+	h.mu.RLock()
+	store := h.store
+	h.mu.RUnlock()
+	if store == nil {
+		return false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	return true, store.PingContext(ctx)
+}
+
+// handleHealthz serves the liveness check: 200 while the store is still being
+// opened, is busy or its database answers a ping, and 500 once the ping fails
+func (h *serviceHealth) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	opened, err := h.ping(r.Context())
+	if errors.Is(err, wallet.ErrStoreBusy) {
+		fmt.Fprintln(w, "busy")
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("database unavailable: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if !opened {
+		fmt.Fprintln(w, "starting")
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+// handleReadyz serves the readiness check: 200 once the store is open and its
+// database answers a ping, and 503 before then, while it is busy or when the
+// ping fails
+func (h *serviceHealth) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	opened, err := h.ping(r.Context())
+	if !opened {
+		http.Error(w, "store not open yet", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("database unavailable: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sei-account-generator/pkg/wallet"
+)
+
+// stubPing is a healthStore whose ping returns err
+type stubPing struct{ err error }
+
+func (s stubPing) PingContext(ctx context.Context) error { return s.err }
+
+// serveHealth sends a GET for path to handler and returns the status and body
+func serveHealth(t *testing.T, handler http.HandlerFunc, path string) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec.Code, strings.TrimSpace(rec.Body.String())
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	tests := []struct {
+		name        string
+		store       healthStore
+		healthz     int
+		healthzBody string
+		readyz      int
+	}{
+		{name: "starting", healthz: http.StatusOK, healthzBody: "starting", readyz: http.StatusServiceUnavailable},
+		{name: "ok", store: stubPing{}, healthz: http.StatusOK, healthzBody: "ok", readyz: http.StatusOK},
+		{name: "busy", store: stubPing{err: wallet.ErrStoreBusy}, healthz: http.StatusOK, healthzBody: "busy", readyz: http.StatusServiceUnavailable},
+		{name: "failing", store: stubPing{err: errors.New("file is not a database")}, healthz: http.StatusInternalServerError, healthzBody: "database unavailable: file is not a database", readyz: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		health := &serviceHealth{store: tt.store}
+		if code, body := serveHealth(t, health.handleHealthz, healthzPath); code != tt.healthz || body != tt.healthzBody {
+			t.Errorf("%s: %s = %d %q, want %d %q", tt.name, healthzPath, code, body, tt.healthz, tt.healthzBody)
+		}
+		if code, _ := serveHealth(t, health.handleReadyz, readyzPath); code != tt.readyz {
+			t.Errorf("%s: %s = %d, want %d", tt.name, readyzPath, code, tt.readyz)
+		}
+	}
+}
+
+func TestHealthEndpointsPingTheStore(t *testing.T) {
+	store := openTestStore(t, t.TempDir())
+	health := &serviceHealth{}
+	health.setStore(store)
+	if code, body := serveHealth(t, health.handleReadyz, readyzPath); code != http.StatusOK || body != "ok" {
+		t.Errorf("%s of an open store = %d %q, want 200 ok", readyzPath, code, body)
+	}
+
+	store.Close()
+	if code, _ := serveHealth(t, health.handleHealthz, healthzPath); code != http.StatusInternalServerError {
+		t.Errorf("%s of a closed store = %d, want 500", healthzPath, code)
+	}
+}
@@ -1,168 +1,740 @@
 package main
 
 import (
-	"encoding/hex"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
 
-	"github.com/cosmos/cosmos-sdk/crypto/hd"
-	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/go-bip39"
+	"sei-account-generator/pkg/wallet"
 )
 
-// Account structure is unchanged, just renamed fields to be more consistent
-type Account struct {
-	Mnemonic   string
-	Address    string
-	PubKey     string
-	PrivateKey string
-}
-
 // Default configuration
 const (
 	DefaultAccountCount     = 10
 	DefaultStorageDirectory = ".sei-accounts"
+	// Output formats accepted by -format
+	FormatText = "text"
+	FormatJSON = "json"
+	// maxSkippedAccounts is how many generated accounts may turn out to be
+	// stored already before generation gives up
+	maxSkippedAccounts = 3
 )
 
-func init() {
-	// Set up Sei network configuration
-	config := sdk.GetConfig()
-	config.SetBech32PrefixForAccount("sei", "seipub")
-	config.SetBech32PrefixForValidator("seivaloper", "seivaloperpub")
-	config.SetBech32PrefixForConsensusNode("seivalcons", "seivalconspub")
-	config.Seal()
+func main() {
+	// Dispatch to a subcommand when the first argument names one
+	if len(os.Args) > 1 {
+		if cmd, ok := findCommand(os.Args[1]); ok {
+			cmd.run(os.Args[2:])
+			return
+		}
+	}
+
+	runGenerate(os.Args[1:])
 }
 
-// generateAccount creates a new account with mnemonic
-func generateAccount() (*Account, error) {
-	// Generate a random mnemonic
-	entropySizeInBits := 256 // 24 words
-	entropy, err := bip39.NewEntropy(entropySizeInBits)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate entropy: %w", err)
+// runGenerate is the default command: it tops the store up to -count accounts
+// (or imports one with -import) and prints the accounts
+func runGenerate(args []string) {
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [flags]\n       %s <command> [flags]\n\nCommands:\n", os.Args[0], os.Args[0])
+		printCommands(fs.Output())
+		fmt.Fprintf(fs.Output(), "\nFlags:\n")
+		fs.PrintDefaults()
 	}
-
-	mnemonic, err := bip39.NewMnemonic(entropy)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate mnemonic: %w", err)
+	var opts storeOptions
+	opts.register(fs)
+	importFlag := fs.Bool("import", false, "import an existing mnemonic read from stdin instead of generating accounts")
+	importKeyFlag := fs.Bool("import-key", false, "import a hex-encoded private key read from stdin (the account has no mnemonic)")
+	countFlag := fs.Int("count", DefaultAccountCount, "number of accounts to keep in the store (with -import, the number of consecutive indices to import)")
+	startIndexFlag := fs.Int("start-index", 0, "with -import, the first address index to import, for continuing a wallet whose first addresses are in use")
+	resumeFlag := fs.Bool("resume", false, "continue an interrupted run, topping up to the -count recorded in its checkpoint")
+	wordsFlag := fs.Int("words", wallet.DefaultMnemonicWords, "number of mnemonic words for generated accounts (12, 15, 18, 21 or 24)")
+	algoFlag := fs.String("algo", string(wallet.DefaultKeyAlgorithm), "key algorithm for generated or imported accounts ("+strings.Join(wallet.KeyAlgorithms(), ", ")+"); only secp256k1 is accepted by every chain and derived from a mnemonic")
+	passphraseFlag := fs.Bool("passphrase", false, "read a BIP39 passphrase (25th word) from stdin")
+	coinTypeFlag := fs.Int("coin-type", -1, "BIP44 coin type for key derivation (default: the chain's coin type, 118 for sei)")
+	vanityFlag := fs.String("vanity", "", "only keep generated addresses whose data part matches this bech32 pattern")
+	vanityPositionFlag := fs.String("vanity-position", wallet.VanityPrefix, "where the -vanity pattern must appear: prefix or suffix")
+	formatFlag := fs.String("format", FormatText, "output format for accounts: text or json")
+	allowWeakFlag := fs.Bool("allow-weak-mnemonic", false, "with -import, accept mnemonics whose words are all the same or consecutive")
+	verboseFlag := fs.Bool("verbose", false, "also print the entropy strength of each mnemonic")
+	quietFlag := fs.Bool("quiet", false, "print only addresses, keeping mnemonics and private keys off the terminal")
+	qrFlag := fs.Bool("qr", false, "print a QR code of each address for scanning into a mobile wallet")
+	templateFlag := fs.String("template", "", "Go text/template printed for each account instead of the text block, e.g. '{{.Address}} {{.Label}}'")
+	dryRunFlag := fs.Bool("dry-run", false, "generate and print -count accounts without opening or writing the database")
+	jsonStdoutFlag := fs.Bool("json-stdout", false, "generate -count accounts and write them to stdout as JSON, never touching disk (-dry-run -format json)")
+	toFileFlag := fs.String("to-file", "", "generate -count throwaway accounts and write their addresses to this file, without opening the database")
+	secretsFlag := fs.Bool("secrets", false, "with -to-file, also write each account's mnemonic and private key")
+	cfg := opts.parse(fs, args)
+	set := flagsSet(fs)
+	if !set["count"] && cfg.Count != 0 {
+		*countFlag = cfg.Count
 	}
 
-	// Derive key from mnemonic using BIP44 HD path for Sei
-	// Cosmos coin type is 118, Sei uses the same standard
-	derivationPath := "m/44'/118'/0'/0/0"
+	if *jsonStdoutFlag {
+		if set["format"] && *formatFlag != FormatJSON {
+			fmt.Println("Error: -json-stdout always writes JSON and cannot be combined with -format text")
+			os.Exit(1)
+		}
+		*dryRunFlag = true
+		*formatFlag = FormatJSON
+	}
+	if *formatFlag != FormatText && *formatFlag != FormatJSON {
+		fmt.Printf("Error: unsupported -format %q (supported: %s, %s)\n", *formatFlag, FormatText, FormatJSON)
+		os.Exit(1)
+	}
+	jsonOutput := *formatFlag == FormatJSON
+	if jsonOutput && *qrFlag {
+		fmt.Println("Error: -qr cannot be combined with -format json")
+		os.Exit(1)
+	}
+	if jsonOutput && *templateFlag != "" {
+		fmt.Println("Error: -template cannot be combined with -format json")
+		os.Exit(1)
+	}
+	out := accountOutput{format: *formatFlag, verbose: *verboseFlag, quiet: *quietFlag, qr: *qrFlag}
+	if *templateFlag != "" {
+		tmpl, err := parseAccountTemplate(*templateFlag)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		out.template = tmpl
+	}
+	// Prose status lines only appear in full text output, so a custom
+	// template's output can be piped on as it is
+	chatty := !jsonOutput && !*quietFlag && out.template == nil
 
-	// Derive private key from mnemonic
-	seed := bip39.NewSeed(mnemonic, "")
-	master, ch := hd.ComputeMastersFromSeed(seed)
+	chain := opts.configureChain()
+	coinType, err := resolveCoinType(*coinTypeFlag, cfg, chain)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
 
-	// Get private key from derivation path
-	derivedPrivateKey, err := hd.DerivePrivateKeyForPath(master, ch, derivationPath)
+	if *countFlag <= 0 {
+		fmt.Printf("Error: -count must be a positive number, got %d\n", *countFlag)
+		os.Exit(1)
+	}
+	if _, err := wallet.EntropyForWords(*wordsFlag); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	algo, err := wallet.ParseKeyAlgorithm(*algoFlag)
 	if err != nil {
-		return nil, fmt.Errorf("failed to derive private key: %w", err)
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if algo != wallet.DefaultKeyAlgorithm && (*importKeyFlag || *vanityFlag != "") {
+		fmt.Printf("Error: -algo %s cannot be combined with -import-key or -vanity\n", algo)
+		os.Exit(1)
+	}
+	if algo != wallet.DefaultKeyAlgorithm && (*importFlag || *passphraseFlag) {
+		fmt.Printf("Error: -algo %s accounts get a random key and no mnemonic, so -import and -passphrase only work with %s\n", algo, wallet.DefaultKeyAlgorithm)
+		os.Exit(1)
+	}
+	if *vanityFlag != "" {
+		if err := wallet.ValidateVanityPattern(strings.ToLower(*vanityFlag)); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if *vanityPositionFlag != wallet.VanityPrefix && *vanityPositionFlag != wallet.VanitySuffix {
+			fmt.Printf("Error: -vanity-position must be %q or %q\n", wallet.VanityPrefix, wallet.VanitySuffix)
+			os.Exit(1)
+		}
 	}
 
-	// Create private key object
-	privKey := &secp256k1.PrivKey{Key: derivedPrivateKey}
+	if *startIndexFlag < 0 {
+		fmt.Printf("Error: -start-index must not be negative, got %d\n", *startIndexFlag)
+		os.Exit(1)
+	}
+	if *startIndexFlag != 0 && !*importFlag {
+		fmt.Println("Error: -start-index only applies to -import")
+		os.Exit(1)
+	}
 
-	// Get public key
-	pubKey := privKey.PubKey()
+	if *dryRunFlag && (*importFlag || *importKeyFlag) {
+		fmt.Println("Error: -dry-run and -json-stdout cannot be combined with -import or -import-key")
+		os.Exit(1)
+	}
+	if *resumeFlag && (*dryRunFlag || *importFlag || *importKeyFlag || *toFileFlag != "") {
+		fmt.Println("Error: -resume cannot be combined with -dry-run, -json-stdout, -import, -import-key or -to-file")
+		os.Exit(1)
+	}
+	if *dryRunFlag && *quietFlag {
+		// Dry-run secrets exist only on screen, so hiding them would lose the keys
+		fmt.Println("Error: -dry-run and -json-stdout cannot be combined with -quiet")
+		os.Exit(1)
+	}
 
-	// Get address from public key
-	addr := sdk.AccAddress(pubKey.Address())
+	if *secretsFlag && *toFileFlag == "" {
+		fmt.Println("Error: -secrets only applies to -to-file")
+		os.Exit(1)
+	}
+	if *toFileFlag != "" {
+		if *dryRunFlag || *importFlag || *importKeyFlag || *vanityFlag != "" || *passphraseFlag {
+			fmt.Println("Error: -to-file cannot be combined with -dry-run, -json-stdout, -import, -import-key, -vanity or -passphrase")
+			os.Exit(1)
+		}
+		path, err := expandHome(*toFileFlag)
+		if err != nil {
+			fmt.Printf("Error resolving output path: %v\n", err)
+			os.Exit(1)
+		}
+		if err := generateToFile(*countFlag, path, algo, coinType, *wordsFlag, *secretsFlag, os.FileMode(opts.fileMode)); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	// Format the public key
-	pubKeyHex := hex.EncodeToString(pubKey.Bytes())
+	stdin := bufio.NewReader(os.Stdin)
 
-	return &Account{
-		Mnemonic:   mnemonic,
-		Address:    addr.String(),
-		PubKey:     pubKeyHex,
-		PrivateKey: hex.EncodeToString(privKey.Key),
-	}, nil
-}
+	// A dry run keeps accounts in memory only, so nothing is read from or written to disk
+	var (
+		accountStore wallet.Store
+		storageDir   string
+	)
+	if *dryRunFlag {
+		accountStore = wallet.NewMemoryStoreWithConfig(opts.storeConfig())
+	} else {
+		accountStore, storageDir = opts.openStore()
+	}
+	defer accountStore.Close()
 
-func main() {
-	// Create a home directory for storing accounts if not specified
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		fmt.Printf("Error getting home directory: %v\n", err)
-		os.Exit(1)
+	// Import accounts from a mnemonic provided on stdin, by default just the
+	// first one; -count only applies when given explicitly
+	if *importFlag {
+		importCount := 1
+		if set["count"] {
+			importCount = *countFlag
+		}
+		runImport(accountStore, stdin, algo, coinType, *startIndexFlag, importCount, *passphraseFlag, *allowWeakFlag)
+		return
 	}
 
-	// Create storage directory
-	storageDir := filepath.Join(homeDir, DefaultStorageDirectory)
+	// Import a single account from a raw private key provided on stdin
+	if *importKeyFlag {
+		runImportKey(accountStore, stdin)
+		return
+	}
 
-	// Initialize account store for secure storage
-	accountStore, err := NewAccountStore(storageDir)
-	if err != nil {
-		fmt.Printf("Error initializing account store: %v\n", err)
-		os.Exit(1)
+	// A checkpoint beside the database records the target and progress of the
+	// run, so one that crashed or was interrupted can be continued with -resume
+	var checkpoint *generationCheckpoint
+	if !*dryRunFlag {
+		path := checkpointPath(opts.dbPath(storageDir))
+		previous, err := readCheckpoint(path)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			closeAndExit(1, accountStore)
+		}
+		switch {
+		case *resumeFlag && previous == nil:
+			fmt.Println("Error: -resume found no interrupted run to continue")
+			closeAndExit(1, accountStore)
+		case *resumeFlag:
+			if set["count"] && *countFlag != previous.Target {
+				fmt.Printf("Error: -count %d differs from the interrupted run's target of %d accounts\n", *countFlag, previous.Target)
+				closeAndExit(1, accountStore)
+			}
+			*countFlag = previous.Target
+			checkpoint = previous
+			fmt.Fprintf(os.Stderr, "Resuming the run started %s towards %d accounts (%d stored at the last checkpoint)\n",
+				previous.StartedAt.Format(time.RFC3339), previous.Target, previous.Stored)
+		case previous != nil:
+			fmt.Fprintf(os.Stderr, "Note: a run towards %d accounts was interrupted with %d stored; pass -resume to continue it\n",
+				previous.Target, previous.Stored)
+		}
 	}
-	defer accountStore.Close()
 
 	// Check if we already have accounts
 	count, err := accountStore.CountAccounts()
 	if err != nil {
 		fmt.Printf("Error counting accounts: %v\n", err)
-		os.Exit(1)
+		closeAndExit(1, accountStore)
 	}
 
 	// If we already have accounts, retrieve and display them
-	if count >= DefaultAccountCount {
-		fmt.Println("Using existing SEI accounts from secure storage")
-		printStoredAccounts(accountStore)
+	if count >= *countFlag {
+		if checkpoint != nil {
+			// The run was interrupted after its last account but before finishing up
+			removeCheckpoint(checkpoint)
+		}
+		if chatty {
+			fmt.Println("Using existing SEI accounts from secure storage")
+		}
+		out.printStoredAccounts(accountStore)
 		return
 	}
 
-	// We need to generate new accounts
-	fmt.Printf("Generating %d SEI Accounts\n", DefaultAccountCount)
-	fmt.Println("=======================")
-
-	// Generate and store accounts
-	for i := count + 1; i <= DefaultAccountCount; i++ {
-		// Generate new account
-		account, err := generateAccount()
+	// Read the passphrase once and use it for every generated account
+	var passphrase string
+	if *passphraseFlag {
+		passphrase, err = readLine(stdin, "Enter BIP39 passphrase:")
 		if err != nil {
-			fmt.Printf("Error generating account %d: %v\n", i, err)
-			os.Exit(1)
+			fmt.Printf("Error reading passphrase: %v\n", err)
+			closeAndExit(1, accountStore)
 		}
+	}
 
-		// Save account to secure storage
-		if err := accountStore.SaveAccount(account); err != nil {
-			fmt.Printf("Error saving account %d: %v\n", i, err)
-			os.Exit(1)
-		}
+	// Stop between batches on SIGINT/SIGTERM so the store is closed cleanly
+	ctx, exitCode, stop := shutdownContext()
+	defer stop()
 
-		// Print account details
-		fmt.Printf("Account #%d\n", i)
-		fmt.Printf("Address: %s\n", account.Address)
-		fmt.Printf("Mnemonic: %s\n", account.Mnemonic)
-		fmt.Printf("Public Key: %s\n", account.PubKey)
-		fmt.Printf("Private Key: %s\n", account.PrivateKey)
+	// Top up by the shortfall in stored accounts; ids and deleted rows play no
+	// part, so repeated runs converge on exactly -count accounts
+	needed := *countFlag - count
+	if !*dryRunFlag {
+		if checkpoint == nil {
+			checkpoint = newCheckpoint(checkpointPath(opts.dbPath(storageDir)), *countFlag)
+		}
+		updateCheckpoint(checkpoint, count, os.FileMode(opts.fileMode))
+	}
+	if chatty {
+		fmt.Printf("Generating %d SEI Accounts\n", needed)
 		fmt.Println("=======================")
 	}
 
+	// Generate accounts in parallel batches, or one at a time in vanity mode
+	bar := newProgress(needed)
+	generate := func(ctx context.Context, n int) ([]*wallet.Account, error) {
+		return wallet.GenerateAccountsWithAlgorithm(algo, n, coinType, *wordsFlag, passphrase)
+	}
+	if *vanityFlag != "" {
+		generate = func(ctx context.Context, n int) ([]*wallet.Account, error) {
+			account, attempts, err := wallet.GenerateVanityAccountContext(ctx, *vanityFlag, *vanityPositionFlag, coinType, *wordsFlag, passphrase)
+			if err != nil {
+				return nil, err
+			}
+			bar.clear()
+			fmt.Fprintf(os.Stderr, "Found vanity address after %d attempts\n", attempts)
+			return []*wallet.Account{account}, nil
+		}
+	}
+
+	// Store the accounts, collecting them for JSON output
+	var generated []*wallet.Account
+	saved, err := topUpAccounts(ctx, accountStore, count, *countFlag, generate, checkpoint, os.FileMode(opts.fileMode), bar,
+		func(first int, accounts []*wallet.Account) {
+			if jsonOutput {
+				generated = append(generated, accounts...)
+				return
+			}
+			// Account details go to the same terminal, so make room for them
+			bar.clear()
+			for i, account := range accounts {
+				out.printAccount(first+i, account)
+			}
+		})
+	bar.clear()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		closeAndExit(1, accountStore)
+	}
+
+	if jsonOutput {
+		out.printAccountsJSON(generated)
+	}
+	if ctx.Err() != nil {
+		if checkpoint != nil {
+			updateCheckpoint(checkpoint, count+saved, os.FileMode(opts.fileMode))
+		}
+		// os.Exit skips deferred calls, so close the store explicitly first
+		if err := accountStore.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error closing account store: %v\n", err)
+		}
+		fmt.Fprintf(os.Stderr, "Interrupted: %d new accounts were saved before stopping; the store was closed cleanly.\n", saved)
+		if checkpoint != nil {
+			fmt.Fprintln(os.Stderr, "Run the command again with -resume to generate the rest.")
+		}
+		os.Exit(exitCode())
+	}
+	if checkpoint != nil {
+		removeCheckpoint(checkpoint)
+	}
+	if *quietFlag {
+		fmt.Fprintf(os.Stderr, "Mnemonics and private keys were not printed; they are stored encrypted in %s\n", storageDir)
+		return
+	}
+	if jsonOutput {
+		return
+	}
+
+	if *dryRunFlag {
+		fmt.Println("Dry run: the accounts above were not saved.")
+		return
+	}
+
 	fmt.Println("All accounts have been securely stored on disk.")
 	fmt.Printf("You can find them in: %s\n", storageDir)
 }
 
-// printStoredAccounts displays all accounts from secure storage
-func printStoredAccounts(store *AccountStore) {
-	accounts, err := store.GetAccounts()
+// accountSource makes up to n new accounts for topUpAccounts
+type accountSource func(ctx context.Context, n int) ([]*wallet.Account, error)
+
+// topUpAccounts saves accounts from generate until store, which holds count
+// active accounts, holds target. Only the shortfall is generated, so ids and
+// deleted rows play no part and repeated runs converge on exactly target
+// accounts. Each batch of up to checkpointEvery accounts is saved at once and
+// then passed to onSaved with the number of its first account; checkpoint, if
+// not nil, is updated every checkpointEvery accounts. When ctx is done it stops
+// between batches without an error. It returns how many accounts were saved.
+func topUpAccounts(ctx context.Context, store wallet.Store, count, target int, generate accountSource,
+	checkpoint *generationCheckpoint, mode os.FileMode, bar *progress, onSaved func(first int, accounts []*wallet.Account)) (int, error) {
+	saved, skipped := 0, 0
+	for count+saved < target && ctx.Err() == nil {
+		batch, err := generate(ctx, min(checkpointEvery, target-count-saved))
+		if errors.Is(err, context.Canceled) {
+			break
+		}
+		if err != nil {
+			return saved, fmt.Errorf("failed to generate account %d: %w", count+saved+1, err)
+		}
+
+		inserted, err := store.SaveAccounts(batch)
+		if err != nil {
+			return saved, fmt.Errorf("failed to save accounts: %w", err)
+		}
+		// Fresh random accounts never collide, so repeats mean the entropy source is broken
+		if inserted < len(batch) {
+			if len(batch) > 1 {
+				// The accounts that were stored cannot be told apart from the others
+				return saved + inserted, fmt.Errorf("%d of %d generated accounts were already stored; check the system's random source",
+					len(batch)-inserted, len(batch))
+			}
+			bar.clear()
+			fmt.Fprintf(os.Stderr, "Skipped existing account %s\n", batch[0].Address)
+			if skipped++; skipped >= maxSkippedAccounts {
+				return saved, fmt.Errorf("%d generated accounts were already stored; check the system's random source", skipped)
+			}
+			continue
+		}
+
+		onSaved(count+saved+1, batch)
+		saved += inserted
+		if checkpoint != nil && saved/checkpointEvery > (saved-inserted)/checkpointEvery {
+			updateCheckpoint(checkpoint, count+saved, mode)
+		}
+		bar.update(saved)
+	}
+	return saved, nil
+}
+
+// generateToFile generates n accounts that are never stored and writes them to
+// path as they are made: one address per line, or with withSecrets a
+// tab-separated address, mnemonic and private key. The file is created, or
+// truncated if it exists, and set to mode either way. An interrupt stops
+// generation and keeps the accounts written so far.
+func generateToFile(n int, path string, algo wallet.KeyAlgorithm, coinType uint32, words int, withSecrets bool, mode os.FileMode) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
 	if err != nil {
-		fmt.Printf("Error retrieving accounts: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+	// OpenFile only applies mode to a new file, and an existing one may be
+	// readable by others
+	if err := file.Chmod(mode); err != nil {
+		return fmt.Errorf("failed to set permissions of %s: %w", path, err)
 	}
 
-	fmt.Println("=======================")
-	for i, account := range accounts {
-		fmt.Printf("Account #%d\n", i+1)
+	ctx, _, stop := shutdownContext()
+	defer stop()
+
+	w := bufio.NewWriter(file)
+	written := 0
+	for ; written < n && ctx.Err() == nil; written++ {
+		account, err := wallet.GenerateAccountWithAlgorithm(algo, coinType, words, "")
+		if err != nil {
+			return fmt.Errorf("failed to generate account %d: %w", written+1, err)
+		}
+
+		if withSecrets {
+			_, err = fmt.Fprintf(w, "%s\t%s\t%s\n", account.Address, account.Mnemonic, account.PrivateKey)
+		} else {
+			_, err = fmt.Fprintln(w, account.Address)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", path, err)
+	}
+
+	if ctx.Err() != nil {
+		fmt.Fprintf(os.Stderr, "Interrupted: wrote %d of %d accounts to %s\n", written, n, path)
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d accounts to %s\n", written, path)
+	if withSecrets {
+		fmt.Fprintf(os.Stderr, "Warning: %s holds unencrypted mnemonics and private keys; delete it when the job is done\n", path)
+	}
+	return nil
+}
+
+// runImport reads a mnemonic (and optionally a passphrase) from stdin, derives
+// count accounts from address index start on and stores them. Reading from
+// stdin keeps secrets out of shell history.
+func runImport(store wallet.Store, stdin *bufio.Reader, algo wallet.KeyAlgorithm, coinType uint32, start, count int, withPassphrase, allowWeak bool) {
+	mnemonic, err := readLine(stdin, "Enter mnemonic:")
+	if err != nil {
+		fmt.Printf("Error reading mnemonic: %v\n", err)
+		closeAndExit(1, store)
+	}
+
+	var passphrase string
+	if withPassphrase {
+		passphrase, err = readLine(stdin, "Enter BIP39 passphrase:")
+		if err != nil {
+			fmt.Printf("Error reading passphrase: %v\n", err)
+			closeAndExit(1, store)
+		}
+	}
+
+	accounts, err := wallet.ImportAccountsWithAlgorithm(mnemonic, passphrase, coinType, algo, start, count)
+	if err != nil {
+		fmt.Printf("Error importing account: %v\n", err)
+		closeAndExit(1, store)
+	}
+
+	// A patterned phrase that passes the checksum is almost always a mistake
+	if !allowWeak {
+		if err := wallet.CheckMnemonicStrength(accounts[0].Mnemonic); err != nil {
+			fmt.Printf("Error importing account: %v (pass -allow-weak-mnemonic to import it anyway)\n", err)
+			closeAndExit(1, store)
+		}
+	}
+
+	for _, account := range accounts {
+		inserted, err := store.SaveAccount(account)
+		shared := errors.Is(err, wallet.ErrMnemonicAlreadyStored)
+		if err != nil && !shared {
+			fmt.Printf("Error saving account: %v\n", err)
+			closeAndExit(1, store)
+		}
+
+		switch {
+		case inserted:
+			fmt.Println("Imported SEI account into secure storage")
+		case shared:
+			fmt.Println("Skipped account whose mnemonic is already in secure storage at this path for another address (pass -allow-shared-mnemonic to import it anyway)")
+		default:
+			fmt.Println("Skipped existing account already in secure storage")
+		}
+		fmt.Println("=======================")
+		if start != 0 || count > 1 {
+			// Tell the accounts of one mnemonic apart
+			fmt.Printf("Derivation Path: %s\n", account.DerivationPath)
+		}
 		fmt.Printf("Address: %s\n", account.Address)
-		fmt.Printf("Mnemonic: %s\n", account.Mnemonic)
 		fmt.Printf("Public Key: %s\n", account.PubKey)
-		fmt.Printf("Private Key: %s\n", account.PrivateKey)
 		fmt.Println("=======================")
 	}
 }
+
+// runImportKey reads a hex private key from stdin and stores the resulting account
+func runImportKey(store wallet.Store, stdin *bufio.Reader) {
+	hexKey, err := readLine(stdin, "Enter hex private key:")
+	if err != nil {
+		fmt.Printf("Error reading private key: %v\n", err)
+		closeAndExit(1, store)
+	}
+
+	account, err := wallet.ImportFromPrivateKey(hexKey)
+	if err != nil {
+		fmt.Printf("Error importing private key: %v\n", err)
+		closeAndExit(1, store)
+	}
+
+	inserted, err := store.SaveAccount(account)
+	if err != nil {
+		fmt.Printf("Error saving account: %v\n", err)
+		closeAndExit(1, store)
+	}
+
+	if inserted {
+		fmt.Println("Imported SEI account into secure storage")
+	} else {
+		fmt.Println("Skipped existing account already in secure storage")
+	}
+	fmt.Println("=======================")
+	fmt.Printf("Address: %s\n", account.Address)
+	fmt.Printf("Public Key: %s\n", account.PubKey)
+	fmt.Println("=======================")
+	fmt.Println("Note: this account has no mnemonic; back up the private key itself.")
+}
+
+// readLine prints prompt to stderr and reads a single line from r without the trailing newline
+func readLine(r *bufio.Reader, prompt string) (string, error) {
+	fmt.Fprintln(os.Stderr, prompt)
+	line, err := r.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// accountOutput selects how accounts are printed
+type accountOutput struct {
+	format  string
+	verbose bool
+	// quiet prints only public details, never mnemonics or private keys
+	quiet bool
+	// qr prints a QR code of each address in text output
+	qr bool
+	// template replaces the default text block when -template is given
+	template *template.Template
+}
+
+// printStoredAccounts displays all accounts from secure storage in the selected format,
+// reading them one page at a time
+func (o accountOutput) printStoredAccounts(store wallet.Store) {
+	var accounts []*wallet.Account
+	for offset := 0; ; offset += wallet.MaxPageSize {
+		page, err := store.GetAccountsPage(wallet.MaxPageSize, offset)
+		if err != nil {
+			fmt.Printf("Error retrieving accounts: %v\n", err)
+			closeAndExit(1, store)
+		}
+
+		if !o.quiet {
+			if err := revealSealed(store, page); err != nil {
+				fmt.Printf("Error retrieving accounts: %v\n", err)
+				closeAndExit(1, store)
+			}
+		}
+
+		if o.format == FormatJSON {
+			// JSON output is a single array, so collect every page first
+			accounts = append(accounts, page...)
+		} else {
+			if offset == 0 && !o.quiet && o.template == nil {
+				fmt.Println("=======================")
+			}
+			for i, account := range page {
+				o.printAccount(offset+i+1, account)
+			}
+		}
+
+		if len(page) < wallet.MaxPageSize {
+			break
+		}
+	}
+
+	if o.format == FormatJSON {
+		o.printAccountsJSON(accounts)
+	}
+}
+
+// revealSealed replaces the accounts of a bulk read whose secrets were left out
+// because they are sealed (see SEI_SECRETS_PASSPHRASE) with the decrypted
+// account when the store is unlocked. Accounts of a locked store keep their
+// empty secrets, which isSealed recognizes.
+func revealSealed(store wallet.Store, accounts []*wallet.Account) error {
+	for i, account := range accounts {
+		if !isSealed(account) {
+			continue
+		}
+		revealed, err := store.GetAccountByAddress(account.Address)
+		if errors.Is(err, wallet.ErrSecretsLocked) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		accounts[i] = revealed
+	}
+	return nil
+}
+
+// isSealed reports whether a bulk read left out the account's secrets because
+// they are sealed. Every account has a private key, so only sealing empties it.
+func isSealed(account *wallet.Account) bool {
+	return account.PrivateKey == ""
+}
+
+// printAccount prints a single account through the -template, or by default
+// as a text block, or just its address in quiet mode
+func (o accountOutput) printAccount(n int, account *wallet.Account) {
+	view, err := o.newAccountView(n, account)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	tmpl := o.template
+	if tmpl == nil {
+		tmpl = defaultTemplate
+		if o.quiet {
+			tmpl = quietTemplate
+		}
+	}
+	if err := tmpl.Execute(os.Stdout, view); err != nil {
+		fmt.Printf("\nError printing account: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// printKeyAlgorithm prints the account's key algorithm unless it is the default,
+// so output for ordinary accounts is unchanged
+func printKeyAlgorithm(account *wallet.Account) {
+	if account.KeyAlgorithm != "" && account.KeyAlgorithm != wallet.DefaultKeyAlgorithm {
+		fmt.Printf("Key Algorithm: %s\n", account.KeyAlgorithm)
+	}
+}
+
+// sealedAccountJSON is the JSON form of an account whose secrets are sealed:
+// its shallower, omitted Mnemonic and PrivateKey fields hide the account's empty
+// ones, and sealed is set instead
+type sealedAccountJSON struct {
+	*wallet.Account
+	Mnemonic   string `json:"mnemonic,omitempty"`
+	PrivateKey string `json:"private_key,omitempty"`
+	Sealed     bool   `json:"sealed"`
+}
+
+// printAccountsJSON writes accounts to stdout as an indented JSON array. In
+// quiet mode only the address and public key of each account are included.
+func (o accountOutput) printAccountsJSON(accounts []*wallet.Account) {
+	var value any = accounts
+	if slices.ContainsFunc(accounts, isSealed) {
+		sealed := make([]any, len(accounts))
+		for i, account := range accounts {
+			sealed[i] = account
+			if isSealed(account) {
+				sealed[i] = sealedAccountJSON{Account: account, Sealed: true}
+			}
+		}
+		value = sealed
+	}
+	if o.quiet {
+		public := make([]wallet.PublicAccount, len(accounts))
+		for i, account := range accounts {
+			public[i] = wallet.PublicAccount{Address: account.Address, PubKey: account.PubKey}
+		}
+		value = public
+	} else if accounts == nil {
+		value = []*wallet.Account{}
+	}
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding accounts as JSON: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(data))
+}
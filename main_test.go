@@ -0,0 +1,372 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sei-account-generator/pkg/wallet"
+)
+
+// testPassword is the database password used by the tests
+const testPassword = "test-password-123"
+
+// testMnemonic is the well-known BIP39 test vector phrase
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// runGenerateIn runs the default command against the store in dir, with the
+// password taken from the environment and the home directory isolated
+func runGenerateIn(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(DBPasswordEnvVar, testPassword)
+	runGenerate(append([]string{"-dir", dir, "-quiet"}, args...))
+}
+
+// setStdin makes input the process's standard input until the test ends
+func setStdin(t *testing.T, input string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stdin")
+	if err := os.WriteFile(path, []byte(input), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	stdin := os.Stdin
+	os.Stdin = file
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		file.Close()
+	})
+}
+
+// captureStdout runs fn and returns what it wrote to the process's standard output
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	file, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatalf("CreateTemp() error = %v", err)
+	}
+	defer file.Close()
+
+	stdout := os.Stdout
+	os.Stdout = file
+	defer func() { os.Stdout = stdout }()
+	fn()
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	return string(data)
+}
+
+// openTestStore opens the account store in dir and closes it when the test ends
+func openTestStore(t *testing.T, dir string) *wallet.AccountStore {
+	t.Helper()
+	config := wallet.DefaultStoreConfig()
+	config.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	store, err := wallet.NewAccountStoreWithConfig(dir, testPassword, config)
+	if err != nil {
+		t.Fatalf("NewAccountStoreWithConfig() error = %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// storedAddresses returns the addresses of the accounts stored in dir
+func storedAddresses(t *testing.T, dir string) []string {
+	t.Helper()
+	store := openTestStore(t, dir)
+	accounts, err := store.GetAccounts()
+	if err != nil {
+		t.Fatalf("GetAccounts() error = %v", err)
+	}
+	store.Close()
+
+	addresses := make([]string, len(accounts))
+	for i, account := range accounts {
+		addresses[i] = account.Address
+	}
+	return addresses
+}
+
+func TestTopUpAfterDeletingMiddleAccount(t *testing.T) {
+	dir := t.TempDir()
+	runGenerateIn(t, dir, "-count", "5")
+	first := storedAddresses(t, dir)
+	if len(first) != 5 {
+		t.Fatalf("first run stored %d accounts, want 5", len(first))
+	}
+
+	store := openTestStore(t, dir)
+	if err := store.DeleteAccount(first[2]); err != nil {
+		t.Fatalf("DeleteAccount() error = %v", err)
+	}
+	store.Close()
+
+	runGenerateIn(t, dir, "-count", "5")
+	second := storedAddresses(t, dir)
+	if len(second) != 5 {
+		t.Fatalf("run after deleting one account left %d accounts, want 5", len(second))
+	}
+
+	// Another run finds nothing to do
+	runGenerateIn(t, dir, "-count", "5")
+	third := storedAddresses(t, dir)
+	if len(third) != 5 {
+		t.Fatalf("repeated run left %d accounts, want 5", len(third))
+	}
+	for i := range second {
+		if third[i] != second[i] {
+			t.Errorf("repeated run changed account %d from %s to %s", i, second[i], third[i])
+		}
+	}
+}
+
+func TestImportStoredMnemonicWithPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	setStdin(t, testMnemonic+"\n")
+	runGenerateIn(t, dir, "-import", "-allow-weak-mnemonic")
+
+	// A passphrase wallet at the stored path looks like a re-import
+	setStdin(t, testMnemonic+"\nTREZOR\n")
+	runGenerateIn(t, dir, "-import", "-passphrase", "-allow-weak-mnemonic")
+	if got := storedAddresses(t, dir); len(got) != 1 {
+		t.Fatalf("stored %d accounts after importing a passphrase wallet, want 1", len(got))
+	}
+
+	setStdin(t, testMnemonic+"\nTREZOR\n")
+	runGenerateIn(t, dir, "-import", "-passphrase", "-allow-weak-mnemonic", "-allow-shared-mnemonic")
+	want, err := wallet.ImportAccount(testMnemonic, "TREZOR", wallet.DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+	addresses := storedAddresses(t, dir)
+	if len(addresses) != 2 || addresses[1] != want.Address {
+		t.Fatalf("stored addresses = %v, want the plain account and %s", addresses, want.Address)
+	}
+
+	// Further address indices of the stored phrase need no flag
+	setStdin(t, testMnemonic+"\n")
+	runGenerateIn(t, dir, "-import", "-allow-weak-mnemonic", "-start-index", "1")
+	if got := storedAddresses(t, dir); len(got) != 3 {
+		t.Errorf("stored %d accounts after importing index 1, want 3", len(got))
+	}
+}
+
+func TestGenerateSealsSecretsWithEnvPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(SecretsPassphraseEnvVar, "secrets-passphrase")
+	runGenerateIn(t, dir, "-count", "2")
+
+	// Without the passphrase the addresses are readable but the secrets are not
+	addresses := storedAddresses(t, dir)
+	if len(addresses) != 2 {
+		t.Fatalf("stored %d accounts, want 2", len(addresses))
+	}
+	store := openTestStore(t, dir)
+	if _, err := store.GetAccountByAddress(addresses[0]); !errors.Is(err, wallet.ErrSecretsLocked) {
+		t.Errorf("GetAccountByAddress() without the secrets passphrase error = %v, want ErrSecretsLocked", err)
+	}
+	store.Close()
+
+	// A later run finds the passphrase again and can save more accounts
+	runGenerateIn(t, dir, "-count", "3")
+	if got := storedAddresses(t, dir); len(got) != 3 {
+		t.Errorf("stored %d accounts after topping up, want 3", len(got))
+	}
+}
+
+func TestPrintStoredAccountsWithSealedSecrets(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(SecretsPassphraseEnvVar, "secrets-passphrase")
+	runGenerateIn(t, dir, "-count", "2")
+
+	config := wallet.DefaultStoreConfig()
+	config.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	config.SecretsPassphrase = "secrets-passphrase"
+	store, err := wallet.NewAccountStoreWithConfig(dir, testPassword, config)
+	if err != nil {
+		t.Fatalf("NewAccountStoreWithConfig() error = %v", err)
+	}
+	account, err := store.GetAccountByAddress(storedAddresses(t, dir)[0])
+	store.Close()
+	if err != nil {
+		t.Fatalf("GetAccountByAddress() error = %v", err)
+	}
+
+	// With the passphrase the stored secrets are decrypted for display
+	out := captureStdout(t, func() { runGenerate([]string{"-dir", dir, "-count", "2"}) })
+	if !strings.Contains(out, "Mnemonic: "+account.Mnemonic+"\n") || !strings.Contains(out, "Private Key: "+account.PrivateKey+"\n") {
+		t.Errorf("output with the secrets passphrase lacks the decrypted secrets:\n%s", out)
+	}
+
+	// Without it they are reported as sealed rather than missing
+	t.Setenv(SecretsPassphraseEnvVar, "")
+	out = captureStdout(t, func() { runGenerate([]string{"-dir", dir, "-count", "2"}) })
+	if strings.Count(out, "Mnemonic: (sealed)\n") != 2 || strings.Contains(out, "Private Key:") || strings.Contains(out, "imported from private key") {
+		t.Errorf("output without the secrets passphrase does not mark the secrets as sealed:\n%s", out)
+	}
+
+	out = captureStdout(t, func() { runGenerate([]string{"-dir", dir, "-count", "2", "-format", "json"}) })
+	var printed []map[string]any
+	if err := json.Unmarshal([]byte(out), &printed); err != nil {
+		t.Fatalf("JSON output does not parse: %v\n%s", err, out)
+	}
+	for _, fields := range printed {
+		_, hasMnemonic := fields["mnemonic"]
+		_, hasPrivateKey := fields["private_key"]
+		if fields["sealed"] != true || hasMnemonic || hasPrivateKey || fields["address"] == "" {
+			t.Errorf("JSON output of a sealed account = %v, want its address and sealed set without secrets", fields)
+		}
+	}
+}
+
+func TestResolveCoinType(t *testing.T) {
+	chain, err := wallet.LookupChain(wallet.DefaultChain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configured, negative, tooLarge := 60, -1, 1<<31
+
+	tests := []struct {
+		name    string
+		flag    int
+		cfg     fileConfig
+		want    uint32
+		wantErr bool
+	}{
+		{"chain default", -1, fileConfig{}, chain.CoinType, false},
+		{"config file", -1, fileConfig{CoinType: &configured}, 60, false},
+		{"negative in config file", -1, fileConfig{CoinType: &negative}, chain.CoinType, false},
+		{"flag over config file", 529, fileConfig{CoinType: &configured}, 529, false},
+		{"flag set to zero", 0, fileConfig{CoinType: &configured}, 0, false},
+		{"largest coin type", 1<<31 - 1, fileConfig{}, 1<<31 - 1, false},
+		{"flag past the hardened range", 1 << 31, fileConfig{}, 0, true},
+		{"config file past the hardened range", -1, fileConfig{CoinType: &tooLarge}, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := resolveCoinType(tt.flag, tt.cfg, chain)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: resolveCoinType() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: resolveCoinType() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWithAddressSuggestion(t *testing.T) {
+	if err := wallet.ConfigureChain(wallet.DefaultChain); err != nil {
+		t.Fatal(err)
+	}
+	store := wallet.NewMemoryStore()
+	account, err := wallet.GenerateAccount(wallet.DefaultCoinType, wallet.DefaultMnemonicWords, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.SaveAccount(account); err != nil {
+		t.Fatal(err)
+	}
+	// Dropping the last character keeps the address close but breaks its checksum
+	mistyped := account.Address[:len(account.Address)-1]
+
+	_, lookupErr := store.GetAccountByAddress(mistyped)
+	err = withAddressSuggestion(store, mistyped, lookupErr)
+	if want := "did you mean " + account.Address + "?"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("withAddressSuggestion() = %v, want a hint %q", err, want)
+	}
+
+	// Errors that are not about the address pass through unchanged
+	other := errors.New("database is locked")
+	if err := withAddressSuggestion(store, account.Address, other); err != other {
+		t.Errorf("withAddressSuggestion() of an unrelated error = %v, want it unchanged", err)
+	}
+}
+
+func TestAddressesPrintsTheDerivedPaths(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(DBPasswordEnvVar, testPassword)
+	account, err := wallet.GenerateAccountAtPath("m/44'/60'/2'/0/0", wallet.DefaultMnemonicWords, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := openTestStore(t, dir)
+	if _, err := store.SaveAccount(account); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	// Without -coin-type the stored path's coin type and account level are used
+	out := captureStdout(t, func() { runAddresses([]string{"-dir", dir, "-count", "2", account.Address}) })
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("addresses printed %q, want 2 lines", out)
+	}
+	for i, line := range lines {
+		want := fmt.Sprintf("m/44'/60'/2'/0/%d  ", i+1)
+		if !strings.HasPrefix(line, want) {
+			t.Errorf("addresses line %d = %q, want it to start with %q", i, line, want)
+		}
+	}
+}
+
+func TestMergeUsesEachFilesSecretsPassphrase(t *testing.T) {
+	srcDir, dstDir := t.TempDir(), t.TempDir()
+	t.Setenv(SecretsPassphraseEnvVar, "src-secrets")
+	runGenerateIn(t, srcDir, "-count", "2")
+	t.Setenv(SecretsPassphraseEnvVar, "dst-secrets")
+	runGenerateIn(t, dstDir, "-count", "1")
+	addresses := storedAddresses(t, srcDir)
+
+	srcPath := filepath.Join(srcDir, wallet.DBFileName)
+	before, err := os.ReadFile(srcPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(SecretsPassphraseEnvVar, "")
+	t.Setenv("SRC_SECRETS", "src-secrets")
+	t.Setenv("DST_SECRETS", "dst-secrets")
+	out := captureStdout(t, func() {
+		runMerge([]string{"-src-secrets-env", "SRC_SECRETS", "-dst-secrets-env", "DST_SECRETS",
+			srcPath, filepath.Join(dstDir, wallet.DBFileName)})
+	})
+	if !strings.HasPrefix(out, "Merged 2 accounts") {
+		t.Fatalf("merge printed %q, want 2 accounts merged", out)
+	}
+
+	// The merged secrets are sealed with the destination's passphrase
+	config := wallet.DefaultStoreConfig()
+	config.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	config.SecretsPassphrase = "dst-secrets"
+	store, err := wallet.NewAccountStoreWithConfig(dstDir, testPassword, config)
+	if err != nil {
+		t.Fatalf("NewAccountStoreWithConfig() error = %v", err)
+	}
+	defer store.Close()
+	for _, address := range addresses {
+		if account, err := store.GetAccountByAddress(address); err != nil || account.Mnemonic == "" {
+			t.Errorf("GetAccountByAddress(%s) after merging = %v, %v, want the account with its mnemonic", address, account, err)
+		}
+	}
+
+	after, err := os.ReadFile(srcPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("merge changed the source database")
+	}
+}
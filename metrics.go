@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"sei-account-generator/pkg/wallet"
+)
+
+// metricsPath is the HTTP path Prometheus metrics are served on
+const metricsPath = "/metrics"
+
+// serveMetrics registers the store metrics and serves them over HTTP on addr
+// in the background for the rest of the process, along with the health
+// endpoints answered by health. The listener is opened before returning so a
+// busy or invalid address is reported right away.
+func serveMetrics(addr string, health *serviceHealth) (*wallet.Metrics, error) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	metrics, err := wallet.NewMetrics(reg)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	mux.HandleFunc(healthzPath, health.handleHealthz)
+	mux.HandleFunc(readyzPath, health.handleReadyz)
+
+	slog.Info("serving metrics", "addr", listener.Addr().String(), "path", metricsPath)
+	go func() {
+		if err := http.Serve(listener, mux); err != nil {
+			slog.Error("metrics listener stopped", "error", err)
+		}
+	}()
+
+	return metrics, nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"golang.org/x/term"
+
+	"sei-account-generator/pkg/wallet"
+)
+
+const (
+	// DBPasswordEnvVar is the environment variable holding the database encryption key
+	DBPasswordEnvVar = "SEI_DB_PASSWORD"
+	// DBNewPasswordEnvVar is the environment variable holding the replacement key for rekey
+	DBNewPasswordEnvVar = "SEI_DB_NEW_PASSWORD"
+	// ExportPassphraseEnvVar is the environment variable holding the passphrase for encrypted exports
+	ExportPassphraseEnvVar = "SEI_EXPORT_PASSPHRASE"
+	// SecretsPassphraseEnvVar is the environment variable holding the passphrase
+	// that encrypts mnemonics and private keys inside the database
+	SecretsPassphraseEnvVar = "SEI_SECRETS_PASSPHRASE"
+)
+
+// resolveDBPassword determines the encryption key for the database at dbPath. The key is taken
+// from envVar (normally SEI_DB_PASSWORD) when set; otherwise the user is prompted on the terminal
+// without echo, confirming the password when the database does not exist yet.
+// When stdin is not a terminal, DefaultDBPassword is used with a warning.
+func resolveDBPassword(envVar, dbPath string) (string, error) {
+	if password := os.Getenv(envVar); password != "" {
+		return password, nil
+	}
+
+	if !stdinIsTerminal() {
+		slog.Info("database password not provided and stdin is not a terminal, falling back to the default password", "env", envVar)
+		return wallet.DefaultDBPassword, nil
+	}
+
+	// A new database gets its key on first open, so ask twice to catch typos
+	_, err := os.Stat(dbPath)
+	isNew := os.IsNotExist(err)
+
+	password, err := promptPassword("Enter database password: ")
+	if err != nil {
+		return "", err
+	}
+	if password == "" {
+		return "", errors.New("password must not be empty")
+	}
+
+	if isNew {
+		confirm, err := promptPassword("Confirm database password: ")
+		if err != nil {
+			return "", err
+		}
+		if confirm != password {
+			return "", errors.New("passwords do not match")
+		}
+	}
+
+	return password, nil
+}
+
+// resolveSecretsPassphrase returns the passphrase for the stored secrets from
+// SEI_SECRETS_PASSPHRASE, or "" when it is unset. It is never prompted for:
+// unlike the database password it is optional, so a missing one cannot be told
+// from a database that does not use it.
+func resolveSecretsPassphrase() string {
+	return os.Getenv(SecretsPassphraseEnvVar)
+}
+
+// resolveNewDBPassword determines the replacement key for a rekey, taken from
+// SEI_DB_NEW_PASSWORD when set or otherwise prompted for twice on the terminal
+func resolveNewDBPassword() (string, error) {
+	return resolveConfirmedSecret(DBNewPasswordEnvVar, "new database password")
+}
+
+// resolveExportPassphrase determines the passphrase for an encrypted export,
+// taken from SEI_EXPORT_PASSPHRASE when set or otherwise prompted for twice
+func resolveExportPassphrase() (string, error) {
+	return resolveConfirmedSecret(ExportPassphraseEnvVar, "export passphrase")
+}
+
+// resolveConfirmedSecret reads a new secret from envVar, or prompts for it twice
+// on the terminal so a typo cannot lock the user out
+func resolveConfirmedSecret(envVar, name string) (string, error) {
+	if secret := os.Getenv(envVar); secret != "" {
+		return secret, nil
+	}
+
+	if !stdinIsTerminal() {
+		return "", fmt.Errorf("%s is not set and stdin is not a terminal", envVar)
+	}
+
+	secret, err := promptPassword("Enter " + name + ": ")
+	if err != nil {
+		return "", err
+	}
+	if secret == "" {
+		return "", fmt.Errorf("%s must not be empty", name)
+	}
+
+	confirm, err := promptPassword("Confirm " + name + ": ")
+	if err != nil {
+		return "", err
+	}
+	if confirm != secret {
+		return "", fmt.Errorf("%ss do not match", name)
+	}
+
+	return secret, nil
+}
+
+// passwordPrompted reports whether resolveDBPassword asks on the terminal
+// instead of reading envVar, so a wrong password can be retried
+func passwordPrompted(envVar string) bool {
+	return os.Getenv(envVar) == "" && stdinIsTerminal()
+}
+
+// stdinIsTerminal reports whether stdin is an interactive terminal
+func stdinIsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// promptPassword reads a line from the terminal without echoing it
+func promptPassword(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	password, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return string(password), nil
+}
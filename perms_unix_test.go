@@ -0,0 +1,50 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sei-account-generator/pkg/wallet"
+)
+
+// permissiveFile creates a world-readable file in a temporary directory
+func permissiveFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "out")
+	if err := os.WriteFile(path, []byte("old contents\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+// checkMode fails the test unless the file at path has mode
+func checkMode(t *testing.T, path string, mode os.FileMode) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if got := info.Mode().Perm(); got != mode {
+		t.Errorf("mode of %s = %#o, want %#o", path, got, mode)
+	}
+}
+
+func TestExportNDJSONToResetsMode(t *testing.T) {
+	store := openTestStore(t, t.TempDir())
+	path := permissiveFile(t)
+	if err := exportNDJSONTo(store, path, false, true, 0o600); err != nil {
+		t.Fatalf("exportNDJSONTo() error = %v", err)
+	}
+	checkMode(t, path, 0o600)
+}
+
+func TestGenerateToFileResetsMode(t *testing.T) {
+	path := permissiveFile(t)
+	if err := generateToFile(2, path, wallet.AlgoSecp256k1, wallet.DefaultCoinType, wallet.DefaultMnemonicWords, true, 0o600); err != nil {
+		t.Fatalf("generateToFile() error = %v", err)
+	}
+	checkMode(t, path, 0o600)
+}
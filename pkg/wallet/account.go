@@ -0,0 +1,176 @@
+// Package wallet generates and derives Sei (and other Cosmos) accounts and keeps
+// them in an encrypted SQLCipher database. The sei-account-generator CLI is a
+// thin layer over this package.
+package wallet
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+// Account structure is unchanged, just renamed fields to be more consistent
+type Account struct {
+	Mnemonic   string    `json:"mnemonic"`
+	Address    string    `json:"address"`
+	PubKey     string    `json:"public_key"`
+	PrivateKey string    `json:"private_key"`
+	Label      string    `json:"label,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	// DerivationPath is the BIP44 path the key was derived at from Mnemonic,
+	// such as m/44'/118'/0'/0/0. It is empty for accounts imported from a raw
+	// private key and for stored accounts whose path is unknown.
+	DerivationPath string `json:"derivation_path,omitempty"`
+	// Successor is the address of the account that replaced this one through
+	// RotateAccount. Only archived accounts have one.
+	Successor string `json:"successor,omitempty"`
+	// KeyAlgorithm is the signature algorithm of the key pair. Empty means
+	// DefaultKeyAlgorithm; accounts read from a store always have it set.
+	KeyAlgorithm KeyAlgorithm `json:"key_algorithm,omitempty"`
+}
+
+// redactedChars is how many characters of a secret String shows at each end
+const redactedChars = 4
+
+// String describes the account with its mnemonic and private key shortened to
+// their first and last few characters, so an account formatted with %v or
+// logged by accident does not leak its secrets. Use StringUnsafe to show them.
+func (a Account) String() string {
+	return a.format(Redact)
+}
+
+// GoString redacts secrets like String, so %#v is also safe to log
+func (a Account) GoString() string {
+	return a.String()
+}
+
+// StringUnsafe describes the account like String but includes the full
+// mnemonic and private key
+func (a Account) StringUnsafe() string {
+	return a.format(func(secret string) string { return secret })
+}
+
+// format describes the account, passing the mnemonic and private key through secret
+func (a Account) format(secret func(string) string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Account{Address: %s", a.Address)
+	if a.Label != "" {
+		fmt.Fprintf(&b, ", Label: %q", a.Label)
+	}
+	if algo := a.algorithm(); algo != DefaultKeyAlgorithm {
+		fmt.Fprintf(&b, ", KeyAlgorithm: %s", algo)
+	}
+	fmt.Fprintf(&b, ", PubKey: %s, Mnemonic: %s, PrivateKey: %s", a.PubKey, secret(a.Mnemonic), secret(a.PrivateKey))
+	if !a.CreatedAt.IsZero() {
+		fmt.Fprintf(&b, ", CreatedAt: %s", a.CreatedAt.Format(time.RFC3339))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// Redact shortens secret the way String does, keeping its first and last few
+// characters. Secrets too short to shorten meaningfully are hidden entirely.
+func Redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	if len(secret) <= 4*redactedChars {
+		return "[redacted]"
+	}
+	return secret[:redactedChars] + "..." + secret[len(secret)-redactedChars:]
+}
+
+// Verify re-derives the public key and address from the account's private key
+// and confirms they match the stored PubKey and Address
+func (a *Account) Verify() error {
+	privKey, err := a.privKey()
+	if err != nil {
+		return err
+	}
+
+	pubKey := privKey.PubKey()
+
+	if pubKeyHex := hex.EncodeToString(pubKey.Bytes()); pubKeyHex != a.PubKey {
+		return fmt.Errorf("public key mismatch: stored %s, derived %s", a.PubKey, pubKeyHex)
+	}
+	if addr := sdk.AccAddress(pubKey.Address()).String(); addr != a.Address {
+		return fmt.Errorf("address mismatch: stored %s, derived %s", a.Address, addr)
+	}
+
+	return nil
+}
+
+// Sign signs msg with the account's private key. For secp256k1 and secp256r1
+// the message is hashed with SHA-256 and the signature is returned in the
+// 64-byte r||s form used by Cosmos; ed25519 signs msg itself, also in 64 bytes.
+func (a *Account) Sign(msg []byte) ([]byte, error) {
+	privKey, err := a.privKey()
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := privKey.Sign(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+	return sig, nil
+}
+
+// VerifySignature reports whether sig is a valid signature of msg by the account's public key
+func (a *Account) VerifySignature(msg, sig []byte) bool {
+	pubKeyBytes, err := hex.DecodeString(a.PubKey)
+	if err != nil {
+		return false
+	}
+
+	pubKey, err := a.algorithm().decodePubKey(pubKeyBytes)
+	if err != nil {
+		return false
+	}
+	return pubKey.VerifySignature(msg, sig)
+}
+
+// privKey decodes the account's hex private key for its key algorithm
+func (a *Account) privKey() (cryptotypes.PrivKey, error) {
+	keyBytes, err := hex.DecodeString(a.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("private key is not valid hex: %w", err)
+	}
+	return a.algorithm().decodePrivKey(keyBytes)
+}
+
+// algorithm returns the account's key algorithm, defaulting an empty one
+func (a *Account) algorithm() KeyAlgorithm {
+	if a.KeyAlgorithm == "" {
+		return DefaultKeyAlgorithm
+	}
+	return a.KeyAlgorithm
+}
+
+// ValidateSeiAddress checks that addr is a well-formed bech32 account address
+// with the configured account prefix (sei by default)
+func ValidateSeiAddress(addr string) error {
+	if addr == "" {
+		return fmt.Errorf("address must not be empty")
+	}
+
+	hrp, bz, err := bech32.DecodeAndConvert(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+
+	if prefix := sdk.GetConfig().GetBech32AccountAddrPrefix(); hrp != prefix {
+		return fmt.Errorf("invalid address %q: expected prefix %q, got %q", addr, prefix, hrp)
+	}
+
+	if err := sdk.VerifyAddressFormat(bz); err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+
+	return nil
+}
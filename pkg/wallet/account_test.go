@@ -0,0 +1,64 @@
+package wallet
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSeiAddress(t *testing.T) {
+	account := newTestAccount(t)
+	cosmos := withPrefix(t, account.Address, "cosmos")
+
+	// Changing the last character breaks the bech32 checksum
+	last := "q"
+	if strings.HasSuffix(account.Address, last) {
+		last = "p"
+	}
+	badChecksum := account.Address[:len(account.Address)-1] + last
+
+	tests := []struct {
+		name    string
+		addr    string
+		wantErr bool
+	}{
+		{"valid", account.Address, false},
+		{"cosmos prefix", cosmos, true},
+		{"bad checksum", badChecksum, true},
+		{"empty", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateSeiAddress(tt.addr); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSeiAddress(%q) error = %v, wantErr %v", tt.addr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSignVerifySignature(t *testing.T) {
+	for _, name := range KeyAlgorithms() {
+		t.Run(name, func(t *testing.T) {
+			account, err := GenerateAccountWithAlgorithm(KeyAlgorithm(name), DefaultCoinType, DefaultMnemonicWords, "")
+			if err != nil {
+				t.Fatalf("GenerateAccountWithAlgorithm() error = %v", err)
+			}
+
+			msg := []byte("transfer 10usei to sei1recipient")
+			sig, err := account.Sign(msg)
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			if !account.VerifySignature(msg, sig) {
+				t.Error("VerifySignature() of the signed message = false, want true")
+			}
+
+			tampered := []byte("transfer 99usei to sei1recipient")
+			if account.VerifySignature(tampered, sig) {
+				t.Error("VerifySignature() of a tampered message = true, want false")
+			}
+			if other := newTestAccount(t); other.VerifySignature(msg, sig) {
+				t.Error("VerifySignature() by another account = true, want false")
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package wallet
+
+import (
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+	"strings"
+
+	sdked25519 "github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256r1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+)
+
+// KeyAlgorithm names the signature algorithm of an account's key pair. The
+// values match the key type names used by the Cosmos SDK.
+type KeyAlgorithm string
+
+// Supported key algorithms
+const (
+	// AlgoSecp256k1 is the standard Cosmos account key, and the only one
+	// hardware wallets and most chains accept
+	AlgoSecp256k1 KeyAlgorithm = "secp256k1"
+	// AlgoSecp256r1 is NIST P-256, usable on chains that register the SDK's
+	// secp256r1 public key type
+	AlgoSecp256r1 KeyAlgorithm = "secp256r1"
+	// AlgoEd25519 keys are accepted for accounts by few chains; the SDK's
+	// default ante handler rejects them
+	AlgoEd25519 KeyAlgorithm = "ed25519"
+)
+
+// DefaultKeyAlgorithm is the algorithm of generated accounts unless another
+// one is requested, and of stored accounts that predate recording it
+const DefaultKeyAlgorithm = AlgoSecp256k1
+
+// keyAlgorithms lists the supported algorithms in the order they are documented
+var keyAlgorithms = []KeyAlgorithm{AlgoSecp256k1, AlgoSecp256r1, AlgoEd25519}
+
+// KeyAlgorithms returns the names of the supported key algorithms
+func KeyAlgorithms() []string {
+	names := make([]string, len(keyAlgorithms))
+	for i, algo := range keyAlgorithms {
+		names[i] = string(algo)
+	}
+	return names
+}
+
+// ParseKeyAlgorithm returns the key algorithm with the given name, ignoring case
+func ParseKeyAlgorithm(name string) (KeyAlgorithm, error) {
+	for _, algo := range keyAlgorithms {
+		if strings.EqualFold(name, string(algo)) {
+			return algo, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported key algorithm %q (supported: %s)", name, strings.Join(KeyAlgorithms(), ", "))
+}
+
+// validate checks that algo is one of the supported algorithms
+func (algo KeyAlgorithm) validate() error {
+	_, err := ParseKeyAlgorithm(string(algo))
+	return err
+}
+
+// checkMnemonicKeys rejects deriving algo keys from a mnemonic. BIP32 is only
+// defined over secp256k1, and the SLIP-0010 derivation other wallets use for
+// secp256r1 and ed25519 is not implemented, so a mnemonic would not recover
+// such an account anywhere else. Those accounts get a random key instead.
+func (algo KeyAlgorithm) checkMnemonicKeys() error {
+	if algo != AlgoSecp256k1 {
+		return fmt.Errorf("%s keys cannot be derived from a mnemonic; only %s keys can", algo, AlgoSecp256k1)
+	}
+	return nil
+}
+
+// generatePrivKey returns a new random private key for algo
+func (algo KeyAlgorithm) generatePrivKey() (cryptotypes.PrivKey, error) {
+	switch algo {
+	case AlgoSecp256k1:
+		return secp256k1.GenPrivKey(), nil
+	case AlgoSecp256r1:
+		privKey, err := secp256r1.GenPrivKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate secp256r1 key: %w", err)
+		}
+		return privKey, nil
+	case AlgoEd25519:
+		return sdked25519.GenPrivKey(), nil
+	default:
+		return nil, algo.validate()
+	}
+}
+
+// privKeyFromSecret turns the 32-byte key derived at a BIP44 path into a
+// private key for algo. New accounts only derive secp256k1 keys (see
+// checkMnemonicKeys); the other algorithms remain so that accounts stored
+// before, whose secp256k1 secret was reused as the P-256 scalar or the ed25519
+// seed, can still be found by their mnemonic.
+func (algo KeyAlgorithm) privKeyFromSecret(secret []byte) (cryptotypes.PrivKey, error) {
+	switch algo {
+	case AlgoSecp256k1:
+		return &secp256k1.PrivKey{Key: secret}, nil
+	case AlgoSecp256r1:
+		return secp256r1PrivKey(secret)
+	case AlgoEd25519:
+		// The secret is used as the RFC 8032 seed
+		return &sdked25519.PrivKey{Key: ed25519.NewKeyFromSeed(secret)}, nil
+	default:
+		return nil, algo.validate()
+	}
+}
+
+// decodePrivKey parses a private key in the form Account.PrivateKey stores it
+func (algo KeyAlgorithm) decodePrivKey(keyBytes []byte) (cryptotypes.PrivKey, error) {
+	switch algo {
+	case AlgoSecp256k1:
+		if len(keyBytes) != secp256k1.PrivKeySize {
+			return nil, fmt.Errorf("private key has %d bytes, expected %d", len(keyBytes), secp256k1.PrivKeySize)
+		}
+		return &secp256k1.PrivKey{Key: keyBytes}, nil
+	case AlgoSecp256r1:
+		return secp256r1PrivKey(keyBytes)
+	case AlgoEd25519:
+		if len(keyBytes) != sdked25519.PrivKeySize {
+			return nil, fmt.Errorf("private key has %d bytes, expected %d", len(keyBytes), sdked25519.PrivKeySize)
+		}
+		return &sdked25519.PrivKey{Key: keyBytes}, nil
+	default:
+		return nil, algo.validate()
+	}
+}
+
+// decodePubKey parses a public key in the form Account.PubKey stores it
+func (algo KeyAlgorithm) decodePubKey(keyBytes []byte) (cryptotypes.PubKey, error) {
+	switch algo {
+	case AlgoSecp256k1:
+		if len(keyBytes) != secp256k1.PubKeySize {
+			return nil, fmt.Errorf("public key has %d bytes, expected %d", len(keyBytes), secp256k1.PubKeySize)
+		}
+		return &secp256k1.PubKey{Key: keyBytes}, nil
+	case AlgoSecp256r1:
+		if len(keyBytes) != secp256r1PubKeySize {
+			return nil, fmt.Errorf("public key has %d bytes, expected %d", len(keyBytes), secp256r1PubKeySize)
+		}
+		pubKey := &secp256r1.PubKey{}
+		if err := pubKey.Unmarshal(protoBytesField(keyBytes)); err != nil {
+			return nil, fmt.Errorf("invalid secp256r1 public key: %w", err)
+		}
+		return pubKey, nil
+	case AlgoEd25519:
+		if len(keyBytes) != sdked25519.PubKeySize {
+			return nil, fmt.Errorf("public key has %d bytes, expected %d", len(keyBytes), sdked25519.PubKeySize)
+		}
+		return &sdked25519.PubKey{Key: keyBytes}, nil
+	default:
+		return nil, algo.validate()
+	}
+}
+
+// Sizes in bytes of a P-256 private scalar and compressed public key
+const (
+	secp256r1KeySize    = 32
+	secp256r1PubKeySize = 33
+)
+
+// secp256r1PrivKey builds a P-256 private key from its big-endian scalar,
+// which must be non-zero and below the curve order
+func secp256r1PrivKey(scalar []byte) (*secp256r1.PrivKey, error) {
+	if len(scalar) != secp256r1KeySize {
+		return nil, fmt.Errorf("private key has %d bytes, expected %d", len(scalar), secp256r1KeySize)
+	}
+	if d := new(big.Int).SetBytes(scalar); d.Sign() == 0 || d.Cmp(elliptic.P256().Params().N) >= 0 {
+		return nil, fmt.Errorf("private key is not a valid secp256r1 scalar")
+	}
+
+	privKey := &secp256r1.PrivKey{}
+	if err := privKey.Unmarshal(protoBytesField(scalar)); err != nil {
+		return nil, fmt.Errorf("invalid secp256r1 private key: %w", err)
+	}
+	return privKey, nil
+}
+
+// protoBytesField encodes value as field 1 of a protobuf message. The SDK's
+// secp256r1 key types keep their fields in an internal package, so they can
+// only be built by unmarshaling their protobuf form.
+func protoBytesField(value []byte) []byte {
+	return append([]byte{0x0a, byte(len(value))}, value...)
+}
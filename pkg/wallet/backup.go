@@ -0,0 +1,210 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// backupTimeLayout is the UTC timestamp in rotated backup file names. It sorts
+// in time order and is parsed back when pruning. The nanoseconds keep backups
+// taken within the same second apart.
+const backupTimeLayout = "20060102T150405.000000000Z"
+
+// legacyBackupTimeLayout is the whole-second timestamp of backups written by
+// earlier versions, which are still pruned
+const legacyBackupTimeLayout = "20060102T150405Z"
+
+// BackupTo writes a consistent, encrypted copy of the database to destPath
+// while the store stays open. The copy uses the same password and SQLCipher
+// settings as the store. destPath must not exist yet.
+func (s *AccountStore) BackupTo(destPath string) error {
+	return s.BackupToContext(context.Background(), destPath)
+}
+
+// BackupToContext is like BackupTo but honors cancellation and deadlines from ctx
+func (s *AccountStore) BackupToContext(ctx context.Context, destPath string) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db == nil {
+		return fmt.Errorf("database connection not established")
+	}
+
+	if _, err := os.Stat(destPath); err == nil {
+		return fmt.Errorf("backup destination %s already exists", destPath)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to check backup destination: %w", err)
+	}
+	if _, err := s.ensureDir(filepath.Dir(destPath)); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	// ATTACH is per connection, so keep the whole export on one
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	s.logger.Debug("backing up database", "path", s.dbPath, "destination", destPath)
+
+	// destPath did not exist, so whatever is there after a failure is a partial backup
+	defer func() {
+		if err != nil {
+			removeBackupFiles(destPath)
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS backup KEY ?", destPath, s.password); err != nil {
+		return fmt.Errorf("failed to create backup database: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "DETACH DATABASE backup")
+
+	// Attached databases start from SQLCipher's defaults, not the main database's settings.
+	// PRAGMA does not accept bound parameters; the values are validated ints.
+	// cipher_compatibility resets the other settings, so it goes first.
+	var settings []string
+	if s.config.CipherCompatibility != 0 {
+		settings = append(settings, fmt.Sprintf("PRAGMA backup.cipher_compatibility = %d", s.config.CipherCompatibility))
+	}
+	settings = append(settings, fmt.Sprintf("PRAGMA backup.cipher_page_size = %d", s.config.CipherPageSize))
+	if s.config.KDFIterations != 0 {
+		settings = append(settings, fmt.Sprintf("PRAGMA backup.kdf_iter = %d", s.config.KDFIterations))
+	}
+	for _, pragma := range settings {
+		if _, err := conn.ExecContext(ctx, pragma); err != nil {
+			return fmt.Errorf("failed to configure backup database: %w", err)
+		}
+	}
+
+	// sqlcipher_export copies the schema, data and user_version in one read transaction
+	if _, err := conn.ExecContext(ctx, "SELECT sqlcipher_export('backup')"); err != nil {
+		return fmt.Errorf("failed to export database: %w", err)
+	}
+
+	// The journal mode is not part of the export; match the WAL mode new stores use
+	if _, err := conn.ExecContext(ctx, "PRAGMA backup.journal_mode = WAL"); err != nil {
+		return fmt.Errorf("failed to set backup journal mode: %w", err)
+	}
+
+	if err := chmod(destPath, s.config.fileMode()); err != nil {
+		return fmt.Errorf("failed to set backup permissions: %w", err)
+	}
+
+	return nil
+}
+
+// BackupRotate writes a new backup into dir, named after the database file and
+// the current time, for example sei_accounts_20240102T150405.123456789Z.db, and then
+// deletes the oldest backups of this database in dir so that keep remain.
+// Only files matching that name pattern are considered. The backup just
+// written is never deleted: a keep below 1 is treated as 1. dir must not be
+// the database's own directory, where the backups would look like profiles.
+func (s *AccountStore) BackupRotate(dir string, keep int) error {
+	return s.BackupRotateContext(context.Background(), dir, keep)
+}
+
+// BackupRotateContext is like BackupRotate but honors cancellation and deadlines from ctx
+func (s *AccountStore) BackupRotateContext(ctx context.Context, dir string, keep int) error {
+	if keep < 1 {
+		s.logger.Warn("backup rotation must keep at least one backup, keeping only the newest", "keep", keep)
+		keep = 1
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve backup directory: %w", err)
+	}
+	absDBDir, err := filepath.Abs(filepath.Dir(s.dbPath))
+	if err != nil {
+		return fmt.Errorf("failed to resolve database directory: %w", err)
+	}
+	if absDir == absDBDir {
+		return fmt.Errorf("backup directory %s is the database directory; use a separate directory", dir)
+	}
+
+	prefix := backupFilePrefix(s.dbPath)
+	latest := filepath.Join(dir, prefix+time.Now().UTC().Format(backupTimeLayout)+profileFileSuffix)
+	if err := s.BackupToContext(ctx, latest); err != nil {
+		return err
+	}
+
+	if err := s.pruneBackups(dir, prefix, filepath.Base(latest), keep); err != nil {
+		return fmt.Errorf("backed up database to %s, but %w", latest, err)
+	}
+	return nil
+}
+
+// backupFilePrefix returns the start of the rotated backup names of the
+// database at dbPath: its file name without the extension, followed by '_'
+func backupFilePrefix(dbPath string) string {
+	name := filepath.Base(dbPath)
+	return strings.TrimSuffix(name, filepath.Ext(name)) + "_"
+}
+
+// pruneBackups deletes all but the newest keep rotated backups in dir. latest,
+// the backup just written, always counts as one of them, so a clock that was
+// set ahead when an older backup was taken cannot get it deleted.
+func (s *AccountStore) pruneBackups(dir, prefix, latest string, keep int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	type backup struct {
+		name  string
+		taken time.Time
+	}
+	var older []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() || name == latest {
+			continue
+		}
+		stamp, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		stamp, ok = strings.CutSuffix(stamp, profileFileSuffix)
+		if !ok {
+			continue
+		}
+		taken, err := time.Parse(backupTimeLayout, stamp)
+		if err != nil {
+			if taken, err = time.Parse(legacyBackupTimeLayout, stamp); err != nil {
+				continue
+			}
+		}
+		older = append(older, backup{name: name, taken: taken})
+	}
+	if len(older) < keep {
+		return nil
+	}
+
+	sort.Slice(older, func(i, j int) bool { return older[i].taken.After(older[j].taken) })
+	for _, b := range older[keep-1:] {
+		path := filepath.Join(dir, b.name)
+		if err := removeBackupFiles(path); err != nil {
+			return fmt.Errorf("failed to remove old backup: %w", err)
+		}
+		s.logger.Debug("removed old backup", "path", path)
+	}
+	return nil
+}
+
+// removeBackupFiles deletes the backup at path along with its WAL and shared
+// memory files. Files that do not exist are ignored.
+func removeBackupFiles(path string) error {
+	for _, file := range []string{path, path + "-wal", path + "-shm"} {
+		if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
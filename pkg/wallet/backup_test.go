@@ -0,0 +1,40 @@
+package wallet
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackupRotateWithinOneSecond(t *testing.T) {
+	store := newTestStore(t)
+	if _, err := store.SaveAccount(newTestAccount(t)); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	legacy := filepath.Join(dir, "sei_accounts_20200102T150405Z.db")
+	if err := os.WriteFile(legacy, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Runs this close together share a whole-second timestamp
+	for i := 0; i < 3; i++ {
+		if err := store.BackupRotate(dir, 2); err != nil {
+			t.Fatalf("BackupRotate() run %d error = %v", i+1, err)
+		}
+	}
+
+	backups, err := filepath.Glob(filepath.Join(dir, "sei_accounts_*.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("backups after rotation = %v, want 2", backups)
+	}
+	for _, path := range backups {
+		if path == legacy {
+			t.Errorf("legacy backup %s was kept over newer ones", path)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultNodeURL is the public Sei LCD/REST endpoint used for balance queries
+	DefaultNodeURL = "https://rest.sei-apis.com"
+	// BaseDenom is the smallest unit of SEI (1 SEI = 1,000,000 usei)
+	BaseDenom = "usei"
+)
+
+// Coin is an amount of a single denomination as returned by the bank module
+type Coin struct {
+	Denom  string `json:"denom"`
+	Amount string `json:"amount"`
+}
+
+// balancesResponse is the body of /cosmos/bank/v1beta1/balances/{address}
+type balancesResponse struct {
+	Balances []Coin `json:"balances"`
+}
+
+// BalanceQuerier is implemented by BalanceClient (REST) and GRPCBalanceClient
+type BalanceQuerier interface {
+	// BalancesContext returns all balances held by address
+	BalancesContext(ctx context.Context, address string) ([]Coin, error)
+	// BalanceContext returns the amount of denom held by address, or "0"
+	BalanceContext(ctx context.Context, address, denom string) (string, error)
+}
+
+// BalanceClient queries account balances from a Cosmos SDK REST endpoint
+type BalanceClient struct {
+	nodeURL    string
+	httpClient *http.Client
+}
+
+// NewBalanceClient creates a client for the given LCD/REST endpoint
+func NewBalanceClient(nodeURL string) *BalanceClient {
+	return &BalanceClient{
+		nodeURL:    strings.TrimRight(nodeURL, "/"),
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Balances returns all balances held by address
+func (c *BalanceClient) Balances(address string) ([]Coin, error) {
+	return c.BalancesContext(context.Background(), address)
+}
+
+// BalancesContext is like Balances but aborts the request when ctx is cancelled
+func (c *BalanceClient) BalancesContext(ctx context.Context, address string) ([]Coin, error) {
+	endpoint := fmt.Sprintf("%s/cosmos/bank/v1beta1/balances/%s", c.nodeURL, url.PathEscape(address))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build balance request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query balances: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read balances response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("balance query returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var parsed balancesResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode balances response: %w", err)
+	}
+
+	return parsed.Balances, nil
+}
+
+// Balance returns the amount of denom held by address, or "0" if it holds none
+func (c *BalanceClient) Balance(address, denom string) (string, error) {
+	return c.BalanceContext(context.Background(), address, denom)
+}
+
+// BalanceContext is like Balance but aborts the request when ctx is cancelled
+func (c *BalanceClient) BalanceContext(ctx context.Context, address, denom string) (string, error) {
+	coins, err := c.BalancesContext(ctx, address)
+	if err != nil {
+		return "", err
+	}
+	for _, coin := range coins {
+		if coin.Denom == denom {
+			return coin.Amount, nil
+		}
+	}
+	return "0", nil
+}
@@ -0,0 +1,67 @@
+package wallet
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// GenerationBenchmark reports how fast accounts were generated by BenchmarkGeneration
+type GenerationBenchmark struct {
+	Accounts int
+	// Workers is the number of goroutines that generated accounts in parallel
+	Workers int
+	// Elapsed is the wall-clock time for the whole batch
+	Elapsed time.Duration
+	// Min, Max and Avg describe the time to generate a single account,
+	// including its mnemonic, seed and key derivation
+	Min time.Duration
+	Max time.Duration
+	Avg time.Duration
+}
+
+// AccountsPerSecond returns the batch throughput
+func (b *GenerationBenchmark) AccountsPerSecond() float64 {
+	if b.Elapsed <= 0 {
+		return 0
+	}
+	return float64(b.Accounts) / b.Elapsed.Seconds()
+}
+
+// BenchmarkGeneration generates n accounts in memory with the same parallel
+// workers as GenerateAccounts and times them. Nothing is stored and the
+// generated keys are discarded.
+func BenchmarkGeneration(n int, coinType uint32, words int) (*GenerationBenchmark, error) {
+	if _, err := EntropyForWords(words); err != nil {
+		return nil, err
+	}
+
+	var (
+		mu    sync.Mutex
+		total time.Duration
+	)
+	result := &GenerationBenchmark{Accounts: n, Workers: parallelWorkers(n)}
+
+	start := time.Now()
+	_, err := generateParallel(n, func() (*Account, error) {
+		began := time.Now()
+		account, err := GenerateAccount(coinType, words, "")
+		took := time.Since(began)
+
+		mu.Lock()
+		defer mu.Unlock()
+		total += took
+		if result.Min == 0 || took < result.Min {
+			result.Min = took
+		}
+		result.Max = max(result.Max, took)
+		return account, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate accounts: %w", err)
+	}
+	result.Elapsed = time.Since(start)
+	result.Avg = total / time.Duration(n)
+
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package wallet
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// DefaultChain is the chain used when -chain is not given
+const DefaultChain = "sei"
+
+// ChainConfig describes the address encoding and key derivation of a Cosmos chain
+type ChainConfig struct {
+	Name          string
+	AccountPrefix string
+	CoinType      uint32
+}
+
+// knownChains maps -chain names to their configuration
+var knownChains = map[string]ChainConfig{
+	"sei":     {Name: "sei", AccountPrefix: "sei", CoinType: DefaultCoinType},
+	"cosmos":  {Name: "cosmos", AccountPrefix: "cosmos", CoinType: 118},
+	"osmosis": {Name: "osmosis", AccountPrefix: "osmo", CoinType: 118},
+}
+
+var (
+	chainMu         sync.Mutex
+	configuredChain string
+)
+
+func init() {
+	// Use Sei prefixes until ConfigureChain selects and seals a chain
+	setBech32Prefixes(knownChains[DefaultChain].AccountPrefix)
+}
+
+// LookupChain returns the configuration for a chain name such as "sei" or "osmosis"
+func LookupChain(name string) (ChainConfig, error) {
+	chain, ok := knownChains[strings.ToLower(name)]
+	if !ok {
+		return ChainConfig{}, fmt.Errorf("unknown chain %q (supported: %s)", name, strings.Join(ChainNames(), ", "))
+	}
+	return chain, nil
+}
+
+// ChainNames returns the sorted names of all supported chains
+func ChainNames() []string {
+	names := make([]string, 0, len(knownChains))
+	for name := range knownChains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ConfigureChain sets the bech32 prefixes derived from accountPrefix and seals
+// the SDK config. The SDK config can only be sealed once, so calling it again
+// with the same prefix is a no-op and a different prefix is an error.
+func ConfigureChain(accountPrefix string) error {
+	chainMu.Lock()
+	defer chainMu.Unlock()
+
+	if configuredChain != "" {
+		if configuredChain != accountPrefix {
+			return fmt.Errorf("chain already configured with prefix %q, cannot switch to %q", configuredChain, accountPrefix)
+		}
+		return nil
+	}
+
+	setBech32Prefixes(accountPrefix)
+	sdk.GetConfig().Seal()
+	configuredChain = accountPrefix
+
+	return nil
+}
+
+// setBech32Prefixes applies the standard Cosmos prefix scheme for accountPrefix
+func setBech32Prefixes(accountPrefix string) {
+	config := sdk.GetConfig()
+	config.SetBech32PrefixForAccount(accountPrefix, accountPrefix+"pub")
+	config.SetBech32PrefixForValidator(accountPrefix+"valoper", accountPrefix+"valoperpub")
+	config.SetBech32PrefixForConsensusNode(accountPrefix+"valcons", accountPrefix+"valconspub")
+}
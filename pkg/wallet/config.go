@@ -0,0 +1,255 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	sqlite3 "github.com/mutecomm/go-sqlcipher/v4"
+)
+
+const (
+	// DefaultCipherPageSize is the SQLCipher page size used for new databases
+	DefaultCipherPageSize = 4096
+	// DefaultMaxOpenConns caps the connection pool size used when
+	// StoreConfig.MaxOpenConns is zero
+	DefaultMaxOpenConns = 4
+
+	// currentCipherCompatibility is the major version of the bundled SQLCipher,
+	// whose defaults apply when StoreConfig.CipherCompatibility is zero
+	currentCipherCompatibility = 4
+)
+
+// StoreConfig holds the SQLCipher settings used to open the database. A database
+// must be reopened with the same settings it was created with.
+type StoreConfig struct {
+	// Profile selects which database in the directory to open, see
+	// ProfileFileName. Empty selects DefaultProfile.
+	Profile string
+	// CipherPageSize is the encrypted page size in bytes (PRAGMA cipher_page_size)
+	CipherPageSize int
+	// KDFIterations is the PBKDF2 iteration count used to derive the encryption
+	// key from the password (PRAGMA kdf_iter). Zero keeps SQLCipher's default.
+	KDFIterations int
+	// CipherCompatibility opens the database with the defaults of an earlier
+	// SQLCipher major version (PRAGMA cipher_compatibility), for databases
+	// created by other tools. Valid values are 1, 2, 3 and 4; zero uses the
+	// current SQLCipher 4 defaults. Versions 1 to 3 used 1024-byte pages, so
+	// set CipherPageSize to 1024 as well unless the database was created with
+	// a custom page size. KDFIterations, when set, overrides the version's
+	// iteration count.
+	CipherCompatibility int
+	// Logger receives debug output about database operations and warnings.
+	// Nil uses slog.Default().
+	Logger *slog.Logger
+	// RefuseDefaultPassword makes opening fail with ErrDefaultPassword instead
+	// of only logging a warning when the store would use DefaultDBPassword
+	RefuseDefaultPassword bool
+
+	// MaxOpenConns limits the number of pooled database connections. Reads run
+	// concurrently on separate connections while writes stay serialized. Zero
+	// selects one connection per CPU, up to DefaultMaxOpenConns.
+	MaxOpenConns int
+	// MaxIdleConns limits the number of idle connections kept open. Every new
+	// connection repeats the key derivation, so zero keeps all MaxOpenConns
+	// connections idle rather than closing them.
+	MaxIdleConns int
+	// ConnMaxLifetime is how long a connection may be reused. Zero reuses
+	// connections forever.
+	ConnMaxLifetime time.Duration
+
+	// Metrics receives counts and latencies of store operations. Nil disables
+	// instrumentation.
+	Metrics *Metrics
+
+	// FileMode is the permission mode of a new database and of exported files.
+	// Zero selects DefaultFileMode. Group access (for example 0640) is allowed,
+	// access for other users is not.
+	FileMode os.FileMode
+	// DirMode is the permission mode of directories the store creates. Zero
+	// selects DefaultDirMode.
+	DirMode os.FileMode
+
+	// SecretsPassphrase, when set, additionally encrypts each account's mnemonic
+	// and private key inside the database with a key derived from it, see
+	// secrets.go. Bulk reads such as GetAccounts then return accounts without
+	// their secrets; only GetAccountByAddress and the exporters decrypt them.
+	// A store without encrypted secrets is encrypted by its first write with
+	// the passphrase, which also seals the accounts saved before; opening it
+	// only to read changes nothing. Every later open must pass the same passphrase.
+	SecretsPassphrase string
+
+	// AllowForeignPrefix lets SaveAccount and SaveAccounts store accounts whose
+	// address prefix differs from the configured chain's (see ConfigureChain).
+	// By default such accounts are rejected with ErrForeignPrefix, which
+	// catches a cosmos1 account being saved into a sei store.
+	AllowForeignPrefix bool
+
+	// AllowSharedMnemonics lets SaveAccount and SaveAccounts store an account
+	// whose mnemonic is already stored under another address, such as the
+	// same phrase with a different BIP39 passphrase. By default such accounts
+	// are not saved, and SaveAccount and SaveAccounts report them with a
+	// SharedMnemonicError; HD siblings, the further address indices of one
+	// wallet, are saved either way (see mnemonic_hash.go).
+	AllowSharedMnemonics bool
+
+	// ReadOnly opens an existing database without changing it: the file is
+	// opened with SQLite's mode=ro, its schema is not migrated and writes
+	// fail. Its schema must already be at LatestSchemaVersion; an older or a
+	// newer one is refused with ErrSchemaVersion. A SecretsPassphrase only
+	// unlocks secrets already encrypted with it and never seals any.
+	ReadOnly bool
+}
+
+// DefaultStoreConfig returns the settings used by NewAccountStore
+func DefaultStoreConfig() StoreConfig {
+	return StoreConfig{CipherPageSize: DefaultCipherPageSize}
+}
+
+// validate checks the settings against the limits SQLCipher accepts
+func (c StoreConfig) validate() error {
+	if err := ValidateProfileName(c.Profile); err != nil {
+		return err
+	}
+	if c.CipherPageSize < 512 || c.CipherPageSize > 65536 || c.CipherPageSize&(c.CipherPageSize-1) != 0 {
+		return fmt.Errorf("invalid cipher page size %d: must be a power of two between 512 and 65536", c.CipherPageSize)
+	}
+	if c.KDFIterations < 0 {
+		return fmt.Errorf("invalid KDF iteration count %d: must not be negative", c.KDFIterations)
+	}
+	if c.CipherCompatibility < 0 || c.CipherCompatibility > currentCipherCompatibility {
+		return fmt.Errorf("invalid cipher compatibility %d: must be 1, 2, 3 or 4 (0 for the default)", c.CipherCompatibility)
+	}
+	if err := validateModes(c.fileMode(), c.dirMode()); err != nil {
+		return err
+	}
+	if c.MaxOpenConns < 0 || c.MaxIdleConns < 0 || c.ConnMaxLifetime < 0 {
+		return fmt.Errorf("invalid connection pool settings: limits must not be negative")
+	}
+	return nil
+}
+
+// configurePool applies the connection pool limits to db
+func (c StoreConfig) configurePool(db *sql.DB) {
+	// Readers beyond the CPU count only add contention on SQLite's shared locks
+	maxOpen := c.MaxOpenConns
+	if maxOpen == 0 {
+		maxOpen = min(runtime.NumCPU(), DefaultMaxOpenConns)
+	}
+	maxIdle := c.MaxIdleConns
+	if maxIdle == 0 {
+		maxIdle = maxOpen
+	}
+
+	db.SetMaxOpenConns(maxOpen)
+	db.SetMaxIdleConns(maxIdle)
+	db.SetConnMaxLifetime(c.ConnMaxLifetime)
+}
+
+// kdfMu guards SQLCipher's process-wide default KDF iteration count and
+// compatibility version. Connection opens that change them hold it exclusively;
+// all others hold it shared, so they never see another store's values.
+var kdfMu sync.RWMutex
+
+// connector opens SQLCipher connections for a DSN with a custom KDF iteration
+// count or compatibility version. The driver runs statements that read the
+// database before any connect hook, so neither can be set per connection with
+// PRAGMA kdf_iter or cipher_compatibility. They are applied through PRAGMA
+// cipher_default_compatibility and cipher_default_kdf_iter for the duration
+// of the open instead.
+type connector struct {
+	dsn           string
+	kdfIter       int
+	compatibility int
+	driver        *sqlite3.SQLiteDriver
+}
+
+// newConnector returns a connector for dsn configured from c
+func (c StoreConfig) newConnector(dsn string) *connector {
+	return &connector{dsn: dsn, kdfIter: c.KDFIterations, compatibility: c.CipherCompatibility, driver: &sqlite3.SQLiteDriver{}}
+}
+
+// Connect implements driver.Connector
+func (c *connector) Connect(context.Context) (driver.Conn, error) {
+	if c.kdfIter == 0 && c.compatibility == 0 {
+		kdfMu.RLock()
+		defer kdfMu.RUnlock()
+		return c.driver.Open(c.dsn)
+	}
+
+	kdfMu.Lock()
+	defer kdfMu.Unlock()
+
+	// The default is global, so change it through a scratch in-memory connection
+	// and restore the previous value once the real connection has been keyed
+	scratch, err := c.driver.Open(":memory:")
+	if err != nil {
+		return nil, err
+	}
+	defer scratch.Close()
+	conn := scratch.(*sqlite3.SQLiteConn)
+
+	previous, err := defaultKDFIter(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Selecting a compatibility version resets every default, including the
+	// KDF iteration count, so it is applied first and undone first. Nothing
+	// else changes the defaults, so undoing it means selecting the current version.
+	defer func() {
+		if c.compatibility != 0 {
+			conn.Exec(fmt.Sprintf("PRAGMA cipher_default_compatibility = %d", currentCipherCompatibility), nil)
+		}
+		conn.Exec(fmt.Sprintf("PRAGMA cipher_default_kdf_iter = %d", previous), nil)
+	}()
+	if c.compatibility != 0 {
+		if _, err := conn.Exec(fmt.Sprintf("PRAGMA cipher_default_compatibility = %d", c.compatibility), nil); err != nil {
+			return nil, fmt.Errorf("failed to set cipher compatibility: %w", err)
+		}
+	}
+	if c.kdfIter != 0 {
+		if _, err := conn.Exec(fmt.Sprintf("PRAGMA cipher_default_kdf_iter = %d", c.kdfIter), nil); err != nil {
+			return nil, fmt.Errorf("failed to set KDF iterations: %w", err)
+		}
+	}
+
+	return c.driver.Open(c.dsn)
+}
+
+// Driver implements driver.Connector
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// defaultKDFIter reads SQLCipher's current default KDF iteration count
+func defaultKDFIter(conn *sqlite3.SQLiteConn) (int64, error) {
+	rows, err := conn.Query("PRAGMA cipher_default_kdf_iter", nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read default KDF iterations: %w", err)
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, 1)
+	if err := rows.Next(values); err != nil {
+		return 0, fmt.Errorf("failed to read default KDF iterations: %w", err)
+	}
+
+	switch v := values[0].(type) {
+	case int64:
+		return v, nil
+	case []byte:
+		return strconv.ParseInt(string(v), 10, 64)
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	default:
+		return 0, fmt.Errorf("unexpected default KDF iterations value %v", v)
+	}
+}
@@ -0,0 +1,153 @@
+package wallet
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestCustomCipherSettingsRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	config := testConfig()
+	config.KDFIterations = 10000
+	config.CipherPageSize = 8192
+
+	store, err := NewAccountStoreWithConfig(dir, testPassword, config)
+	if err != nil {
+		t.Fatalf("NewAccountStoreWithConfig() error = %v", err)
+	}
+	accounts := []*Account{newTestAccount(t), newTestAccount(t)}
+	if inserted, err := store.SaveAccounts(accounts); err != nil || inserted != 2 {
+		t.Fatalf("SaveAccounts() = %d, %v, want 2", inserted, err)
+	}
+	store.Close()
+
+	// The file can only be read back with the settings it was created with
+	if other, err := NewAccountStoreWithConfig(dir, testPassword, testConfig()); err == nil {
+		other.Close()
+		t.Error("NewAccountStoreWithConfig() with the default cipher settings succeeded")
+	}
+
+	store = openTestStore(t, dir, config)
+	for _, account := range accounts {
+		got, err := store.GetAccountByAddress(account.Address)
+		if err != nil {
+			t.Fatalf("GetAccountByAddress() error = %v", err)
+		}
+		if got.Mnemonic != account.Mnemonic || got.PrivateKey != account.PrivateKey {
+			t.Errorf("account %s did not round-trip", account.Address)
+		}
+	}
+}
+
+func TestConcurrentOpensKeepTheirCipherSettings(t *testing.T) {
+	custom := testConfig()
+	custom.KDFIterations = 100000
+
+	const stores = 6
+	defaultDirs := make([]string, stores)
+	customDirs := make([]string, stores)
+	for i := range defaultDirs {
+		defaultDirs[i], customDirs[i] = t.TempDir(), t.TempDir()
+	}
+
+	// Create databases with default and custom settings at the same time. A
+	// default one keyed while a custom open had changed the process-wide
+	// defaults could not be opened with the default settings afterwards.
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*stores)
+	for i := 0; i < stores; i++ {
+		wg.Add(2)
+		go func(dir string) {
+			defer wg.Done()
+			store, err := NewAccountStoreWithConfig(dir, testPassword, testConfig())
+			if err == nil {
+				err = store.Close()
+			}
+			errs <- err
+		}(defaultDirs[i])
+		go func(dir string) {
+			defer wg.Done()
+			store, err := NewAccountStoreWithConfig(dir, testPassword, custom)
+			if err == nil {
+				err = store.Close()
+			}
+			errs <- err
+		}(customDirs[i])
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("NewAccountStoreWithConfig() error = %v", err)
+		}
+	}
+
+	for _, dir := range defaultDirs {
+		openTestStore(t, dir, testConfig())
+	}
+	for _, dir := range customDirs {
+		openTestStore(t, dir, custom)
+	}
+}
+
+func TestCipherCompatibility(t *testing.T) {
+	// SQLCipher 3 defaults: 1024-byte pages, 64000 iterations and SHA1 HMACs
+	dir := t.TempDir()
+	config := testConfig()
+	config.CipherCompatibility = 3
+	config.CipherPageSize = 1024
+	legacy := openTestStore(t, dir, config)
+	account := newTestAccount(t)
+	if _, err := legacy.SaveAccount(account); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+	backupPath := filepath.Join(t.TempDir(), "backup.db")
+	if err := legacy.BackupTo(backupPath); err != nil {
+		t.Fatalf("BackupTo() error = %v", err)
+	}
+	legacy.Close()
+
+	if other, err := NewAccountStoreWithConfig(dir, testPassword, testConfig()); err == nil {
+		other.Close()
+		t.Error("NewAccountStoreWithConfig() of a SQLCipher 3 database with the default settings succeeded")
+	}
+
+	// The compatibility default is global, so it must be back to 4 for the next default store
+	currentDir := t.TempDir()
+	current := openTestStore(t, currentDir, testConfig())
+	if _, err := current.SaveAccount(newTestAccount(t)); err != nil {
+		t.Fatalf("SaveAccount() into a default store error = %v", err)
+	}
+	current.Close()
+	if other, err := NewAccountStoreWithConfig(currentDir, testPassword, config); err == nil {
+		other.Close()
+		t.Error("NewAccountStoreWithConfig() of a default database with compatibility 3 succeeded")
+	}
+
+	for _, path := range []string{filepath.Join(dir, DBFileName), backupPath} {
+		store, err := OpenAccountStoreFile(path, testPassword, config)
+		if err != nil {
+			t.Fatalf("OpenAccountStoreFile(%s) with compatibility 3 error = %v", path, err)
+		}
+		got, err := store.GetAccountByAddress(account.Address)
+		store.Close()
+		if err != nil {
+			t.Fatalf("GetAccountByAddress() from %s error = %v", path, err)
+		}
+		if got.PrivateKey != account.PrivateKey {
+			t.Errorf("account in %s did not round-trip", path)
+		}
+	}
+}
+
+func TestCipherCompatibilityValidation(t *testing.T) {
+	for _, compatibility := range []int{-1, 5} {
+		config := testConfig()
+		config.CipherCompatibility = compatibility
+		if store, err := NewAccountStoreWithConfig(t.TempDir(), testPassword, config); err == nil {
+			store.Close()
+			t.Errorf("NewAccountStoreWithConfig() with compatibility %d succeeded", compatibility)
+		}
+	}
+}
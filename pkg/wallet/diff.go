@@ -0,0 +1,47 @@
+package wallet
+
+import "sort"
+
+// AddressDiff is the result of DiffAddresses. Each list is sorted.
+type AddressDiff struct {
+	// OnlyA holds the addresses found only in the first set
+	OnlyA []string `json:"only_a"`
+	// OnlyB holds the addresses found only in the second set
+	OnlyB []string `json:"only_b"`
+	// Both holds the addresses found in both sets
+	Both []string `json:"both"`
+}
+
+// DiffAddresses compares two address sets, for example from GetAddresses on
+// two stores, to reconcile them before a merge. Duplicates within a set are
+// counted once.
+func DiffAddresses(a, b []string) AddressDiff {
+	inB := make(map[string]bool, len(b))
+	for _, address := range b {
+		inB[address] = true
+	}
+
+	diff := AddressDiff{OnlyA: []string{}, OnlyB: []string{}, Both: []string{}}
+	seen := make(map[string]bool, len(a))
+	for _, address := range a {
+		if seen[address] {
+			continue
+		}
+		seen[address] = true
+		if inB[address] {
+			diff.Both = append(diff.Both, address)
+		} else {
+			diff.OnlyA = append(diff.OnlyA, address)
+		}
+	}
+	for address := range inB {
+		if !seen[address] {
+			diff.OnlyB = append(diff.OnlyB, address)
+		}
+	}
+
+	sort.Strings(diff.OnlyA)
+	sort.Strings(diff.OnlyB)
+	sort.Strings(diff.Both)
+	return diff
+}
@@ -0,0 +1,47 @@
+package wallet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffAddresses(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want AddressDiff
+	}{
+		{
+			name: "empty",
+			want: AddressDiff{OnlyA: []string{}, OnlyB: []string{}, Both: []string{}},
+		},
+		{
+			name: "only in A",
+			a:    []string{"sei1c", "sei1a"},
+			want: AddressDiff{OnlyA: []string{"sei1a", "sei1c"}, OnlyB: []string{}, Both: []string{}},
+		},
+		{
+			name: "only in B",
+			b:    []string{"sei1d", "sei1b"},
+			want: AddressDiff{OnlyA: []string{}, OnlyB: []string{"sei1b", "sei1d"}, Both: []string{}},
+		},
+		{
+			name: "common",
+			a:    []string{"sei1b", "sei1a"},
+			b:    []string{"sei1a", "sei1b"},
+			want: AddressDiff{OnlyA: []string{}, OnlyB: []string{}, Both: []string{"sei1a", "sei1b"}},
+		},
+		{
+			name: "mixed with duplicates",
+			a:    []string{"sei1a", "sei1c", "sei1a", "sei1e"},
+			b:    []string{"sei1e", "sei1b", "sei1c", "sei1b"},
+			want: AddressDiff{OnlyA: []string{"sei1a"}, OnlyB: []string{"sei1b"}, Both: []string{"sei1c", "sei1e"}},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := DiffAddresses(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: DiffAddresses() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
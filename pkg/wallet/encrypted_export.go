@@ -0,0 +1,191 @@
+package wallet
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Parameters for the encrypted export envelope. The scrypt cost parameters are
+// recorded in every envelope so they can be raised later without breaking old files.
+const (
+	encryptedExportVersion = 1
+	encryptedExportKDF     = "scrypt"
+	encryptedExportCipher  = "aes-256-gcm"
+	scryptN                = 1 << 15
+	scryptR                = 8
+	scryptP                = 1
+	scryptKeyLen           = 32
+	scryptSaltLen          = 16
+)
+
+// ErrDecryptionFailed is returned when an encrypted export cannot be opened,
+// which usually means the passphrase is wrong
+var ErrDecryptionFailed = errors.New("failed to decrypt export: wrong passphrase or corrupted file")
+
+// encryptedEnvelope is the self-describing on-disk format of an encrypted export
+type encryptedEnvelope struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Cipher     string `json:"cipher"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// ExportAccountsEncrypted exports all accounts to a JSON envelope encrypted with
+// AES-256-GCM under a key derived from passphrase with scrypt
+func (s *AccountStore) ExportAccountsEncrypted(filePath, passphrase string) error {
+	if passphrase == "" {
+		return fmt.Errorf("export passphrase must not be empty")
+	}
+
+	accounts, err := s.getAccounts(context.Background(), true)
+	if err != nil {
+		return fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	plaintext, err := json.Marshal(accounts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal accounts to JSON: %w", err)
+	}
+
+	envelope, err := encryptPayload(plaintext, passphrase)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal encrypted export: %w", err)
+	}
+
+	if err := s.writeFile(filePath, data); err != nil {
+		return fmt.Errorf("failed to write encrypted export to file: %w", err)
+	}
+
+	return nil
+}
+
+// ImportAccountsEncrypted decrypts an export written by ExportAccountsEncrypted
+// and saves its accounts, skipping addresses that are already stored. It
+// returns the number of accounts inserted, and like SaveAccounts a
+// SharedMnemonicError for accounts left out because their mnemonic is stored.
+func (s *AccountStore) ImportAccountsEncrypted(filePath, passphrase string) (int, error) {
+	accounts, err := readEncryptedExport(filePath, passphrase)
+	if err != nil {
+		return 0, err
+	}
+	return s.SaveAccounts(accounts)
+}
+
+// readEncryptedExport decrypts the accounts stored in an encrypted export file
+func readEncryptedExport(filePath, passphrase string) ([]*Account, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encrypted export: %w", err)
+	}
+
+	var envelope encryptedEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse encrypted export: %w", err)
+	}
+
+	plaintext, err := decryptPayload(&envelope, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []*Account
+	if err := json.Unmarshal(plaintext, &accounts); err != nil {
+		return nil, fmt.Errorf("failed to decode decrypted accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// encryptPayload seals plaintext into a new envelope with a fresh salt and nonce
+func encryptPayload(plaintext []byte, passphrase string) (*encryptedEnvelope, error) {
+	salt := make([]byte, scryptSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	envelope := &encryptedEnvelope{
+		Version: encryptedExportVersion,
+		KDF:     encryptedExportKDF,
+		N:       scryptN,
+		R:       scryptR,
+		P:       scryptP,
+		Cipher:  encryptedExportCipher,
+		Salt:    salt,
+	}
+
+	gcm, err := envelope.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(envelope.Nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	envelope.Ciphertext = gcm.Seal(nil, envelope.Nonce, plaintext, nil)
+
+	return envelope, nil
+}
+
+// decryptPayload opens an envelope, returning ErrDecryptionFailed on authentication failure
+func decryptPayload(envelope *encryptedEnvelope, passphrase string) ([]byte, error) {
+	if envelope.Version != encryptedExportVersion {
+		return nil, fmt.Errorf("unsupported encrypted export version %d", envelope.Version)
+	}
+	if envelope.KDF != encryptedExportKDF || envelope.Cipher != encryptedExportCipher {
+		return nil, fmt.Errorf("unsupported encrypted export scheme %s/%s", envelope.KDF, envelope.Cipher)
+	}
+
+	gcm, err := envelope.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if len(envelope.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce length %d", len(envelope.Nonce))
+	}
+
+	plaintext, err := gcm.Open(nil, envelope.Nonce, envelope.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+
+	return plaintext, nil
+}
+
+// aead derives the envelope key from passphrase and returns the AES-GCM cipher
+func (e *encryptedEnvelope) aead(passphrase string) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), e.Salt, e.N, e.R, e.P, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive encryption key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	return gcm, nil
+}
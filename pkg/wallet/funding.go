@@ -0,0 +1,60 @@
+package wallet
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FundingAddressPlaceholder is replaced by each account's address in the
+// command template passed to ExportFundingScript
+const FundingAddressPlaceholder = "{address}"
+
+// DefaultFundingTemplate sends 1 SEI from the key named in $FROM to each address
+const DefaultFundingTemplate = "seid tx bank send $FROM " + FundingAddressPlaceholder + " 1000000usei"
+
+// ExportFundingScript writes a shell script to filePath that runs faucetCmdTemplate
+// once per stored account, with FundingAddressPlaceholder replaced by the
+// account's address, for example to fund a batch of testnet accounts. The
+// template is trusted and copied verbatim; each address is validated and
+// single-quoted before substitution. The script stops at the first failing
+// command and is made executable by its owner.
+func (s *AccountStore) ExportFundingScript(filePath, faucetCmdTemplate string) error {
+	if !strings.Contains(faucetCmdTemplate, FundingAddressPlaceholder) {
+		return fmt.Errorf("funding command template must contain %s", FundingAddressPlaceholder)
+	}
+
+	accounts, err := s.GetAccounts()
+	if err != nil {
+		return fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&b, "# Funds %d accounts\n", len(accounts))
+	b.WriteString("set -e\n\n")
+	for _, account := range accounts {
+		// Stored addresses are bech32 and never need quoting, but a script must not trust the database
+		if err := ValidateSeiAddress(account.Address); err != nil {
+			return fmt.Errorf("refusing to write funding command for %q: %w", account.Address, err)
+		}
+		b.WriteString(strings.ReplaceAll(faucetCmdTemplate, FundingAddressPlaceholder, shellQuote(account.Address)))
+		b.WriteString("\n")
+	}
+
+	mode := s.config.fileMode() | 0100
+	if err := os.WriteFile(filePath, []byte(b.String()), mode); err != nil {
+		return fmt.Errorf("failed to write funding script: %w", err)
+	}
+	if err := chmod(filePath, mode); err != nil {
+		return fmt.Errorf("failed to set funding script permissions: %w", err)
+	}
+
+	return nil
+}
+
+// shellQuote quotes value for a POSIX shell, so it is passed as a single word
+// with no expansion
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,69 @@
+package wallet
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"sei1abc", `'sei1abc'`},
+		{"", `''`},
+		{"it's", `'it'\''s'`},
+		{"$(rm -rf ~) `id` *", `'$(rm -rf ~) ` + "`id`" + ` *'`},
+	}
+	for _, tt := range tests {
+		if got := shellQuote(tt.value); got != tt.want {
+			t.Errorf("shellQuote(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestExportFundingScript(t *testing.T) {
+	store := newTestStore(t)
+	accounts := []*Account{newTestAccount(t), newTestAccount(t)}
+	if _, err := store.SaveAccounts(accounts); err != nil {
+		t.Fatalf("SaveAccounts() error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "fund.sh")
+	if err := store.ExportFundingScript(path, "fund "+FundingAddressPlaceholder+" 1usei"); err != nil {
+		t.Fatalf("ExportFundingScript() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	script := string(data)
+	if !strings.HasPrefix(script, "#!/bin/sh\n") || !strings.Contains(script, "\nset -e\n") {
+		t.Errorf("script lacks the shebang or set -e:\n%s", script)
+	}
+	for _, account := range accounts {
+		if want := "fund '" + account.Address + "' 1usei\n"; !strings.Contains(script, want) {
+			t.Errorf("script lacks %q:\n%s", want, script)
+		}
+	}
+
+	if unixPermissions {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Stat() error = %v", err)
+		}
+		if mode := info.Mode().Perm(); mode != 0o700 {
+			t.Errorf("script mode = %#o, want 0700", mode)
+		}
+	}
+}
+
+func TestExportFundingScriptRequiresPlaceholder(t *testing.T) {
+	store := newTestStore(t)
+	if err := store.ExportFundingScript(filepath.Join(t.TempDir(), "fund.sh"), "fund everyone"); err == nil {
+		t.Error("ExportFundingScript() without the address placeholder succeeded")
+	}
+}
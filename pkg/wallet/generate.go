@@ -0,0 +1,393 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/go-bip39"
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// Default generation parameters
+const (
+	DefaultMnemonicWords = 24
+	// DefaultCoinType is the SLIP-44 coin type used in Sei derivation paths.
+	// Cosmos coin type is 118, Sei uses the same standard
+	DefaultCoinType uint32 = 118
+	// DefaultDerivationPath is the BIP44 HD path for the first Sei account
+	DefaultDerivationPath = "m/44'/118'/0'/0/0"
+)
+
+// GenerateAccount creates a new account with a mnemonic of the given word count,
+// deriving the first address at m/44'/{coinType}'/0'/0/0. A non-empty passphrase
+// is used as the BIP39 "25th word" when computing the seed.
+func GenerateAccount(coinType uint32, words int, passphrase string) (*Account, error) {
+	return GenerateAccountAtPath(DerivationPath(coinType, 0), words, passphrase)
+}
+
+// GenerateAccountWithAlgorithm is like GenerateAccount but creates a key pair
+// for algo instead of secp256k1. Only secp256k1 keys are derived from a
+// mnemonic. Other algorithms get a random key and, like accounts from
+// ImportFromPrivateKey, no mnemonic or derivation path, so words is ignored
+// and passphrase must be empty.
+func GenerateAccountWithAlgorithm(algo KeyAlgorithm, coinType uint32, words int, passphrase string) (*Account, error) {
+	if err := algo.validate(); err != nil {
+		return nil, err
+	}
+	if algo.checkMnemonicKeys() == nil {
+		return generateAccountAtPath(algo, DerivationPath(coinType, 0), words, passphrase)
+	}
+	if passphrase != "" {
+		return nil, fmt.Errorf("%s accounts have no mnemonic to use a BIP39 passphrase with", algo)
+	}
+
+	privKey, err := algo.generatePrivKey()
+	if err != nil {
+		return nil, err
+	}
+	return accountFromPrivKey("", privKey), nil
+}
+
+// GenerateAccounts creates n accounts in parallel using one worker per CPU.
+// The order of the returned accounts is not deterministic. The first error
+// reported by any worker stops the remaining work and is returned.
+func GenerateAccounts(n int, coinType uint32, words int, passphrase string) ([]*Account, error) {
+	return generateParallel(n, func() (*Account, error) {
+		return GenerateAccount(coinType, words, passphrase)
+	})
+}
+
+// GenerateAccountsWithAlgorithm is like GenerateAccounts but creates key pairs
+// for algo, as GenerateAccountWithAlgorithm does
+func GenerateAccountsWithAlgorithm(algo KeyAlgorithm, n int, coinType uint32, words int, passphrase string) ([]*Account, error) {
+	if err := algo.validate(); err != nil {
+		return nil, err
+	}
+	return generateParallel(n, func() (*Account, error) {
+		return GenerateAccountWithAlgorithm(algo, coinType, words, passphrase)
+	})
+}
+
+// generateParallel calls generate n times across parallelWorkers(n) goroutines
+// and collects the accounts, stopping at the first error
+func generateParallel(n int, generate func() (*Account, error)) ([]*Account, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("account count must be positive, got %d", n)
+	}
+
+	workers := parallelWorkers(n)
+
+	jobs := make(chan struct{})
+	results := make(chan *Account, n)
+	errs := make(chan error, workers)
+	done := make(chan struct{})
+	var stop sync.Once
+
+	// Feed one job per account until all are queued or a worker fails
+	go func() {
+		defer close(jobs)
+		for i := 0; i < n; i++ {
+			select {
+			case jobs <- struct{}{}:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range jobs {
+				account, err := generate()
+				if err != nil {
+					errs <- err
+					stop.Do(func() { close(done) })
+					return
+				}
+				results <- account
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(results)
+	close(errs)
+
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+
+	accounts := make([]*Account, 0, n)
+	for account := range results {
+		accounts = append(accounts, account)
+	}
+
+	return accounts, nil
+}
+
+// parallelWorkers returns how many goroutines generate n accounts: one per
+// CPU, but never more than there are accounts
+func parallelWorkers(n int) int {
+	return min(runtime.NumCPU(), n)
+}
+
+// GenerateAccountAtPath creates a new account with mnemonic, deriving the key at the given BIP44 path
+func GenerateAccountAtPath(path string, words int, passphrase string) (*Account, error) {
+	return generateAccountAtPath(DefaultKeyAlgorithm, path, words, passphrase)
+}
+
+// generateAccountAtPath creates a new account with mnemonic and an algo key pair
+// derived at path
+func generateAccountAtPath(algo KeyAlgorithm, path string, words int, passphrase string) (*Account, error) {
+	// Validate the inputs before spending time on entropy and seed generation
+	if err := ValidateDerivationPath(path); err != nil {
+		return nil, err
+	}
+	entropySizeInBits, err := EntropyForWords(words)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate a random mnemonic
+	entropy, err := bip39.NewEntropy(entropySizeInBits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate entropy: %w", err)
+	}
+
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate mnemonic: %w", err)
+	}
+
+	return deriveFromMnemonic(algo, mnemonic, passphrase, path)
+}
+
+// EntropyForWords returns the entropy size in bits for a BIP39 mnemonic with the given word count.
+// Every 3 words encode 32 bits of entropy plus 1 checksum bit.
+func EntropyForWords(words int) (int, error) {
+	switch words {
+	case 12, 15, 18, 21, 24:
+		return words / 3 * 32, nil
+	default:
+		return 0, fmt.Errorf("invalid mnemonic word count %d: must be one of 12, 15, 18, 21 or 24", words)
+	}
+}
+
+// ImportAccount recovers an account from an existing mnemonic and optional BIP39
+// passphrase, deriving the first address for the given coin type. The mnemonic
+// is put through NormalizeMnemonic first, and the account keeps the normalized form.
+func ImportAccount(mnemonic, passphrase string, coinType uint32) (*Account, error) {
+	return ImportAccountWithAlgorithm(mnemonic, passphrase, coinType, DefaultKeyAlgorithm)
+}
+
+// ImportAccountWithAlgorithm is like ImportAccount for an account whose key
+// pair was created for algo. Only secp256k1 keys can be imported from a
+// mnemonic, see GenerateAccountWithAlgorithm.
+func ImportAccountWithAlgorithm(mnemonic, passphrase string, coinType uint32, algo KeyAlgorithm) (*Account, error) {
+	if err := algo.validate(); err != nil {
+		return nil, err
+	}
+	if err := algo.checkMnemonicKeys(); err != nil {
+		return nil, err
+	}
+	mnemonic = NormalizeMnemonic(mnemonic)
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+
+	return deriveFromMnemonic(algo, mnemonic, passphrase, DerivationPath(coinType, 0))
+}
+
+// ImportAccountsWithAlgorithm is like ImportAccountWithAlgorithm but derives
+// count accounts at address indices start, start+1, ... of the standard path,
+// for extending a wallet whose first addresses are already in use elsewhere.
+// start must not be negative.
+func ImportAccountsWithAlgorithm(mnemonic, passphrase string, coinType uint32, algo KeyAlgorithm, start, count int) ([]*Account, error) {
+	if err := algo.validate(); err != nil {
+		return nil, err
+	}
+	if err := algo.checkMnemonicKeys(); err != nil {
+		return nil, err
+	}
+	mnemonic = NormalizeMnemonic(mnemonic)
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+
+	return derivePathRange(algo, mnemonic, passphrase, start, count, func(index uint32) string {
+		return DerivationPath(coinType, index)
+	})
+}
+
+// ImportFromPrivateKey builds an account from a raw hex-encoded secp256k1 private
+// key, such as one exported from another tool. An optional 0x prefix is accepted.
+// Such accounts have no mnemonic, so they cannot be recovered from a seed phrase
+// and the private key itself must be backed up.
+func ImportFromPrivateKey(hexKey string) (*Account, error) {
+	hexKey = strings.TrimPrefix(strings.TrimSpace(hexKey), "0x")
+	keyBytes, err := hex.DecodeString(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: not valid hex: %w", err)
+	}
+	if len(keyBytes) != secp256k1.PrivKeySize {
+		return nil, fmt.Errorf("invalid private key: got %d bytes, expected %d", len(keyBytes), secp256k1.PrivKeySize)
+	}
+
+	// A valid key is a non-zero scalar below the curve order
+	var scalar secp.ModNScalar
+	if overflow := scalar.SetByteSlice(keyBytes); overflow || scalar.IsZero() {
+		return nil, fmt.Errorf("invalid private key: not a valid secp256k1 scalar")
+	}
+
+	return accountFromPrivKey("", &secp256k1.PrivKey{Key: keyBytes}), nil
+}
+
+// ValidateMnemonic checks the mnemonic's word count, that every word is in the
+// BIP39 wordlist and its checksum, in that order. Words are separated by any
+// whitespace. Misspelled words are reported by position rather than echoed.
+func ValidateMnemonic(mnemonic string) error {
+	words := strings.Fields(mnemonic)
+	if _, err := EntropyForWords(len(words)); err != nil {
+		return fmt.Errorf("invalid mnemonic: expected 12, 15, 18, 21 or 24 words, got %d", len(words))
+	}
+	for i, word := range words {
+		if _, ok := bip39.ReverseWordMap[word]; !ok {
+			return fmt.Errorf("invalid mnemonic: word %d is not in the BIP39 wordlist", i+1)
+		}
+	}
+	if _, err := bip39.MnemonicToByteArray(strings.Join(words, " ")); err != nil {
+		return fmt.Errorf("invalid mnemonic: checksum verification failed: %w", err)
+	}
+	return nil
+}
+
+// DeriveAccountsFromMnemonic derives count accounts from a single mnemonic,
+// walking the address index of the standard path (m/44'/{coinType}'/0'/0/i).
+// The mnemonic is normalized like in ImportAccount.
+func DeriveAccountsFromMnemonic(mnemonic string, coinType uint32, count int) ([]*Account, error) {
+	return DeriveAccountsFromMnemonicAt(mnemonic, coinType, 0, count)
+}
+
+// DeriveAccountsFromMnemonicAt is like DeriveAccountsFromMnemonic but starts
+// at address index start, which must not be negative
+func DeriveAccountsFromMnemonicAt(mnemonic string, coinType uint32, start, count int) ([]*Account, error) {
+	return ImportAccountsWithAlgorithm(mnemonic, "", coinType, DefaultKeyAlgorithm, start, count)
+}
+
+// maxAddressIndex is the largest non-hardened BIP32 index. Larger indices
+// would wrap into the hardened range when converted to uint32.
+const maxAddressIndex = 1<<31 - 1
+
+// derivePathRange derives count algo accounts at the paths pathFor returns for
+// start, start+1, ... from a mnemonic that has already been validated
+func derivePathRange(algo KeyAlgorithm, mnemonic, passphrase string, start, count int, pathFor func(index uint32) string) ([]*Account, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("account count must be positive, got %d", count)
+	}
+	if start < 0 {
+		return nil, fmt.Errorf("start index must not be negative, got %d", start)
+	}
+	if count > maxAddressIndex+1-start {
+		return nil, fmt.Errorf("address indices %d to %d exceed the largest non-hardened index %d", start, start+count-1, maxAddressIndex)
+	}
+
+	// Compute the seed once and reuse it for every index
+	seed := bip39.NewSeed(mnemonic, passphrase)
+	master, ch := hd.ComputeMastersFromSeed(seed)
+
+	accounts := make([]*Account, 0, count)
+	for i := start; i < start+count; i++ {
+		account, err := deriveAccount(algo, mnemonic, master, ch, pathFor(uint32(i)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive account at index %d: %w", i, err)
+		}
+		accounts = append(accounts, account)
+	}
+
+	return accounts, nil
+}
+
+// DerivationPath returns the standard BIP44 path for the given coin type and address index
+func DerivationPath(coinType, index uint32) string {
+	return DerivationPathForChain(coinType, false, index)
+}
+
+// DerivationPathForChain is like DerivationPath but selects the BIP44 chain:
+// with change set it returns m/44'/{coinType}'/0'/1/{index} on the change
+// chain instead of m/44'/{coinType}'/0'/0/{index} on the receive chain. Change
+// addresses of a seed never coincide with its receive addresses.
+func DerivationPathForChain(coinType uint32, change bool, index uint32) string {
+	return hd.NewParams(44, coinType, 0, change, index).String()
+}
+
+// ValidateDerivationPath checks that path is a well-formed BIP44 path such as m/44'/118'/0'/0/0
+func ValidateDerivationPath(path string) error {
+	if _, err := hd.NewParamsFromPath(path); err != nil {
+		return fmt.Errorf("invalid derivation path %q: %w", path, err)
+	}
+	return nil
+}
+
+// deriveFromMnemonic derives the algo account at path from a mnemonic that has
+// already been validated. It is deterministic: the same inputs always give the
+// same keys, which derivationVectors relies on. The same mnemonic with a different
+// passphrase yields an entirely different seed and therefore different keys.
+func deriveFromMnemonic(algo KeyAlgorithm, mnemonic, passphrase, path string) (*Account, error) {
+	seed := bip39.NewSeed(mnemonic, passphrase)
+	master, ch := hd.ComputeMastersFromSeed(seed)
+
+	return deriveAccount(algo, mnemonic, master, ch, path)
+}
+
+// deriveAccount derives the algo account at path from an already computed master key and chain code
+func deriveAccount(algo KeyAlgorithm, mnemonic string, master, ch [32]byte, path string) (*Account, error) {
+	// Get private key from derivation path
+	derivedPrivateKey, err := hd.DerivePrivateKeyForPath(master, ch, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive private key: %w", err)
+	}
+
+	// Create private key object
+	privKey, err := algo.privKeyFromSecret(derivedPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive private key: %w", err)
+	}
+
+	account := accountFromPrivKey(mnemonic, privKey)
+	account.DerivationPath = path
+	return account, nil
+}
+
+// accountFromPrivKey builds an account from a private key and its mnemonic, if
+// any. The key's SDK type name is recorded as the account's KeyAlgorithm.
+func accountFromPrivKey(mnemonic string, privKey cryptotypes.PrivKey) *Account {
+	// Get public key
+	pubKey := privKey.PubKey()
+
+	// Get address from public key
+	addr := sdk.AccAddress(pubKey.Address())
+
+	// Format the public key
+	pubKeyHex := hex.EncodeToString(pubKey.Bytes())
+
+	return &Account{
+		Mnemonic:     mnemonic,
+		Address:      addr.String(),
+		PubKey:       pubKeyHex,
+		PrivateKey:   hex.EncodeToString(privKey.Bytes()),
+		KeyAlgorithm: KeyAlgorithm(privKey.Type()),
+		// The database stores timestamps with second precision
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+}
@@ -0,0 +1,223 @@
+package wallet
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+func TestVerifyDerivation(t *testing.T) {
+	if err := VerifyDerivation(); err != nil {
+		t.Fatalf("VerifyDerivation() error = %v", err)
+	}
+}
+
+func TestVerifyDerivationCatchesMismatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func()
+	}{
+		{name: "seed", tamper: func() { seedVectors[0].passphrase = "" }},
+		{name: "address", tamper: func() {
+			derivationVectors[0].address, _ = bech32.ConvertAndEncode("cosmos", make([]byte, 20))
+		}},
+		{name: "public key", tamper: func() { derivationVectors[0].pubKey = strings.Repeat("0", 66) }},
+		{name: "private key", tamper: func() { derivationVectors[1].path = "m/44'/60'/0'/0/1" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seeds := slices.Clone(seedVectors)
+			derivations := slices.Clone(derivationVectors)
+			t.Cleanup(func() { seedVectors, derivationVectors = seeds, derivations })
+			seedVectors, derivationVectors = slices.Clone(seeds), slices.Clone(derivations)
+
+			tt.tamper()
+			if err := VerifyDerivation(); err == nil {
+				t.Error("VerifyDerivation() succeeded with a mismatched vector")
+			}
+		})
+	}
+}
+
+func TestDerivationVectorsRecordPath(t *testing.T) {
+	for i, v := range derivationVectors {
+		account, err := deriveFromMnemonic(AlgoSecp256k1, v.mnemonic, v.passphrase, v.path)
+		if err != nil {
+			t.Fatalf("vector %d: deriveFromMnemonic() error = %v", i+1, err)
+		}
+		if account.DerivationPath != v.path {
+			t.Errorf("vector %d: DerivationPath = %s, want %s", i+1, account.DerivationPath, v.path)
+		}
+	}
+}
+
+func TestDeriveAccountsFromMnemonicAtIndexLimit(t *testing.T) {
+	accounts, err := DeriveAccountsFromMnemonicAt(testMnemonic, DefaultCoinType, maxAddressIndex, 1)
+	if err != nil {
+		t.Fatalf("DeriveAccountsFromMnemonicAt() at the last index error = %v", err)
+	}
+	if want := DerivationPath(DefaultCoinType, maxAddressIndex); accounts[0].DerivationPath != want {
+		t.Errorf("DerivationPath = %s, want %s", accounts[0].DerivationPath, want)
+	}
+
+	if _, err := DeriveAccountsFromMnemonicAt(testMnemonic, DefaultCoinType, maxAddressIndex, 2); err == nil {
+		t.Error("DeriveAccountsFromMnemonicAt() past the last index succeeded, want an error")
+	}
+}
+
+func TestImportAccountPassphraseChangesAddress(t *testing.T) {
+	plain, err := ImportAccount(testMnemonic, "", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+	withPassphrase, err := ImportAccount(testMnemonic, "TREZOR", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() with passphrase error = %v", err)
+	}
+	other, err := ImportAccount(testMnemonic, "trezor", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() with other passphrase error = %v", err)
+	}
+
+	if plain.Address == withPassphrase.Address || withPassphrase.Address == other.Address || plain.Address == other.Address {
+		t.Errorf("addresses %s, %s and %s are not all different", plain.Address, withPassphrase.Address, other.Address)
+	}
+	if plain.Mnemonic != withPassphrase.Mnemonic {
+		t.Errorf("Mnemonic = %q, want %q", withPassphrase.Mnemonic, plain.Mnemonic)
+	}
+}
+
+func TestImportAccountCoinTypeChangesAddress(t *testing.T) {
+	cosmos, err := ImportAccount(testMnemonic, "", 118)
+	if err != nil {
+		t.Fatalf("ImportAccount() with coin type 118 error = %v", err)
+	}
+	eth, err := ImportAccount(testMnemonic, "", 60)
+	if err != nil {
+		t.Fatalf("ImportAccount() with coin type 60 error = %v", err)
+	}
+
+	if cosmos.Address == eth.Address {
+		t.Errorf("coin types 118 and 60 both derived %s", cosmos.Address)
+	}
+	if cosmos.DerivationPath != "m/44'/118'/0'/0/0" || eth.DerivationPath != "m/44'/60'/0'/0/0" {
+		t.Errorf("DerivationPath = %s and %s, want the coin type in each", cosmos.DerivationPath, eth.DerivationPath)
+	}
+}
+
+func TestValidateMnemonic(t *testing.T) {
+	abandon11 := strings.Repeat("abandon ", 11)
+	tests := []struct {
+		name     string
+		mnemonic string
+		wantErr  string
+	}{
+		{"valid", testMnemonic, ""},
+		{"extra whitespace", "  " + strings.Replace(testMnemonic, " ", " \t ", 3) + "\n", ""},
+		{"too few words", strings.TrimSpace(abandon11), "expected 12, 15, 18, 21 or 24 words, got 11"},
+		{"unknown word", abandon11 + "abandonn", "word 12 is not in the BIP39 wordlist"},
+		{"bad checksum", abandon11 + "abandon", "checksum verification failed"},
+	}
+	for _, tt := range tests {
+		err := ValidateMnemonic(tt.mnemonic)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: ValidateMnemonic() error = %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: ValidateMnemonic() error = %v, want one containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestOnlySecp256k1KeysComeFromMnemonics(t *testing.T) {
+	for _, algo := range []KeyAlgorithm{AlgoSecp256r1, AlgoEd25519} {
+		account, err := GenerateAccountWithAlgorithm(algo, DefaultCoinType, DefaultMnemonicWords, "")
+		if err != nil {
+			t.Fatalf("GenerateAccountWithAlgorithm(%s) error = %v", algo, err)
+		}
+		if account.Mnemonic != "" || account.DerivationPath != "" || account.KeyAlgorithm != algo {
+			t.Errorf("GenerateAccountWithAlgorithm(%s) = %+v, want a %s key without mnemonic or path", algo, account, algo)
+		}
+		if err := account.Verify(); err != nil {
+			t.Errorf("Verify() of a generated %s account error = %v", algo, err)
+		}
+
+		if _, err := GenerateAccountWithAlgorithm(algo, DefaultCoinType, DefaultMnemonicWords, "TREZOR"); err == nil {
+			t.Errorf("GenerateAccountWithAlgorithm(%s) with a passphrase succeeded, want an error", algo)
+		}
+		if _, err := ImportAccountWithAlgorithm(testMnemonic, "", DefaultCoinType, algo); err == nil {
+			t.Errorf("ImportAccountWithAlgorithm(%s) succeeded, want an error", algo)
+		}
+		if _, err := ImportAccountsWithAlgorithm(testMnemonic, "", DefaultCoinType, algo, 0, 2); err == nil {
+			t.Errorf("ImportAccountsWithAlgorithm(%s) succeeded, want an error", algo)
+		}
+	}
+}
+
+func TestImportFromPrivateKey(t *testing.T) {
+	// n is the order of the secp256k1 group
+	const n = "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141"
+
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{"one", "0000000000000000000000000000000000000000000000000000000000000001", false},
+		{"n minus one", "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140", false},
+		{"0x prefix and spaces", " 0x1ab42cc412b618bdea3a599e3c9bae199ebf030895b039e9db1e30dafb12b727\n", false},
+		{"zero", "0000000000000000000000000000000000000000000000000000000000000000", true},
+		{"curve order", n, true},
+		{"curve order plus one", "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364142", true},
+		{"all ones", "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", true},
+		{"short", "01", true},
+		{"not hex", "zz00000000000000000000000000000000000000000000000000000000000001", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account, err := ImportFromPrivateKey(tt.key)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ImportFromPrivateKey(%q) succeeded, want an error", tt.key)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ImportFromPrivateKey(%q) error = %v", tt.key, err)
+			}
+			if err := ValidateSeiAddress(account.Address); err != nil {
+				t.Errorf("Address = %s: %v", account.Address, err)
+			}
+			if account.Mnemonic != "" || account.DerivationPath != "" {
+				t.Errorf("Mnemonic = %q, DerivationPath = %q, want both empty", account.Mnemonic, account.DerivationPath)
+			}
+		})
+	}
+}
+
+func BenchmarkGenerateAccounts(b *testing.B) {
+	const n = 32
+
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < n; j++ {
+				if _, err := GenerateAccount(DefaultCoinType, DefaultMnemonicWords, ""); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := GenerateAccounts(n, DefaultCoinType, DefaultMnemonicWords, ""); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package wallet
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Placeholders replaced in the command templates passed to GrantCommands
+const (
+	GranterPlaceholder = "{granter}"
+	GranteePlaceholder = "{grantee}"
+)
+
+// FeeGrantTemplate lets each grantee pay fees from the granter's balance, up to 1 SEI
+const FeeGrantTemplate = "seid tx feegrant grant " + GranterPlaceholder + " " + GranteePlaceholder + " --spend-limit 1000000usei"
+
+// AuthzSendTemplate lets each grantee send up to 1 SEI on the granter's behalf
+const AuthzSendTemplate = "seid tx authz grant " + GranteePlaceholder + " send --spend-limit 1000000usei --from " + GranterPlaceholder
+
+// GrantPair is one granter/grantee pair for a fee grant or authz grant
+type GrantPair struct {
+	Granter string `json:"granter"`
+	Grantee string `json:"grantee"`
+}
+
+// GrantPairs pairs granter with each of the accounts as grantee, in order.
+// Only addresses are used, so accounts without their secrets work too. An
+// account with the granter's own address is left out, since a grant to
+// oneself is rejected on chain. Every address is validated.
+func GrantPairs(granter string, accounts []*Account) ([]GrantPair, error) {
+	if err := ValidateSeiAddress(granter); err != nil {
+		return nil, fmt.Errorf("invalid granter: %w", err)
+	}
+
+	pairs := make([]GrantPair, 0, len(accounts))
+	for _, account := range accounts {
+		if account.Address == granter {
+			continue
+		}
+		if err := ValidateSeiAddress(account.Address); err != nil {
+			return nil, fmt.Errorf("invalid grantee: %w", err)
+		}
+		pairs = append(pairs, GrantPair{Granter: granter, Grantee: account.Address})
+	}
+	return pairs, nil
+}
+
+// GrantCommands returns one shell command per pair, made from template with
+// GranterPlaceholder and GranteePlaceholder replaced by the pair's addresses.
+// Like in ExportFundingScript the template is trusted and copied verbatim,
+// while the addresses are single-quoted.
+func GrantCommands(template string, pairs []GrantPair) ([]string, error) {
+	if !strings.Contains(template, GranteePlaceholder) {
+		return nil, fmt.Errorf("grant command template must contain %s", GranteePlaceholder)
+	}
+
+	commands := make([]string, len(pairs))
+	for i, pair := range pairs {
+		commands[i] = strings.NewReplacer(
+			GranterPlaceholder, shellQuote(pair.Granter),
+			GranteePlaceholder, shellQuote(pair.Grantee),
+		).Replace(template)
+	}
+	return commands, nil
+}
@@ -0,0 +1,79 @@
+package wallet
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGrantPairs(t *testing.T) {
+	granter := newTestAccount(t)
+	grantees := []*Account{newTestAccount(t), granter, newTestAccount(t)}
+
+	pairs, err := GrantPairs(granter.Address, grantees)
+	if err != nil {
+		t.Fatalf("GrantPairs() error = %v", err)
+	}
+	want := []GrantPair{
+		{Granter: granter.Address, Grantee: grantees[0].Address},
+		{Granter: granter.Address, Grantee: grantees[2].Address},
+	}
+	if !slices.Equal(pairs, want) {
+		t.Errorf("GrantPairs() = %v, want %v without the granter itself", pairs, want)
+	}
+
+	if pairs, err := GrantPairs(granter.Address, nil); len(pairs) != 0 || err != nil {
+		t.Errorf("GrantPairs() without accounts = %v, %v, want no pairs", pairs, err)
+	}
+	if _, err := GrantPairs("cosmos1invalid", grantees); err == nil {
+		t.Error("GrantPairs() with an invalid granter succeeded")
+	}
+	if _, err := GrantPairs(granter.Address, []*Account{{Address: "sei1invalid"}}); err == nil {
+		t.Error("GrantPairs() with an invalid grantee succeeded")
+	}
+}
+
+func TestGrantCommands(t *testing.T) {
+	pairs := []GrantPair{{Granter: "sei1granter", Grantee: "sei1a"}, {Granter: "sei1granter", Grantee: "it's"}}
+
+	tests := []struct {
+		name     string
+		template string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "fee grant",
+			template: FeeGrantTemplate,
+			want: []string{
+				"seid tx feegrant grant 'sei1granter' 'sei1a' --spend-limit 1000000usei",
+				`seid tx feegrant grant 'sei1granter' 'it'\''s' --spend-limit 1000000usei`,
+			},
+		},
+		{
+			name:     "authz send",
+			template: AuthzSendTemplate,
+			want: []string{
+				"seid tx authz grant 'sei1a' send --spend-limit 1000000usei --from 'sei1granter'",
+				`seid tx authz grant 'it'\''s' send --spend-limit 1000000usei --from 'sei1granter'`,
+			},
+		},
+		{
+			name:     "placeholders repeated",
+			template: "echo {grantee} {grantee}",
+			want:     []string{"echo 'sei1a' 'sei1a'", `echo 'it'\''s' 'it'\''s'`},
+		},
+		{name: "no grantee placeholder", template: "seid tx feegrant grant {granter}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GrantCommands(tt.template, pairs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GrantCommands() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("GrantCommands() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
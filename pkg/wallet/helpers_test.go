@@ -0,0 +1,65 @@
+package wallet
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+// testPassword is the database password of the stores opened by the tests
+const testPassword = "test-password-123"
+
+// testMnemonic is the well-known BIP39 test vector phrase
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// testConfig returns the default store settings with log output discarded
+func testConfig() StoreConfig {
+	config := DefaultStoreConfig()
+	config.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	return config
+}
+
+// openTestStore opens the store in dir with testPassword and config and closes
+// it when the test ends
+func openTestStore(t testing.TB, dir string, config StoreConfig) *AccountStore {
+	t.Helper()
+	store, err := NewAccountStoreWithConfig(dir, testPassword, config)
+	if err != nil {
+		t.Fatalf("NewAccountStoreWithConfig() error = %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// newTestStore opens a new store in a temporary directory
+func newTestStore(t testing.TB) *AccountStore {
+	t.Helper()
+	return openTestStore(t, t.TempDir(), testConfig())
+}
+
+// newTestAccount generates a fresh account with the default settings
+func newTestAccount(t testing.TB) *Account {
+	t.Helper()
+	account, err := GenerateAccount(DefaultCoinType, DefaultMnemonicWords, "")
+	if err != nil {
+		t.Fatalf("GenerateAccount() error = %v", err)
+	}
+	return account
+}
+
+// withPrefix returns address encoded with another bech32 prefix, such as the
+// cosmos address of a Sei account
+func withPrefix(t testing.TB, address, prefix string) string {
+	t.Helper()
+	_, data, err := bech32.DecodeAndConvert(address)
+	if err != nil {
+		t.Fatalf("DecodeAndConvert(%s) error = %v", address, err)
+	}
+	encoded, err := bech32.ConvertAndEncode(prefix, data)
+	if err != nil {
+		t.Fatalf("ConvertAndEncode() error = %v", err)
+	}
+	return encoded
+}
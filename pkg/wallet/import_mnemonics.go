@@ -0,0 +1,107 @@
+package wallet
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// mnemonicImportBatch is how many derived accounts ImportMnemonics saves per transaction
+const mnemonicImportBatch = 500
+
+// MnemonicLineError is a line that ImportMnemonics could not import
+type MnemonicLineError struct {
+	// Line is the 1-based line number in the input
+	Line int
+	Err  error
+}
+
+// Error implements error without repeating the line's mnemonic
+func (e *MnemonicLineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *MnemonicLineError) Unwrap() error {
+	return e.Err
+}
+
+// MnemonicImport summarizes an ImportMnemonics run
+type MnemonicImport struct {
+	// Imported is the number of new accounts stored
+	Imported int
+	// Skipped counts valid mnemonics whose account was already stored or
+	// appeared earlier in the input
+	Skipped int
+	// SharedMnemonic counts valid mnemonics that were not imported because an
+	// account derived from them differently, such as with a BIP39 passphrase,
+	// is already stored, see SharedMnemonicError
+	SharedMnemonic int
+	// Failed lists the lines that were not imported, in input order
+	Failed []*MnemonicLineError
+}
+
+// ImportMnemonics reads one mnemonic per line from r, derives the account at
+// the first address index of the standard path for coinType, and saves the
+// accounts in batches with SaveAccounts. Each mnemonic is normalized as in
+// ImportAccount, and blank lines are ignored. Invalid mnemonics and, unless
+// allowWeak is set, ones rejected by CheckMnemonicStrength are recorded in the
+// result instead of stopping the import. An error is returned only if reading
+// the input or saving a batch fails. Batches saved before then are kept, and
+// on a read error the mnemonics read before it are still saved.
+func ImportMnemonics(store Store, r io.Reader, coinType uint32, allowWeak bool) (*MnemonicImport, error) {
+	result := &MnemonicImport{}
+	batch := make([]*Account, 0, mnemonicImportBatch)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		inserted, err := store.SaveAccounts(batch)
+		refused := 0
+		var shared *SharedMnemonicError
+		if errors.As(err, &shared) {
+			refused = len(shared.Addresses)
+		} else if err != nil {
+			return fmt.Errorf("failed to save imported accounts: %w", err)
+		}
+		result.Imported += inserted
+		result.SharedMnemonic += refused
+		result.Skipped += len(batch) - inserted - refused
+		batch = batch[:0]
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		mnemonic := strings.TrimSpace(scanner.Text())
+		if mnemonic == "" {
+			continue
+		}
+
+		account, err := ImportAccount(mnemonic, "", coinType)
+		if err == nil && !allowWeak {
+			err = CheckMnemonicStrength(account.Mnemonic)
+		}
+		if err != nil {
+			result.Failed = append(result.Failed, &MnemonicLineError{Line: line, Err: err})
+			continue
+		}
+
+		if batch = append(batch, account); len(batch) == mnemonicImportBatch {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+	// Save what was read before a read error, like the batches before it
+	if err := flush(); err != nil {
+		return result, err
+	}
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("failed to read mnemonics: %w", err)
+	}
+	return result, nil
+}
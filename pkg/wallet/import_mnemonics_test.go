@@ -0,0 +1,86 @@
+package wallet
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestImportMnemonicsReportsLineNumbers(t *testing.T) {
+	first, second := newTestAccount(t), newTestAccount(t)
+	words := strings.Fields(first.Mnemonic)
+	words[6] = "notaword"
+
+	input := strings.Join([]string{
+		first.Mnemonic,
+		"",
+		strings.Join(words, " "), // line 3: not in the wordlist
+		"  " + second.Mnemonic + "  ",
+		testMnemonic,   // line 5: weak
+		first.Mnemonic, // a duplicate, not a failure
+		"abandon abandon",
+	}, "\n")
+
+	store := NewMemoryStore()
+	result, err := ImportMnemonics(store, strings.NewReader(input), DefaultCoinType, false)
+	if err != nil {
+		t.Fatalf("ImportMnemonics() error = %v", err)
+	}
+	if result.Imported != 2 || result.Skipped != 1 {
+		t.Errorf("ImportMnemonics() imported %d and skipped %d, want 2 and 1", result.Imported, result.Skipped)
+	}
+
+	wantLines := []int{3, 5, 7}
+	if len(result.Failed) != len(wantLines) {
+		t.Fatalf("ImportMnemonics() failed lines = %v, want lines %v", result.Failed, wantLines)
+	}
+	for i, failure := range result.Failed {
+		if failure.Line != wantLines[i] {
+			t.Errorf("failure %d is on line %d, want %d", i, failure.Line, wantLines[i])
+		}
+	}
+	if !errors.Is(result.Failed[1], ErrWeakMnemonic) {
+		t.Errorf("line 5 error = %v, want ErrWeakMnemonic", result.Failed[1])
+	}
+
+	// With allowWeak only the malformed lines fail
+	result, err = ImportMnemonics(NewMemoryStore(), strings.NewReader(input), DefaultCoinType, true)
+	if err != nil {
+		t.Fatalf("ImportMnemonics() with allowWeak error = %v", err)
+	}
+	if len(result.Failed) != 2 || result.Failed[0].Line != 3 || result.Failed[1].Line != 7 {
+		t.Errorf("ImportMnemonics() with allowWeak failed lines = %v, want lines 3 and 7", result.Failed)
+	}
+}
+
+// failingReader returns data and then err
+type failingReader struct {
+	data string
+	err  error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestImportMnemonicsSavesLinesReadBeforeAnError(t *testing.T) {
+	account := newTestAccount(t)
+	readErr := errors.New("disk on fire")
+	store := NewMemoryStore()
+
+	result, err := ImportMnemonics(store, &failingReader{data: account.Mnemonic + "\n", err: readErr}, DefaultCoinType, false)
+	if !errors.Is(err, readErr) {
+		t.Fatalf("ImportMnemonics() error = %v, want %v", err, readErr)
+	}
+	if result.Imported != 1 {
+		t.Errorf("ImportMnemonics() imported %d accounts before the read error, want 1", result.Imported)
+	}
+	if _, err := store.GetAccountByAddress(account.Address); err != nil {
+		t.Errorf("GetAccountByAddress() of the account read before the error: %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+)
+
+// KeyringAppName is the application name used for keyring entries, matching seid
+const KeyringAppName = "sei"
+
+// KeyringExportResult summarizes an ExportToKeyring run
+type KeyringExportResult struct {
+	// Exported is the number of keys added to the keyring
+	Exported int
+	// Skipped lists the accounts that were not exported because the keyring
+	// already had a key with their name, in store order
+	Skipped []SkippedKey
+}
+
+// SkippedKey is an account ExportToKeyring left out because its key name was taken
+type SkippedKey struct {
+	Address string
+	Name    string
+	// Existing is the address of the key already stored under Name. It equals
+	// Address when the account was exported before.
+	Existing string
+}
+
+// ExportToKeyring writes every stored account into a Cosmos SDK keyring rooted
+// at dir, so tools like `seid keys list --keyring-dir <dir>` can use them
+// directly. Keys are named after the account label, or the address when no
+// label is set. Keys that already exist in the keyring are left untouched and
+// the accounts that would have used their names are reported as skipped, which
+// includes the second of two accounts with the same label.
+// The file backend prompts for its passphrase on stdin.
+func (s *AccountStore) ExportToKeyring(dir, backend string) (*KeyringExportResult, error) {
+	accounts, err := s.getAccounts(context.Background(), true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	registry := codectypes.NewInterfaceRegistry()
+	cryptocodec.RegisterInterfaces(registry)
+	cdc := codec.NewProtoCodec(registry)
+
+	kr, err := keyring.New(KeyringAppName, backend, dir, os.Stdin, cdc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s keyring: %w", backend, err)
+	}
+
+	result := &KeyringExportResult{}
+	for _, account := range accounts {
+		name := KeyringKeyName(account)
+
+		if existing, err := kr.Key(name); err == nil {
+			addr, err := existing.GetAddress()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get address of key %s: %w", name, err)
+			}
+			result.Skipped = append(result.Skipped, SkippedKey{Address: account.Address, Name: name, Existing: addr.String()})
+			continue
+		}
+
+		// The SDK keyring only accepts secp256k1 keys by default
+		if algo := account.algorithm(); algo != AlgoSecp256k1 {
+			return nil, fmt.Errorf("cannot import %s into keyring: %s keys are not supported", account.Address, algo)
+		}
+
+		// Import the raw key rather than re-deriving from the mnemonic so
+		// accounts without a mnemonic are exported too
+		if err := kr.ImportPrivKeyHex(name, account.PrivateKey, string(hd.Secp256k1Type)); err != nil {
+			return nil, fmt.Errorf("failed to import %s into keyring: %w", account.Address, err)
+		}
+
+		// Confirm the keyring derives the same address from the imported key
+		record, err := kr.Key(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read back key %s: %w", name, err)
+		}
+		addr, err := record.GetAddress()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get address of key %s: %w", name, err)
+		}
+		if addr.String() != account.Address {
+			return nil, fmt.Errorf("keyring address %s does not match stored address %s", addr, account.Address)
+		}
+		result.Exported++
+	}
+
+	return result, nil
+}
+
+// KeyringKeyName returns the name ExportToKeyring gives the key of account: its
+// label, or its address when no label is set
+func KeyringKeyName(account *Account) string {
+	if account.Label != "" {
+		return account.Label
+	}
+	return account.Address
+}
+
+// SeidRecoverCommand returns a shell command that imports account into a seid
+// keyring under name by piping its mnemonic into `seid keys add --recover`,
+// for example `echo '<mnemonic>' | seid keys add 'mykey' --recover`. Accounts
+// not at DefaultDerivationPath get a matching --hd-path, and a non-empty
+// keyringBackend or keyringDir is passed as --keyring-backend or --keyring-dir.
+// See CheckSeidRecoverable for the accounts this is refused for; the private
+// key is never part of the command.
+func SeidRecoverCommand(account *Account, name, keyringBackend, keyringDir string) (string, error) {
+	if err := CheckSeidRecoverable(account); err != nil {
+		return "", err
+	}
+
+	command := "echo " + shellQuote(account.Mnemonic) + " | seid keys add " + shellQuote(name) + " --recover"
+	if account.DerivationPath != DefaultDerivationPath {
+		command += " --hd-path " + shellQuote(account.DerivationPath)
+	}
+	if keyringBackend != "" {
+		command += " --keyring-backend " + shellQuote(keyringBackend)
+	}
+	if keyringDir != "" {
+		command += " --keyring-dir " + shellQuote(keyringDir)
+	}
+	return command, nil
+}
+
+// CheckSeidRecoverable reports whether `seid keys add --recover` recreates
+// account from its mnemonic alone. That fails for accounts imported from a
+// private key, which have no mnemonic, for key algorithms other than
+// secp256k1, for accounts whose derivation path is unknown, and for mnemonics
+// used with a BIP39 passphrase, which seid only asks for with --interactive.
+// The last is detected by deriving the key again without a passphrase and
+// comparing public keys.
+func CheckSeidRecoverable(account *Account) error {
+	if account.Mnemonic == "" {
+		return fmt.Errorf("account %s has no mnemonic, only a private key", account.Address)
+	}
+	if algo := account.algorithm(); algo != AlgoSecp256k1 {
+		return fmt.Errorf("cannot recover %s with seid: %s keys are not supported", account.Address, algo)
+	}
+
+	if account.DerivationPath == "" {
+		return fmt.Errorf("cannot recover %s with seid: its derivation path is unknown", account.Address)
+	}
+	derived, err := deriveFromMnemonic(AlgoSecp256k1, account.Mnemonic, "", account.DerivationPath)
+	if err != nil {
+		return fmt.Errorf("failed to derive %s from its mnemonic: %w", account.Address, err)
+	}
+	if derived.PubKey != account.PubKey {
+		return fmt.Errorf("account %s was derived with a BIP39 passphrase; run seid keys add --recover --interactive and enter it", account.Address)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package wallet
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+)
+
+func TestExportToKeyringReportsSkippedKeys(t *testing.T) {
+	store := newTestStore(t)
+	first, second, unlabeled := newTestAccount(t), newTestAccount(t), newTestAccount(t)
+	first.Label, second.Label = "ops", "ops"
+	if _, err := store.SaveAccounts([]*Account{first, second, unlabeled}); err != nil {
+		t.Fatalf("SaveAccounts() error = %v", err)
+	}
+	dir := t.TempDir()
+
+	result, err := store.ExportToKeyring(dir, keyring.BackendTest)
+	if err != nil {
+		t.Fatalf("ExportToKeyring() error = %v", err)
+	}
+	want := []SkippedKey{{Address: second.Address, Name: "ops", Existing: first.Address}}
+	if result.Exported != 2 || !slices.Equal(result.Skipped, want) {
+		t.Errorf("ExportToKeyring() = %d exported, skipped %v, want 2 and %v", result.Exported, result.Skipped, want)
+	}
+
+	// A second run finds every key, and the label clash again
+	result, err = store.ExportToKeyring(dir, keyring.BackendTest)
+	if err != nil {
+		t.Fatalf("ExportToKeyring() again error = %v", err)
+	}
+	want = []SkippedKey{
+		{Address: first.Address, Name: "ops", Existing: first.Address},
+		{Address: second.Address, Name: "ops", Existing: first.Address},
+		{Address: unlabeled.Address, Name: unlabeled.Address, Existing: unlabeled.Address},
+	}
+	if result.Exported != 0 || !slices.Equal(result.Skipped, want) {
+		t.Errorf("ExportToKeyring() again = %d exported, skipped %v, want 0 and %v", result.Exported, result.Skipped, want)
+	}
+}
+
+func TestSeidRecoverCommand(t *testing.T) {
+	plain, err := ImportAccount(testMnemonic, "", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+	indexed, err := ImportAccountsWithAlgorithm(testMnemonic, "", DefaultCoinType, DefaultKeyAlgorithm, 2, 1)
+	if err != nil {
+		t.Fatalf("ImportAccountsWithAlgorithm() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		account      *Account
+		key          string
+		backend, dir string
+		want         string
+	}{
+		{
+			name:    "default path",
+			account: plain,
+			key:     "mykey",
+			want:    "echo '" + testMnemonic + "' | seid keys add 'mykey' --recover",
+		},
+		{
+			name:    "later index",
+			account: indexed[0],
+			key:     "mykey",
+			backend: "test",
+			dir:     "/tmp/keys",
+			want: "echo '" + testMnemonic + "' | seid keys add 'mykey' --recover --hd-path " + `'m/44'\''/118'\''/0'\''/0/2'` +
+				" --keyring-backend 'test' --keyring-dir '/tmp/keys'",
+		},
+		{
+			name:    "label with a quote",
+			account: plain,
+			key:     "it's mine",
+			want:    "echo '" + testMnemonic + "' | seid keys add " + `'it'\''s mine'` + " --recover",
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := SeidRecoverCommand(tt.account, tt.key, tt.backend, tt.dir)
+		if err != nil {
+			t.Errorf("%s: SeidRecoverCommand() error = %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: SeidRecoverCommand() =\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckSeidRecoverable(t *testing.T) {
+	plain, err := ImportAccount(testMnemonic, "", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+	fromKey, err := ImportFromPrivateKey(newTestAccount(t).PrivateKey)
+	if err != nil {
+		t.Fatalf("ImportFromPrivateKey() error = %v", err)
+	}
+	withPassphrase, err := ImportAccount(testMnemonic, "TREZOR", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() with passphrase error = %v", err)
+	}
+	// Older stores can hold ed25519 keys with a mnemonic, and accounts whose
+	// path could not be recovered
+	ed25519 := *plain
+	ed25519.KeyAlgorithm = AlgoEd25519
+	unknownPath := *plain
+	unknownPath.DerivationPath = ""
+
+	tests := []struct {
+		name    string
+		account *Account
+		wantErr string
+	}{
+		{name: "mnemonic", account: plain},
+		{name: "private key import", account: fromKey, wantErr: "no mnemonic"},
+		{name: "passphrase", account: withPassphrase, wantErr: "BIP39 passphrase"},
+		{name: "ed25519", account: &ed25519, wantErr: "ed25519 keys are not supported"},
+		{name: "unknown path", account: &unknownPath, wantErr: "derivation path is unknown"},
+	}
+
+	for _, tt := range tests {
+		err := CheckSeidRecoverable(tt.account)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: CheckSeidRecoverable() error = %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: CheckSeidRecoverable() error = %v, want one mentioning %q", tt.name, err, tt.wantErr)
+		}
+		if _, err := SeidRecoverCommand(tt.account, "mykey", "", ""); err == nil {
+			t.Errorf("%s: SeidRecoverCommand() succeeded, want an error", tt.name)
+		}
+	}
+}
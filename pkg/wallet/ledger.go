@@ -0,0 +1,15 @@
+package wallet
+
+import (
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+)
+
+// LedgerDerivationPath returns the path the Ledger Cosmos app and Ledger Live
+// use for the given coin type and account number: m/44'/{coinType}'/{account}'/0/0.
+// Ledger walks the hardened account level and keeps the address index at 0,
+// whereas DerivationPath walks the address index of account 0. Both agree on
+// account 0, so a generated account matches the first Ledger account of the
+// same seed.
+func LedgerDerivationPath(coinType, account uint32) string {
+	return hd.NewFundraiserParams(account, coinType, 0).String()
+}
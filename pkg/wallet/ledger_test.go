@@ -0,0 +1,67 @@
+package wallet
+
+import "testing"
+
+func TestLedgerDerivationPath(t *testing.T) {
+	tests := []struct {
+		coinType uint32
+		account  uint32
+		want     string
+	}{
+		{coinType: DefaultCoinType, account: 0, want: "m/44'/118'/0'/0/0"},
+		{coinType: DefaultCoinType, account: 3, want: "m/44'/118'/3'/0/0"},
+		{coinType: 60, account: 1, want: "m/44'/60'/1'/0/0"},
+	}
+
+	for _, tt := range tests {
+		if got := LedgerDerivationPath(tt.coinType, tt.account); got != tt.want {
+			t.Errorf("LedgerDerivationPath(%d, %d) = %s, want %s", tt.coinType, tt.account, got, tt.want)
+		}
+	}
+
+	// Account 0 is where the two layouts meet
+	if ledger, standard := LedgerDerivationPath(DefaultCoinType, 0), DerivationPath(DefaultCoinType, 0); ledger != standard {
+		t.Errorf("LedgerDerivationPath() = %s at account 0, DerivationPath() = %s", ledger, standard)
+	}
+}
+
+func TestDeriveLedgerAddresses(t *testing.T) {
+	account, err := ImportAccount(testMnemonic, "", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+	store := newTestStore(t)
+	if _, err := store.SaveAccount(account); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+
+	addresses, err := store.DeriveLedgerAddresses(account.Address, DefaultCoinType, 0, 3)
+	if err != nil {
+		t.Fatalf("DeriveLedgerAddresses() error = %v", err)
+	}
+	if addresses[0].Address != account.Address {
+		t.Errorf("DeriveLedgerAddresses() at account 0 = %s, want the stored %s", addresses[0].Address, account.Address)
+	}
+	for i, address := range addresses {
+		path := LedgerDerivationPath(DefaultCoinType, uint32(i))
+		want, err := deriveFromMnemonic(AlgoSecp256k1, testMnemonic, "", path)
+		if err != nil {
+			t.Fatalf("deriveFromMnemonic() error = %v", err)
+		}
+		if address.Address != want.Address || address.Path != path {
+			t.Errorf("DeriveLedgerAddresses() at account %d = %+v, want %s at %s", i, address, want.Address, path)
+		}
+	}
+
+	extra, err := store.DeriveExtraAddresses(account.Address, DefaultCoinType, 1, 1)
+	if err != nil {
+		t.Fatalf("DeriveExtraAddresses() error = %v", err)
+	}
+	if extra[0] == addresses[1] {
+		t.Error("DeriveLedgerAddresses() and DeriveExtraAddresses() agree past index 0")
+	}
+
+	if _, err := store.DeriveLedgerAddresses(account.Address, 60, 0, 1); err == nil {
+		t.Error("DeriveLedgerAddresses() with another coin type succeeded, want an error")
+	}
+}
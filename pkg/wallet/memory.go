@@ -0,0 +1,426 @@
+package wallet
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store that keeps accounts in process memory. Nothing is
+// written to disk, which makes it suitable for tests and dry runs. Saving
+// applies the same checks as AccountStore: duplicate addresses are skipped,
+// foreign address prefixes are rejected, and shared mnemonics are refused.
+type MemoryStore struct {
+	accounts []*Account
+	archived map[string]bool
+	config   StoreConfig
+	mu       sync.Mutex
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return NewMemoryStoreWithConfig(StoreConfig{})
+}
+
+// NewMemoryStoreWithConfig is like NewMemoryStore but honors the
+// AllowForeignPrefix and AllowSharedMnemonics settings of config like
+// AccountStore does. The other settings only concern the database and are
+// ignored.
+func NewMemoryStoreWithConfig(config StoreConfig) *MemoryStore {
+	return &MemoryStore{archived: make(map[string]bool), config: config}
+}
+
+// SaveAccount stores a copy of account. It reports true if the account was
+// inserted and false if an account with the same address already existed, and
+// refuses an account whose mnemonic is already stored, unless it is an HD
+// sibling, with a SharedMnemonicError, just like AccountStore.SaveAccount.
+func (m *MemoryStore) SaveAccount(account *Account) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	inserted, shared, err := m.save(account, nil)
+	if shared {
+		return false, &SharedMnemonicError{Addresses: []string{account.Address}}
+	}
+	return inserted, err
+}
+
+// SaveAccounts stores a batch of accounts and returns how many were inserted,
+// skipping addresses that already exist. As with AccountStore.SaveAccounts,
+// accounts refused for a shared mnemonic are listed in a SharedMnemonicError,
+// and any other error leaves the store unchanged.
+func (m *MemoryStore) SaveAccounts(accounts []*Account) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	before := len(m.accounts)
+	inserted := 0
+	var refused []string
+	batch := make(map[string]bool, len(accounts))
+	for _, account := range accounts {
+		ok, shared, err := m.save(account, batch)
+		if err != nil {
+			clear(m.accounts[before:])
+			m.accounts = m.accounts[:before]
+			return 0, err
+		}
+		if shared {
+			refused = append(refused, account.Address)
+		}
+		if ok {
+			batch[account.Address] = true
+			inserted++
+		}
+	}
+	if len(refused) > 0 {
+		return inserted, &SharedMnemonicError{Addresses: refused}
+	}
+	return inserted, nil
+}
+
+// save appends a copy of account unless its address is taken or, reported as
+// shared, its mnemonic is stored and it is no HD sibling of the account holding
+// it. batch holds the addresses saved earlier in the same SaveAccounts call, or
+// is nil. The caller must hold m.mu.
+func (m *MemoryStore) save(account *Account, batch map[string]bool) (inserted, shared bool, err error) {
+	if m.find(account.Address) >= 0 {
+		return false, false, nil
+	}
+	if err := checkAccountPrefix(account, m.config.AllowForeignPrefix); err != nil {
+		return false, false, err
+	}
+	if holder := m.mnemonicHolder(account.Mnemonic); holder != nil && !m.config.AllowSharedMnemonics {
+		sibling, err := isHDSibling(account, holder, batch[holder.address])
+		if err != nil {
+			return false, false, err
+		}
+		if !sibling {
+			return false, true, nil
+		}
+	}
+
+	// Match the database, which fills in the creation time with second precision
+	stored := *account
+	if stored.CreatedAt.IsZero() {
+		stored.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	}
+	stored.KeyAlgorithm = stored.algorithm()
+	m.accounts = append(m.accounts, &stored)
+	return true, false, nil
+}
+
+// find returns the index of the account with the given address, or -1. The caller must hold m.mu.
+func (m *MemoryStore) find(address string) int {
+	for i, account := range m.accounts {
+		if account.Address == address {
+			return i
+		}
+	}
+	return -1
+}
+
+// active returns the accounts that are not archived. The caller must hold m.mu.
+func (m *MemoryStore) active() []*Account {
+	accounts := make([]*Account, 0, len(m.accounts))
+	for _, account := range m.accounts {
+		if !m.archived[account.Address] {
+			accounts = append(accounts, account)
+		}
+	}
+	return accounts
+}
+
+// GetAccounts returns copies of all accounts that are not archived, in insertion order
+func (m *MemoryStore) GetAccounts() ([]*Account, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return copyAccounts(m.active()), nil
+}
+
+// GetAccountsPage returns up to limit accounts in insertion order, skipping the first offset
+func (m *MemoryStore) GetAccountsPage(limit, offset int) ([]*Account, error) {
+	return m.GetAccountsSortedPage(SortByID, limit, offset)
+}
+
+// GetAccountsSortedPage is like GetAccountsPage but orders accounts by sortBy,
+// with the same orders as AccountStore.GetAccountsSortedPage
+func (m *MemoryStore) GetAccountsSortedPage(sortBy string, limit, offset int) ([]*Account, error) {
+	if _, ok := accountSortClauses[sortBy]; !ok {
+		return nil, fmt.Errorf("unsupported sort order %q", sortBy)
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("page limit must be positive, got %d", limit)
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("page offset must not be negative, got %d", offset)
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	m.mu.Lock()
+	accounts := copyAccounts(m.active())
+	m.mu.Unlock()
+
+	// The slice starts in insertion order, so a stable sort keeps the id tiebreaker
+	switch sortBy {
+	case SortByAddress:
+		sort.SliceStable(accounts, func(i, j int) bool {
+			return accounts[i].Address < accounts[j].Address
+		})
+	case SortByCreated:
+		// Newest first, with later insertions first among equal timestamps
+		for i, j := 0, len(accounts)-1; i < j; i, j = i+1, j-1 {
+			accounts[i], accounts[j] = accounts[j], accounts[i]
+		}
+		sort.SliceStable(accounts, func(i, j int) bool {
+			return accounts[i].CreatedAt.After(accounts[j].CreatedAt)
+		})
+	case SortByLabel:
+		sort.SliceStable(accounts, func(i, j int) bool {
+			a, b := accounts[i].Label, accounts[j].Label
+			if (a == "") != (b == "") {
+				return a != ""
+			}
+			return a < b
+		})
+	}
+
+	if offset >= len(accounts) {
+		return nil, nil
+	}
+	accounts = accounts[offset:]
+	if len(accounts) > limit {
+		accounts = accounts[:limit]
+	}
+	return accounts, nil
+}
+
+// GetAccountByAddress returns a copy of the account with the given address,
+// or ErrAccountNotFound
+func (m *MemoryStore) GetAccountByAddress(address string) (*Account, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := ValidateSeiAddress(address); err != nil {
+		return nil, err
+	}
+
+	if m.archived[address] {
+		return nil, fmt.Errorf("%w: %s is archived (restore it first)", ErrAccountNotFound, address)
+	}
+	i := m.find(address)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, address)
+	}
+	account := *m.accounts[i]
+	return &account, nil
+}
+
+// SuggestAddress returns the active stored address closest to a mistyped
+// one, like AccountStore.SuggestAddress
+func (m *MemoryStore) SuggestAddress(address string) (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.find(address) >= 0 {
+		return "", false, nil
+	}
+	active := m.active()
+	addresses := make([]string, len(active))
+	for i, account := range active {
+		addresses[i] = account.Address
+	}
+
+	suggestion, ok := closestMatch(address, addresses)
+	return suggestion, ok, nil
+}
+
+// CountAccounts returns the number of stored accounts, not counting archived ones
+func (m *MemoryStore) CountAccounts() (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.accounts) - len(m.archived), nil
+}
+
+// DeleteAccount archives the account with the given address, or returns
+// ErrAccountNotFound if no active account matched
+func (m *MemoryStore) DeleteAccount(address string) error {
+	if err := ValidateSeiAddress(address); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.find(address) < 0 || m.archived[address] {
+		return fmt.Errorf("%w: %s", ErrAccountNotFound, address)
+	}
+	m.archived[address] = true
+	return nil
+}
+
+// Restore makes the archived account with the given address active again, or
+// returns ErrAccountNotFound if no archived account matched
+func (m *MemoryStore) Restore(address string) error {
+	if err := ValidateSeiAddress(address); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.archived[address] {
+		return fmt.Errorf("%w: no archived account %s", ErrAccountNotFound, address)
+	}
+	delete(m.archived, address)
+	return nil
+}
+
+// RotateAccount archives the active account with the given address and stores
+// a freshly generated successor, like AccountStore.RotateAccount
+func (m *MemoryStore) RotateAccount(oldAddress, newLabel string) (*Account, error) {
+	if err := ValidateSeiAddress(oldAddress); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.find(oldAddress)
+	if i < 0 || m.archived[oldAddress] {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, oldAddress)
+	}
+
+	successor, err := newSuccessor(m.accounts[i], newLabel)
+	if err != nil {
+		return nil, err
+	}
+	inserted, shared, err := m.save(successor, nil)
+	if err != nil {
+		return nil, err
+	}
+	if shared {
+		return nil, fmt.Errorf("successor of %s not saved: %w", oldAddress, ErrMnemonicAlreadyStored)
+	}
+	if !inserted {
+		return nil, fmt.Errorf("successor address %s is already stored", successor.Address)
+	}
+	m.accounts[i].Successor = successor.Address
+	m.archived[oldAddress] = true
+	return successor, nil
+}
+
+// PurgeAccount removes the active or archived account with the given address,
+// or returns ErrAccountNotFound
+func (m *MemoryStore) PurgeAccount(address string) error {
+	if err := ValidateSeiAddress(address); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.find(address)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrAccountNotFound, address)
+	}
+	m.accounts = append(m.accounts[:i], m.accounts[i+1:]...)
+	delete(m.archived, address)
+	return nil
+}
+
+// ListArchived returns copies of the archived accounts in insertion order
+func (m *MemoryStore) ListArchived() ([]*Account, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var archived []*Account
+	for _, account := range m.accounts {
+		if m.archived[account.Address] {
+			archived = append(archived, account)
+		}
+	}
+	return copyAccounts(archived), nil
+}
+
+// SetLabel sets the label of the account with the given address. An empty
+// label clears it. It returns ErrAccountNotFound if no account matched.
+func (m *MemoryStore) SetLabel(address, label string) error {
+	if err := ValidateSeiAddress(address); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.find(address)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrAccountNotFound, address)
+	}
+	m.accounts[i].Label = label
+	return nil
+}
+
+// UpdateAccount edits the label and derivation path of the account with the
+// given address through fn, like AccountStore.UpdateAccount
+func (m *MemoryStore) UpdateAccount(address string, fn func(*Account) error) error {
+	if err := ValidateSeiAddress(address); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.find(address)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrAccountNotFound, address)
+	}
+
+	updated, err := applyAccountUpdate(m.accounts[i], fn)
+	if err != nil {
+		return err
+	}
+	m.accounts[i].Label = updated.Label
+	m.accounts[i].DerivationPath = updated.DerivationPath
+	return nil
+}
+
+// Close discards the stored accounts
+func (m *MemoryStore) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.accounts = nil
+	m.archived = make(map[string]bool)
+	return nil
+}
+
+// copyAccounts returns copies of accounts so callers cannot modify stored state
+func copyAccounts(accounts []*Account) []*Account {
+	copies := make([]*Account, len(accounts))
+	for i, account := range accounts {
+		account := *account
+		copies[i] = &account
+	}
+	return copies
+}
+
+// mnemonicHolder returns the account that would hold the hash of mnemonic in
+// the database: the first one stored with it, archived ones included. It
+// returns nil if no account has it. The caller must hold m.mu.
+func (m *MemoryStore) mnemonicHolder(mnemonic string) *mnemonicHolder {
+	if mnemonic == "" {
+		return nil
+	}
+	mnemonic = NormalizeMnemonic(mnemonic)
+	for _, stored := range m.accounts {
+		if stored.Mnemonic != "" && NormalizeMnemonic(stored.Mnemonic) == mnemonic {
+			return &mnemonicHolder{address: stored.Address, path: stored.DerivationPath, pubKey: stored.PubKey}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package wallet
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestMemoryStoreMatchesAccountStore saves the same batches into both backends
+// and expects the same results
+func TestMemoryStoreMatchesAccountStore(t *testing.T) {
+	hd, err := DeriveAccountsFromMnemonic(testMnemonic, DefaultCoinType, 5)
+	if err != nil {
+		t.Fatalf("DeriveAccountsFromMnemonic() error = %v", err)
+	}
+	withPassphrase, err := ImportAccount(testMnemonic, "TREZOR", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+	foreign := newTestAccount(t)
+	foreign.Address = withPrefix(t, foreign.Address, "cosmos")
+
+	stores := map[string]Store{
+		"sqlite": newTestStore(t),
+		"memory": NewMemoryStore(),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			if inserted, err := store.SaveAccounts(hd); inserted != 5 || err != nil {
+				t.Errorf("SaveAccounts() of an HD batch = %d, %v, want 5, nil", inserted, err)
+			}
+
+			if ok, err := store.SaveAccount(hd[0]); ok || err != nil {
+				t.Errorf("SaveAccount() of a stored address = %v, %v, want false, nil", ok, err)
+			}
+			if _, err := store.SaveAccount(withPassphrase); !errors.Is(err, ErrMnemonicAlreadyStored) {
+				t.Errorf("SaveAccount() of a shared mnemonic error = %v, want ErrMnemonicAlreadyStored", err)
+			}
+
+			batch := []*Account{newTestAccount(t), foreign}
+			if _, err := store.SaveAccounts(batch); !errors.Is(err, ErrForeignPrefix) {
+				t.Errorf("SaveAccounts() with a foreign prefix error = %v, want ErrForeignPrefix", err)
+			}
+			if count, err := store.CountAccounts(); err != nil || count != 5 {
+				t.Errorf("CountAccounts() after a rejected batch = %d, %v, want 5", count, err)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreAllowSharedMnemonics(t *testing.T) {
+	plain, err := ImportAccount(testMnemonic, "", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+	withPassphrase, err := ImportAccount(testMnemonic, "TREZOR", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() with passphrase error = %v", err)
+	}
+	store := NewMemoryStoreWithConfig(StoreConfig{AllowSharedMnemonics: true})
+	if inserted, err := store.SaveAccounts([]*Account{plain, withPassphrase}); inserted != 2 || err != nil {
+		t.Errorf("SaveAccounts() = %d, %v, want 2, nil", inserted, err)
+	}
+}
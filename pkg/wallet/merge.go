@@ -0,0 +1,78 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// MergeResult summarizes a MergeFrom run
+type MergeResult struct {
+	// Merged is the number of accounts copied into the store
+	Merged int
+	// Skipped counts source accounts whose address the store already had,
+	// archived accounts included
+	Skipped int
+	// SharedMnemonic counts source accounts that were not copied because the
+	// store, or an earlier source account, already has their mnemonic under
+	// another address that is not their HD sibling, see SharedMnemonicError
+	SharedMnemonic int
+	// Archived counts the archived source accounts, which are never copied
+	Archived int
+	// Failed lists the source accounts that failed Account.Verify and were
+	// not copied, in source order
+	Failed []VerificationFailure
+}
+
+// MergeFrom copies the active accounts of src into s, keeping their labels,
+// creation times and derivation paths; archived source accounts are left out.
+// Each account is checked with Account.Verify first, so a corrupted source row
+// is reported instead of copied. The accounts that pass are saved with
+// SaveAccounts in a single transaction, skipping addresses s already has and,
+// unless s allows shared mnemonics, accounts whose mnemonic s already has
+// other than as HD siblings, so a failure leaves s unchanged. src and s may use
+// different passwords and settings.
+func (s *AccountStore) MergeFrom(src *AccountStore) (*MergeResult, error) {
+	return s.MergeFromContext(context.Background(), src)
+}
+
+// MergeFromContext is like MergeFrom but honors cancellation and deadlines from ctx
+func (s *AccountStore) MergeFromContext(ctx context.Context, src *AccountStore) (*MergeResult, error) {
+	if src == s {
+		return nil, fmt.Errorf("cannot merge a store into itself")
+	}
+
+	accounts, err := src.getAccounts(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source accounts: %w", err)
+	}
+
+	archived, err := src.ListArchivedContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archived source accounts: %w", err)
+	}
+
+	result := &MergeResult{Archived: len(archived)}
+	verified := make([]*Account, 0, len(accounts))
+	for _, account := range accounts {
+		if err := account.Verify(); err != nil {
+			result.Failed = append(result.Failed, VerificationFailure{Address: account.Address, Err: err})
+			continue
+		}
+		verified = append(verified, account)
+	}
+	if len(verified) == 0 {
+		return result, nil
+	}
+
+	inserted, err := s.SaveAccountsContext(ctx, verified)
+	var shared *SharedMnemonicError
+	if errors.As(err, &shared) {
+		result.SharedMnemonic = len(shared.Addresses)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to merge accounts: %w", err)
+	}
+	result.Merged = inserted
+	result.Skipped = len(verified) - inserted - result.SharedMnemonic
+	return result, nil
+}
@@ -0,0 +1,77 @@
+package wallet
+
+import "testing"
+
+func TestMergeFromCountsEachOutcome(t *testing.T) {
+	hd, err := DeriveAccountsFromMnemonic(testMnemonic, DefaultCoinType, 2)
+	if err != nil {
+		t.Fatalf("DeriveAccountsFromMnemonic() error = %v", err)
+	}
+	// The phrase with a passphrase derives another address at the same path
+	withPassphrase, err := ImportAccount(testMnemonic, "TREZOR", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+	fresh := []*Account{newTestAccount(t), newTestAccount(t), newTestAccount(t)}
+
+	srcConfig := testConfig()
+	srcConfig.AllowSharedMnemonics = true
+	src := openTestStore(t, t.TempDir(), srcConfig)
+	if _, err := src.SaveAccounts(append(append(hd, withPassphrase), fresh...)); err != nil {
+		t.Fatalf("SaveAccounts() error = %v", err)
+	}
+	if err := src.DeleteAccount(fresh[2].Address); err != nil {
+		t.Fatalf("DeleteAccount() error = %v", err)
+	}
+
+	dst := newTestStore(t)
+	if _, err := dst.SaveAccount(hd[0]); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+
+	result, err := dst.MergeFrom(src)
+	if err != nil {
+		t.Fatalf("MergeFrom() error = %v", err)
+	}
+	want := MergeResult{Merged: 3, Skipped: 1, SharedMnemonic: 1, Archived: 1}
+	if result.Merged != want.Merged || result.Skipped != want.Skipped ||
+		result.SharedMnemonic != want.SharedMnemonic || result.Archived != want.Archived || len(result.Failed) != 0 {
+		t.Errorf("MergeFrom() = %+v, want %+v", *result, want)
+	}
+
+	if count, err := dst.CountAccounts(); err != nil || count != 4 {
+		t.Errorf("CountAccounts() = %d, %v, want 4", count, err)
+	}
+}
+
+func TestMergeFromKeepsHDSiblings(t *testing.T) {
+	hd, err := DeriveAccountsFromMnemonic(testMnemonic, DefaultCoinType, 3)
+	if err != nil {
+		t.Fatalf("DeriveAccountsFromMnemonic() error = %v", err)
+	}
+	src := newTestStore(t)
+	if _, err := src.SaveAccounts(hd); err != nil {
+		t.Fatalf("SaveAccounts() error = %v", err)
+	}
+
+	dst := newTestStore(t)
+	result, err := dst.MergeFrom(src)
+	if err != nil {
+		t.Fatalf("MergeFrom() error = %v", err)
+	}
+	if result.Merged != 3 || result.Skipped != 0 || result.SharedMnemonic != 0 {
+		t.Errorf("MergeFrom() = %+v, want all 3 accounts merged", *result)
+	}
+	for _, account := range hd {
+		if _, err := dst.GetAccountByAddress(account.Address); err != nil {
+			t.Errorf("GetAccountByAddress(%s) after merging error = %v", account.DerivationPath, err)
+		}
+	}
+}
+
+func TestMergeFromItself(t *testing.T) {
+	store := newTestStore(t)
+	if _, err := store.MergeFrom(store); err == nil {
+		t.Error("MergeFrom() of a store into itself succeeded")
+	}
+}
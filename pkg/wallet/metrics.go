@@ -0,0 +1,97 @@
+package wallet
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Store operations timed by Metrics, used as the "operation" label
+const (
+	opSaveAccount     = "save_account"
+	opSaveAccounts    = "save_accounts"
+	opGetAccounts     = "get_accounts"
+	opGetAccountsPage = "get_accounts_page"
+	opRotateAccount   = "rotate_account"
+)
+
+// Metrics holds the Prometheus collectors an AccountStore reports to when set
+// in StoreConfig.Metrics. A Metrics value should be used by one store at a time,
+// since the account total it exports is tracked per store.
+type Metrics struct {
+	accountsSaved prometheus.Counter
+	accounts      prometheus.Gauge
+	durations     *prometheus.HistogramVec
+}
+
+// NewMetrics creates the store collectors and registers them with reg
+func NewMetrics(reg prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		accountsSaved: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "sei_wallet",
+			Name:      "accounts_saved_total",
+			Help:      "Number of accounts inserted into the store.",
+		}),
+		accounts: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "sei_wallet",
+			Name:      "accounts",
+			Help:      "Number of accounts currently in the store.",
+		}),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "sei_wallet",
+			Name:      "operation_duration_seconds",
+			Help:      "Duration of store operations.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 14),
+		}, []string{"operation"}),
+	}
+
+	for _, c := range []prometheus.Collector{m.accountsSaved, m.accounts, m.durations} {
+		if err := reg.Register(c); err != nil {
+			return nil, fmt.Errorf("failed to register store metrics: %w", err)
+		}
+	}
+
+	return m, nil
+}
+
+// observe records how long operation took since start. It is a no-op on a nil Metrics.
+func (m *Metrics) observe(operation string, start time.Time) {
+	if m == nil {
+		return
+	}
+	m.durations.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// added records n newly inserted accounts. It is a no-op on a nil Metrics.
+func (m *Metrics) added(n int) {
+	if m == nil || n == 0 {
+		return
+	}
+	m.accountsSaved.Add(float64(n))
+	m.accounts.Add(float64(n))
+}
+
+// restored records an archived account made active again. It is a no-op on a nil Metrics.
+func (m *Metrics) restored() {
+	if m == nil {
+		return
+	}
+	m.accounts.Inc()
+}
+
+// removed records a deleted account. It is a no-op on a nil Metrics.
+func (m *Metrics) removed() {
+	if m == nil {
+		return
+	}
+	m.accounts.Dec()
+}
+
+// setTotal sets the exported account total. It is a no-op on a nil Metrics.
+func (m *Metrics) setTotal(n int) {
+	if m == nil {
+		return
+	}
+	m.accounts.Set(float64(n))
+}
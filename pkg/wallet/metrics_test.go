@@ -0,0 +1,89 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsTrackStoreChanges(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics, err := NewMetrics(reg)
+	if err != nil {
+		t.Fatalf("NewMetrics() error = %v", err)
+	}
+	if _, err := NewMetrics(reg); err == nil {
+		t.Error("NewMetrics() registered the collectors twice")
+	}
+
+	dir := t.TempDir()
+	seed := openTestStore(t, dir, testConfig())
+	existing := newTestAccount(t)
+	if _, err := seed.SaveAccount(existing); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+	seed.Close()
+
+	// Opening sets the gauge to the stored total
+	config := testConfig()
+	config.Metrics = metrics
+	store := openTestStore(t, dir, config)
+	checkMetric(t, "accounts after open", metrics.accounts, 1)
+
+	accounts := []*Account{newTestAccount(t), newTestAccount(t)}
+	if _, err := store.SaveAccounts(accounts); err != nil {
+		t.Fatalf("SaveAccounts() error = %v", err)
+	}
+	if _, err := store.SaveAccount(accounts[0]); err != nil {
+		t.Fatalf("SaveAccount() of a duplicate error = %v", err)
+	}
+	checkMetric(t, "accounts_saved_total", metrics.accountsSaved, 2)
+	checkMetric(t, "accounts after saving", metrics.accounts, 3)
+
+	if err := store.DeleteAccount(existing.Address); err != nil {
+		t.Fatalf("DeleteAccount() error = %v", err)
+	}
+	checkMetric(t, "accounts after archiving", metrics.accounts, 2)
+	if err := store.Restore(existing.Address); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	checkMetric(t, "accounts after restoring", metrics.accounts, 3)
+
+	// Purging an archived account leaves the active total alone
+	if err := store.DeleteAccount(existing.Address); err != nil {
+		t.Fatalf("DeleteAccount() error = %v", err)
+	}
+	if err := store.PurgeAccount(existing.Address); err != nil {
+		t.Fatalf("PurgeAccount() of an archived account error = %v", err)
+	}
+	checkMetric(t, "accounts after purging an archived account", metrics.accounts, 2)
+	if err := store.PurgeAccount(accounts[1].Address); err != nil {
+		t.Fatalf("PurgeAccount() error = %v", err)
+	}
+	checkMetric(t, "accounts after purging", metrics.accounts, 1)
+
+	if _, err := store.GetAccounts(); err != nil {
+		t.Fatalf("GetAccounts() error = %v", err)
+	}
+	// One series each for save_accounts, save_account and get_accounts
+	if got := testutil.CollectAndCount(metrics.durations); got != 3 {
+		t.Errorf("operation_duration_seconds has %d series, want 3", got)
+	}
+}
+
+// checkMetric fails the test unless collector holds the single value want
+func checkMetric(t *testing.T, name string, collector prometheus.Collector, want float64) {
+	t.Helper()
+	if got := testutil.ToFloat64(collector); got != want {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
+
+func TestNilMetricsIsANoOp(t *testing.T) {
+	var metrics *Metrics
+	metrics.added(1)
+	metrics.removed()
+	metrics.restored()
+	metrics.setTotal(3)
+}
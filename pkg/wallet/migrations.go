@@ -0,0 +1,323 @@
+package wallet
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/go-bip39"
+)
+
+// migration is a single, ordered schema change. Versions are tracked in
+// SQLite's PRAGMA user_version so every database knows which have been applied.
+type migration struct {
+	version     int
+	description string
+	apply       func(tx *sql.Tx) error
+}
+
+// migrations lists every schema change in order. Append new entries with the
+// next version number; never edit or reorder entries that have shipped.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "create accounts table",
+		apply: func(tx *sql.Tx) error {
+			_, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS accounts (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				address TEXT UNIQUE NOT NULL,
+				mnemonic TEXT NOT NULL,
+				public_key TEXT NOT NULL,
+				private_key TEXT NOT NULL,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			);
+			CREATE INDEX IF NOT EXISTS idx_accounts_address ON accounts(address);
+			`)
+			return err
+		},
+	},
+	{
+		version:     2,
+		description: "add account labels",
+		apply: func(tx *sql.Tx) error {
+			// Unversioned databases may already have the column
+			return addColumnIfMissing(tx, "accounts", "label", "TEXT")
+		},
+	},
+	{
+		version:     3,
+		description: "add archived flag for soft deletes",
+		apply: func(tx *sql.Tx) error {
+			return addColumnIfMissing(tx, "accounts", "archived", "BOOLEAN NOT NULL DEFAULT 0")
+		},
+	},
+	{
+		version:     4,
+		description: "create store settings table",
+		apply: func(tx *sql.Tx) error {
+			_, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS store_settings (
+				name TEXT PRIMARY KEY,
+				value BLOB NOT NULL
+			);
+			`)
+			return err
+		},
+	},
+	{
+		version:     5,
+		description: "add account derivation paths",
+		apply: func(tx *sql.Tx) error {
+			if err := addColumnIfMissing(tx, "accounts", "derivation_path", "TEXT"); err != nil {
+				return err
+			}
+			return backfillDerivationPaths(tx)
+		},
+	},
+	{
+		version:     6,
+		description: "add successor references for rotated accounts",
+		apply: func(tx *sql.Tx) error {
+			return addColumnIfMissing(tx, "accounts", "successor", "TEXT")
+		},
+	},
+	{
+		version:     7,
+		description: "add account key algorithms",
+		apply: func(tx *sql.Tx) error {
+			// Existing rows stay NULL and are read back as DefaultKeyAlgorithm
+			return addColumnIfMissing(tx, "accounts", "key_algorithm", "TEXT")
+		},
+	},
+	{
+		version:     8,
+		description: "add keyed mnemonic hashes",
+		apply: func(tx *sql.Tx) error {
+			// Existing rows are hashed before the first write, see mnemonic_hash.go
+			if err := addColumnIfMissing(tx, "accounts", "mnemonic_hash", "BLOB"); err != nil {
+				return err
+			}
+			_, err := tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_mnemonic_hash ON accounts(mnemonic_hash)")
+			return err
+		},
+	},
+	{
+		version:     9,
+		description: "key mnemonic hashes by derivation path",
+		apply: func(tx *sql.Tx) error {
+			// Hashes of the mnemonic alone are recomputed before the first write
+			_, err := tx.Exec("UPDATE accounts SET mnemonic_hash = NULL")
+			return err
+		},
+	},
+	{
+		version:     10,
+		description: "key mnemonic hashes by the phrase alone",
+		apply: func(tx *sql.Tx) error {
+			// Hashes keyed by the derivation path are recomputed before the first write
+			_, err := tx.Exec("UPDATE accounts SET mnemonic_hash = NULL")
+			return err
+		},
+	},
+}
+
+// LatestSchemaVersion is the schema version a fully migrated database has
+var LatestSchemaVersion = migrations[len(migrations)-1].version
+
+// ErrSchemaVersion is returned when a store opened with StoreConfig.ReadOnly
+// is not at LatestSchemaVersion, since it cannot be migrated
+var ErrSchemaVersion = errors.New("database schema version does not match this build")
+
+// migrate applies all pending migrations, each in its own transaction
+func (s *AccountStore) migrate() error {
+	current, err := s.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+
+		s.logger.Debug("applying schema migration", "version", m.version, "description", m.description)
+		tx, err := s.db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to begin migration %d: %w", m.version, err)
+		}
+
+		if err := m.apply(tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.description, err)
+		}
+
+		// PRAGMA does not accept bound parameters; the version is a trusted int
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", m.version)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record schema version %d: %w", m.version, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", m.version, err)
+		}
+	}
+
+	return nil
+}
+
+// checkSchemaVersion refuses a read-only store whose schema is not the one this
+// build reads: a newer one comes from a later release, and an older one would
+// need migrations that cannot be applied without writing
+func (s *AccountStore) checkSchemaVersion() error {
+	version, err := s.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	switch {
+	case version > LatestSchemaVersion:
+		return fmt.Errorf("%s has schema version %d, newer than the %d this build supports: %w",
+			s.dbPath, version, LatestSchemaVersion, ErrSchemaVersion)
+	case version < LatestSchemaVersion:
+		return fmt.Errorf("%s has schema version %d; open it once for writing to upgrade it to %d: %w",
+			s.dbPath, version, LatestSchemaVersion, ErrSchemaVersion)
+	}
+	return nil
+}
+
+// SchemaVersion returns the schema version recorded in the database
+func (s *AccountStore) SchemaVersion() (int, error) {
+	var version int
+	if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// legacyPathSearch is how many address indices backfillDerivationPaths tries
+// for each coin type
+const legacyPathSearch = 10
+
+// backfillDerivationPaths records the derivation path of accounts stored before
+// paths were, by deriving their mnemonic again at the standard paths for the
+// known coin types and the first legacyPathSearch address indices and matching
+// the public key. Accounts that match none, such as those derived with a BIP39
+// passphrase, keep a NULL path and are read back with an unknown one.
+func backfillDerivationPaths(tx *sql.Tx) error {
+	rows, err := tx.Query("SELECT id, mnemonic, public_key FROM accounts WHERE derivation_path IS NULL AND mnemonic != ''")
+	if err != nil {
+		return fmt.Errorf("failed to query accounts without a derivation path: %w", err)
+	}
+	type legacyRow struct {
+		id               int64
+		mnemonic, pubKey string
+	}
+	var legacy []legacyRow
+	for rows.Next() {
+		var row legacyRow
+		if err := rows.Scan(&row.id, &row.mnemonic, &row.pubKey); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan account: %w", err)
+		}
+		legacy = append(legacy, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate accounts: %w", err)
+	}
+
+	coinTypes := legacyCoinTypes()
+	for _, row := range legacy {
+		path, err := findDerivationPath(row.mnemonic, row.pubKey, coinTypes)
+		if err != nil {
+			return err
+		}
+		if path == "" {
+			continue
+		}
+		if _, err := tx.Exec("UPDATE accounts SET derivation_path = ? WHERE id = ?", path, row.id); err != nil {
+			return fmt.Errorf("failed to record derivation path: %w", err)
+		}
+	}
+	return nil
+}
+
+// legacyCoinTypes returns the coin types backfillDerivationPaths tries:
+// DefaultCoinType first, then those of the known chains and Ethereum's 60
+func legacyCoinTypes() []uint32 {
+	coinTypes := []uint32{DefaultCoinType}
+	for _, name := range ChainNames() {
+		if chain := knownChains[name]; !slices.Contains(coinTypes, chain.CoinType) {
+			coinTypes = append(coinTypes, chain.CoinType)
+		}
+	}
+	if !slices.Contains(coinTypes, 60) {
+		coinTypes = append(coinTypes, 60)
+	}
+	return coinTypes
+}
+
+// findDerivationPath returns the standard secp256k1 path for one of coinTypes
+// at which mnemonic derives pubKey without a passphrase, or "" if none does
+func findDerivationPath(mnemonic, pubKey string, coinTypes []uint32) (string, error) {
+	master, ch := hd.ComputeMastersFromSeed(bip39.NewSeed(mnemonic, ""))
+	for _, coinType := range coinTypes {
+		for index := uint32(0); index < legacyPathSearch; index++ {
+			path := DerivationPath(coinType, index)
+			derived, err := deriveAccount(AlgoSecp256k1, mnemonic, master, ch, path)
+			if err != nil {
+				return "", err
+			}
+			if derived.PubKey == pubKey {
+				return path, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// addColumnIfMissing adds a column to a table unless it already exists
+func addColumnIfMissing(tx *sql.Tx, table, column, definition string) error {
+	exists, err := hasColumn(tx, table, column)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add %s column: %w", column, err)
+	}
+	return nil
+}
+
+// hasColumn reports whether the given table has a column with the given name
+func hasColumn(tx *sql.Tx, table, column string) (bool, error) {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return false, fmt.Errorf("failed to scan table info: %w", err)
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+
+	return false, rows.Err()
+}
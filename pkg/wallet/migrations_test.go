@@ -0,0 +1,136 @@
+package wallet
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrateUnversionedDatabase(t *testing.T) {
+	dir := t.TempDir()
+	config := testConfig()
+	account := newTestAccount(t)
+	ethereum, err := ImportAccountsWithAlgorithm(testMnemonic, "", 60, AlgoSecp256k1, 3, 1)
+	if err != nil {
+		t.Fatalf("ImportAccountsWithAlgorithm() error = %v", err)
+	}
+	withPassphrase, err := ImportAccount(testMnemonic, "extra words", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+
+	// Write a database as it was before schema versions, at user_version 0
+	old := &AccountStore{
+		dbPath:   filepath.Join(dir, DBFileName),
+		password: testPassword,
+		config:   config,
+		logger:   config.Logger,
+	}
+	if err := old.openDB(); err != nil {
+		t.Fatalf("openDB() error = %v", err)
+	}
+	if _, err := old.db.Exec(`
+		CREATE TABLE accounts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			address TEXT UNIQUE NOT NULL,
+			mnemonic TEXT NOT NULL,
+			public_key TEXT NOT NULL,
+			private_key TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`); err != nil {
+		t.Fatalf("failed to create the old schema: %v", err)
+	}
+	for _, a := range []*Account{account, ethereum[0], withPassphrase} {
+		if _, err := old.db.Exec("INSERT INTO accounts (address, mnemonic, public_key, private_key) VALUES (?, ?, ?, ?)",
+			a.Address, a.Mnemonic, a.PubKey, a.PrivateKey); err != nil {
+			t.Fatalf("failed to insert into the old schema: %v", err)
+		}
+	}
+	if version, err := old.SchemaVersion(); err != nil || version != 0 {
+		t.Fatalf("SchemaVersion() of the old database = %d, %v, want 0", version, err)
+	}
+	old.Close()
+
+	store, err := NewAccountStore(dir, testPassword)
+	if err != nil {
+		t.Fatalf("NewAccountStore() error = %v", err)
+	}
+	defer store.Close()
+	if version, err := store.SchemaVersion(); err != nil || version != LatestSchemaVersion {
+		t.Errorf("SchemaVersion() = %d, %v, want %d", version, err, LatestSchemaVersion)
+	}
+
+	got, err := store.GetAccountByAddress(account.Address)
+	if err != nil {
+		t.Fatalf("GetAccountByAddress() error = %v", err)
+	}
+	if got.Mnemonic != account.Mnemonic || got.DerivationPath != DefaultDerivationPath || got.Label != "" {
+		t.Errorf("migrated account = %+v, want the old row with default path and no label", got)
+	}
+
+	// The path is recovered for other coin types and indices, and left unknown
+	// when no standard path without a passphrase matches
+	for address, want := range map[string]string{ethereum[0].Address: "m/44'/60'/0'/0/3", withPassphrase.Address: ""} {
+		got, err := store.GetAccountByAddress(address)
+		if err != nil {
+			t.Fatalf("GetAccountByAddress() error = %v", err)
+		}
+		if got.DerivationPath != want {
+			t.Errorf("migrated account %s has path %q, want %q", address, got.DerivationPath, want)
+		}
+	}
+	if _, err := store.SaveAccount(newTestAccount(t)); err != nil {
+		t.Errorf("SaveAccount() after migration error = %v", err)
+	}
+}
+
+func TestMigrationsKeepKeyAlgorithms(t *testing.T) {
+	dir := t.TempDir()
+	store := openTestStore(t, dir, testConfig())
+	var accounts []*Account
+	for _, algo := range keyAlgorithms {
+		account, err := GenerateAccountWithAlgorithm(algo, DefaultCoinType, DefaultMnemonicWords, "")
+		if err != nil {
+			t.Fatalf("GenerateAccountWithAlgorithm(%s) error = %v", algo, err)
+		}
+		accounts = append(accounts, account)
+	}
+	if _, err := store.SaveAccounts(accounts); err != nil {
+		t.Fatalf("SaveAccounts() error = %v", err)
+	}
+
+	// Run migration 7 and those after it again over the stored rows
+	if _, err := store.db.Exec("PRAGMA user_version = 6"); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+	store = openTestStore(t, dir, testConfig())
+	if version, err := store.SchemaVersion(); err != nil || version != LatestSchemaVersion {
+		t.Fatalf("SchemaVersion() = %d, %v, want %d", version, err, LatestSchemaVersion)
+	}
+
+	msg := []byte("migrated")
+	for _, want := range accounts {
+		got, err := store.GetAccountByAddress(want.Address)
+		if err != nil {
+			t.Fatalf("GetAccountByAddress() error = %v", err)
+		}
+		if got.KeyAlgorithm != want.KeyAlgorithm {
+			t.Errorf("account %s has key algorithm %s after migrating, want %s", want.Address, got.KeyAlgorithm, want.KeyAlgorithm)
+		}
+		sig, err := got.Sign(msg)
+		if err != nil {
+			t.Fatalf("Sign() with the %s key error = %v", want.KeyAlgorithm, err)
+		}
+		if !want.VerifySignature(msg, sig) {
+			t.Errorf("the migrated %s key signs differently", want.KeyAlgorithm)
+		}
+	}
+
+	// Rows from before the column read back as secp256k1
+	if _, err := store.db.Exec("UPDATE accounts SET key_algorithm = NULL WHERE address = ?", accounts[0].Address); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.GetAccountByAddress(accounts[0].Address); err != nil || got.KeyAlgorithm != DefaultKeyAlgorithm {
+		t.Errorf("account without a key algorithm = %+v, %v, want %s", got, err, DefaultKeyAlgorithm)
+	}
+}
@@ -0,0 +1,67 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cosmos/go-bip39"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrWeakMnemonic is returned by CheckMnemonicStrength for phrases that pass the
+// BIP39 checksum but follow an obvious pattern, which usually means a typo or a
+// documentation example rather than a real backup
+var ErrWeakMnemonic = errors.New("mnemonic follows an obvious pattern")
+
+// MnemonicBits returns the entropy, in bits, encoded by the account's mnemonic
+func (a *Account) MnemonicBits() (int, error) {
+	if a.Mnemonic == "" {
+		return 0, fmt.Errorf("account %s has no mnemonic", a.Address)
+	}
+	return EntropyForWords(len(strings.Fields(a.Mnemonic)))
+}
+
+// CheckMnemonicStrength rejects mnemonics whose words are all the same or run
+// consecutively through the BIP39 wordlist. The last word carries the checksum
+// and is ignored, so test vectors such as "abandon ... abandon about" are caught.
+func CheckMnemonicStrength(mnemonic string) error {
+	words := strings.Fields(mnemonic)
+	if len(words) < 3 {
+		return nil
+	}
+	words = words[:len(words)-1]
+
+	indices := make([]int, len(words))
+	for i, word := range words {
+		index, ok := bip39.ReverseWordMap[word]
+		if !ok {
+			return fmt.Errorf("invalid mnemonic: %q is not in the BIP39 wordlist", word)
+		}
+		indices[i] = index
+	}
+
+	same, ascending, descending := true, true, true
+	for i := 1; i < len(indices); i++ {
+		same = same && indices[i] == indices[0]
+		ascending = ascending && indices[i] == indices[i-1]+1
+		descending = descending && indices[i] == indices[i-1]-1
+	}
+
+	switch {
+	case same:
+		return fmt.Errorf("%w: every word is %q", ErrWeakMnemonic, words[0])
+	case ascending, descending:
+		return fmt.Errorf("%w: the words are consecutive in the BIP39 wordlist", ErrWeakMnemonic)
+	}
+	return nil
+}
+
+// NormalizeMnemonic puts a mnemonic typed or pasted by a user into the form
+// BIP39 expects: lowercase, NFKD-normalized, with words separated by single
+// spaces. The seed is computed from the exact string, so a stray tab, double
+// space or capital letter would otherwise derive a different wallet or fail
+// validation.
+func NormalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(norm.NFKD.String(strings.ToLower(mnemonic))), " ")
+}
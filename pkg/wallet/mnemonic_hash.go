@@ -0,0 +1,265 @@
+package wallet
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/go-bip39"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Mnemonic hashes. Every stored mnemonic also gets an HMAC-SHA256 of its
+// normalized form in the mnemonic_hash column, which has a unique index, so a
+// phrase that is already stored is found without comparing plaintext. Only the
+// first account of a mnemonic holds its hash. Saving another account of it is
+// refused with a SharedMnemonicError, which catches a re-import whatever its
+// derivation path or BIP39 passphrase, except for the HD siblings of the
+// holder, see isHDSibling. With StoreConfig.SecretsPassphrase the HMAC key is
+// derived from the secrets key and never stored, so the hashes are no easier
+// to attack than the sealed mnemonics. Otherwise a random key is kept in
+// store_settings next to the plaintext mnemonics it protects.
+const (
+	mnemonicHashKeySetting = "mnemonic_hash_key"
+	mnemonicHashKeyInfo    = "sei-wallet mnemonic hash"
+	mnemonicHashKeyLen     = 32
+)
+
+// ErrMnemonicAlreadyStored is wrapped by SharedMnemonicError
+var ErrMnemonicAlreadyStored = errors.New("mnemonic already stored under another address")
+
+// SharedMnemonicError is returned by SaveAccount and SaveAccounts when accounts
+// were not saved because another stored account, or an earlier one in the
+// batch, has the same mnemonic and they are not HD siblings. SaveAccounts
+// still saves the rest of the batch. Set StoreConfig.AllowSharedMnemonics to
+// store such accounts, for example wallets of one mnemonic with different
+// BIP39 passphrases.
+type SharedMnemonicError struct {
+	// Addresses lists the accounts that were not saved, in input order
+	Addresses []string
+}
+
+// Error implements error
+func (e *SharedMnemonicError) Error() string {
+	if len(e.Addresses) == 1 {
+		return fmt.Sprintf("account %s not saved: %v", e.Addresses[0], ErrMnemonicAlreadyStored)
+	}
+	return fmt.Sprintf("%d accounts not saved: %v", len(e.Addresses), ErrMnemonicAlreadyStored)
+}
+
+// Unwrap returns ErrMnemonicAlreadyStored
+func (e *SharedMnemonicError) Unwrap() error {
+	return ErrMnemonicAlreadyStored
+}
+
+// rowQuerier is implemented by both *sql.DB and *sql.Tx
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// prepareMnemonicHashes loads or creates the key for mnemonic hashes and fills
+// in the hash of accounts stored without one, such as those saved before the
+// column existed. It runs before the first write of an opened store rather
+// than on open, so read-only use never writes to the database. A store whose
+// secrets are locked has no key, but refuses to save accounts anyway, see
+// ErrSecretsLocked. The caller must hold s.mu exclusively.
+func (s *AccountStore) prepareMnemonicHashes(ctx context.Context) (err error) {
+	if s.mnemonicHashKey != nil || s.secretsLocked {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			s.mnemonicHashKey = nil
+		}
+	}()
+
+	if s.mnemonicHashKey, err = s.loadMnemonicHashKey(tx); err != nil {
+		return err
+	}
+	hashed, err := s.hashStoredMnemonics(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit mnemonic hashes: %w", err)
+	}
+	if hashed > 0 {
+		s.logger.Debug("hashed stored mnemonics", "accounts", hashed)
+	}
+	return nil
+}
+
+// loadMnemonicHashKey returns the HMAC key for mnemonic hashes, creating the
+// stored key on first use. The caller must make sure the secrets are not locked.
+func (s *AccountStore) loadMnemonicHashKey(tx *sql.Tx) ([]byte, error) {
+	if s.secretsKey != nil {
+		key := make([]byte, mnemonicHashKeyLen)
+		if _, err := io.ReadFull(hkdf.New(sha256.New, s.secretsKey, nil, []byte(mnemonicHashKeyInfo)), key); err != nil {
+			return nil, fmt.Errorf("failed to derive mnemonic hash key: %w", err)
+		}
+		return key, nil
+	}
+
+	var key []byte
+	err := tx.QueryRow("SELECT value FROM store_settings WHERE name = ?", mnemonicHashKeySetting).Scan(&key)
+	if err == nil {
+		return key, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to read mnemonic hash key: %w", err)
+	}
+
+	key = make([]byte, mnemonicHashKeyLen)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate mnemonic hash key: %w", err)
+	}
+	if _, err := tx.Exec("INSERT INTO store_settings (name, value) VALUES (?, ?)", mnemonicHashKeySetting, key); err != nil {
+		return nil, fmt.Errorf("failed to store mnemonic hash key: %w", err)
+	}
+	return key, nil
+}
+
+// hashStoredMnemonics sets the hash of every account with a mnemonic but no
+// hash yet and returns how many were updated. The first account of each
+// mnemonic holds its hash; the others, such as HD siblings or duplicates saved
+// with AllowSharedMnemonics, keep none until that one is purged.
+func (s *AccountStore) hashStoredMnemonics(ctx context.Context, tx *sql.Tx) (int, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT address, mnemonic FROM accounts WHERE mnemonic_hash IS NULL AND mnemonic != '' ORDER BY id")
+	if err != nil {
+		return 0, fmt.Errorf("failed to query unhashed mnemonics: %w", err)
+	}
+
+	type unhashed struct {
+		address, mnemonic string
+	}
+	var pending []unhashed
+	for rows.Next() {
+		var row unhashed
+		if err := rows.Scan(&row.address, &row.mnemonic); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan mnemonic: %w", err)
+		}
+		pending = append(pending, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating mnemonics: %w", err)
+	}
+
+	hashed := 0
+	for _, row := range pending {
+		mnemonic, err := s.openSecret(row.address, row.mnemonic)
+		if err != nil {
+			return 0, err
+		}
+		hash, holder, err := s.claimMnemonicHash(ctx, tx, mnemonic)
+		if err != nil {
+			return 0, err
+		}
+		if holder != nil {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE accounts SET mnemonic_hash = ? WHERE address = ?", hash, row.address); err != nil {
+			return 0, fmt.Errorf("failed to store mnemonic hash of account %s: %w", row.address, err)
+		}
+		hashed++
+	}
+	return hashed, nil
+}
+
+// mnemonicHolder is the stored account that holds a mnemonic's hash
+type mnemonicHolder struct {
+	address, path, pubKey string
+}
+
+// claimMnemonicHash returns the hash to store for a new account with the given
+// plaintext mnemonic. If another stored account already holds it, no hash is
+// returned and holder describes that account. Without a mnemonic or a key
+// there is nothing to hash.
+func (s *AccountStore) claimMnemonicHash(ctx context.Context, q rowQuerier, mnemonic string) (hash []byte, holder *mnemonicHolder, err error) {
+	if mnemonic == "" || s.mnemonicHashKey == nil {
+		return nil, nil, nil
+	}
+
+	mac := hmac.New(sha256.New, s.mnemonicHashKey)
+	mac.Write([]byte(NormalizeMnemonic(mnemonic)))
+	hash = mac.Sum(nil)
+
+	var held mnemonicHolder
+	err = q.QueryRowContext(ctx, "SELECT address, COALESCE(derivation_path, ''), public_key FROM accounts WHERE mnemonic_hash = ?", hash).
+		Scan(&held.address, &held.path, &held.pubKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return hash, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check mnemonic hash: %w", err)
+	}
+	return nil, &held, nil
+}
+
+// mnemonicHashForSave returns the hash to insert with account. refused reports
+// an account that must not be saved because its mnemonic is already stored,
+// it is not an HD sibling of the account holding it (see isHDSibling) and
+// StoreConfig.AllowSharedMnemonics is not set. batch holds the addresses
+// inserted earlier in the same SaveAccounts call, or is nil. The caller must
+// hold s.mu and have called prepareWrite.
+func (s *AccountStore) mnemonicHashForSave(ctx context.Context, q rowQuerier, account *Account, batch map[string]bool) (hash []byte, refused bool, err error) {
+	hash, holder, err := s.claimMnemonicHash(ctx, q, account.Mnemonic)
+	if err != nil || holder == nil {
+		return hash, false, err
+	}
+	sibling, err := isHDSibling(account, holder, batch[holder.address])
+	if err != nil {
+		return nil, false, err
+	}
+	if !sibling && !s.config.AllowSharedMnemonics {
+		s.logger.Debug("mnemonic already stored, not saving", "address", account.Address, "stored", holder.address)
+		return nil, true, nil
+	}
+	return nil, false, nil
+}
+
+// isHDSibling reports whether account, whose mnemonic holder already has, is
+// another address of the same HD wallet rather than a re-import of the
+// phrase. That is the case when both are derived from the mnemonic without a
+// BIP39 passphrase at different paths, which is checked by deriving them
+// again. The passphrase is not stored, so accounts derived with one cannot be
+// checked this way; they count as siblings only when sameBatch reports that
+// holder was saved in the same SaveAccounts call, as ImportAccounts returns
+// the indices of one wallet.
+func isHDSibling(account *Account, holder *mnemonicHolder, sameBatch bool) (bool, error) {
+	path := account.DerivationPath
+	if path == "" {
+		path = DefaultDerivationPath
+	}
+	if holder.path == "" || holder.path == path {
+		return false, nil
+	}
+	if sameBatch {
+		return true, nil
+	}
+
+	master, ch := hd.ComputeMastersFromSeed(bip39.NewSeed(NormalizeMnemonic(account.Mnemonic), ""))
+	for _, want := range []struct{ path, pubKey string }{{path, account.PubKey}, {holder.path, holder.pubKey}} {
+		derived, err := deriveAccount(AlgoSecp256k1, account.Mnemonic, master, ch, want.path)
+		if err != nil {
+			return false, err
+		}
+		if derived.PubKey != want.pubKey {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,225 @@
+package wallet
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSaveAccountsReportsSharedMnemonics(t *testing.T) {
+	hd, err := DeriveAccountsFromMnemonic(testMnemonic, DefaultCoinType, 3)
+	if err != nil {
+		t.Fatalf("DeriveAccountsFromMnemonic() error = %v", err)
+	}
+	// A BIP39 passphrase gives other addresses of the same phrase
+	withPassphrase, err := ImportAccountsWithAlgorithm(testMnemonic, "passphrase", DefaultCoinType, DefaultKeyAlgorithm, 2, 2)
+	if err != nil {
+		t.Fatalf("ImportAccountsWithAlgorithm() error = %v", err)
+	}
+	sibling, err := ImportAccountsWithAlgorithm(testMnemonic, "", DefaultCoinType, DefaultKeyAlgorithm, 4, 1)
+	if err != nil {
+		t.Fatalf("ImportAccountsWithAlgorithm() error = %v", err)
+	}
+	other := newTestAccount(t).Mnemonic
+	otherWallet, err := ImportAccountsWithAlgorithm(other, "passphrase", DefaultCoinType, DefaultKeyAlgorithm, 0, 3)
+	if err != nil {
+		t.Fatalf("ImportAccountsWithAlgorithm() error = %v", err)
+	}
+
+	stores := map[string]Store{
+		"sqlite": newTestStore(t),
+		"memory": NewMemoryStore(),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			// The address indices of one wallet are HD siblings
+			if inserted, err := store.SaveAccounts(hd); inserted != 3 || err != nil {
+				t.Fatalf("SaveAccounts() of an HD wallet = %d, %v, want 3, nil", inserted, err)
+			}
+
+			// A re-import is caught whatever its address index
+			inserted, err := store.SaveAccounts(withPassphrase)
+			var shared *SharedMnemonicError
+			if !errors.As(err, &shared) || !errors.Is(err, ErrMnemonicAlreadyStored) {
+				t.Fatalf("SaveAccounts() error = %v, want a SharedMnemonicError", err)
+			}
+			if inserted != 0 || len(shared.Addresses) != 2 {
+				t.Errorf("SaveAccounts() of a passphrase wallet inserted %d and refused %v, want 0 and both", inserted, shared.Addresses)
+			}
+
+			// A later index derived without a passphrase is checked to be a sibling
+			if ok, err := store.SaveAccount(sibling[0]); !ok || err != nil {
+				t.Errorf("SaveAccount() of index 4 = %v, %v, want true, nil", ok, err)
+			}
+
+			// Passphrase siblings cannot be checked, so only one batch of them is saved
+			if inserted, err := store.SaveAccounts(otherWallet[:2]); inserted != 2 || err != nil {
+				t.Errorf("SaveAccounts() of a passphrase HD wallet = %d, %v, want 2, nil", inserted, err)
+			}
+			if _, err := store.SaveAccount(otherWallet[2]); !errors.Is(err, ErrMnemonicAlreadyStored) {
+				t.Errorf("SaveAccount() of a later passphrase index error = %v, want ErrMnemonicAlreadyStored", err)
+			}
+
+			// Saving a stored account again is an ordinary duplicate
+			if ok, err := store.SaveAccount(hd[0]); ok || err != nil {
+				t.Errorf("SaveAccount() of a stored address = %v, %v, want false, nil", ok, err)
+			}
+		})
+	}
+}
+
+func TestAllowSharedMnemonics(t *testing.T) {
+	plain, err := ImportAccount(testMnemonic, "", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+	withPassphrase, err := ImportAccount(testMnemonic, "passphrase", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() with passphrase error = %v", err)
+	}
+
+	config := testConfig()
+	config.AllowSharedMnemonics = true
+	store := openTestStore(t, t.TempDir(), config)
+	inserted, err := store.SaveAccounts([]*Account{plain, withPassphrase})
+	if err != nil || inserted != 2 {
+		t.Errorf("SaveAccounts() = %d, %v, want 2, nil", inserted, err)
+	}
+}
+
+func TestPurgeHandsOverMnemonicHash(t *testing.T) {
+	plain, err := ImportAccount(testMnemonic, "", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+	var passphrases []*Account
+	for _, passphrase := range []string{"first", "second"} {
+		account, err := ImportAccount(testMnemonic, passphrase, DefaultCoinType)
+		if err != nil {
+			t.Fatalf("ImportAccount() with passphrase error = %v", err)
+		}
+		passphrases = append(passphrases, account)
+	}
+
+	dir := t.TempDir()
+	config := testConfig()
+	config.AllowSharedMnemonics = true
+	store := openTestStore(t, dir, config)
+	if _, err := store.SaveAccounts([]*Account{plain, passphrases[0]}); err != nil {
+		t.Fatalf("SaveAccounts() error = %v", err)
+	}
+	if err := store.PurgeAccount(plain.Address); err != nil {
+		t.Fatalf("PurgeAccount() error = %v", err)
+	}
+	var hashed int
+	store.db.QueryRow("SELECT COUNT(mnemonic_hash) FROM accounts").Scan(&hashed)
+	if hashed != 1 {
+		t.Errorf("%d accounts hashed after purging the hashed one, want 1", hashed)
+	}
+	store.Close()
+
+	store = openTestStore(t, dir, testConfig())
+	if _, err := store.SaveAccount(passphrases[1]); !errors.Is(err, ErrMnemonicAlreadyStored) {
+		t.Errorf("SaveAccount() after the purge error = %v, want ErrMnemonicAlreadyStored", err)
+	}
+}
+
+func TestMnemonicHashesBackfilledOnFirstWrite(t *testing.T) {
+	dir := t.TempDir()
+	store := openTestStore(t, dir, testConfig())
+	account := newTestAccount(t)
+	if _, err := store.SaveAccount(account); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+	// Simulate a database from before mnemonic hashes
+	if _, err := store.db.Exec("UPDATE accounts SET mnemonic_hash = NULL"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.db.Exec("DELETE FROM store_settings WHERE name = ?", mnemonicHashKeySetting); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	store = openTestStore(t, dir, testConfig())
+	if _, err := store.GetAccounts(); err != nil {
+		t.Fatalf("GetAccounts() error = %v", err)
+	}
+	var hashed, keys int
+	store.db.QueryRow("SELECT COUNT(mnemonic_hash) FROM accounts").Scan(&hashed)
+	store.db.QueryRow("SELECT COUNT(*) FROM store_settings WHERE name = ?", mnemonicHashKeySetting).Scan(&keys)
+	if hashed != 0 || keys != 0 {
+		t.Fatalf("opening and reading wrote %d hashes and %d keys, want none", hashed, keys)
+	}
+
+	again, err := ImportAccount(account.Mnemonic, "passphrase", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+	if _, err := store.SaveAccount(again); !errors.Is(err, ErrMnemonicAlreadyStored) {
+		t.Errorf("SaveAccount() after backfill error = %v, want ErrMnemonicAlreadyStored", err)
+	}
+	store.db.QueryRow("SELECT COUNT(mnemonic_hash) FROM accounts").Scan(&hashed)
+	if hashed != 1 {
+		t.Errorf("%d accounts hashed after the first write, want 1", hashed)
+	}
+}
+
+func TestMnemonicHashesRekeyedWithSecretsPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	store := openTestStore(t, dir, testConfig())
+	account := newTestAccount(t)
+	if _, err := store.SaveAccount(account); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+	store.Close()
+
+	config := testConfig()
+	config.SecretsPassphrase = "secrets-passphrase"
+	store = openTestStore(t, dir, config)
+	again, err := ImportAccount(account.Mnemonic, "passphrase", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+	if _, err := store.SaveAccount(again); !errors.Is(err, ErrMnemonicAlreadyStored) {
+		t.Errorf("SaveAccount() on a sealed store error = %v, want ErrMnemonicAlreadyStored", err)
+	}
+
+	var keys int
+	store.db.QueryRow("SELECT COUNT(*) FROM store_settings WHERE name = ?", mnemonicHashKeySetting).Scan(&keys)
+	if keys != 0 {
+		t.Error("stored mnemonic hash key kept after enabling secrets encryption")
+	}
+}
+
+func TestMigrationRehashesMnemonicsByPhrase(t *testing.T) {
+	hd, err := DeriveAccountsFromMnemonic(testMnemonic, DefaultCoinType, 2)
+	if err != nil {
+		t.Fatalf("DeriveAccountsFromMnemonic() error = %v", err)
+	}
+	dir := t.TempDir()
+	store := openTestStore(t, dir, testConfig())
+	if _, err := store.SaveAccounts(hd); err != nil {
+		t.Fatalf("SaveAccounts() error = %v", err)
+	}
+	// Stand in for the hashes of the phrase and path that schema 9 kept
+	if _, err := store.db.Exec("UPDATE accounts SET mnemonic_hash = randomblob(32)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.db.Exec("PRAGMA user_version = 9"); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	store = openTestStore(t, dir, testConfig())
+	again, err := ImportAccount(testMnemonic, "passphrase", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+	if _, err := store.SaveAccount(again); !errors.Is(err, ErrMnemonicAlreadyStored) {
+		t.Errorf("SaveAccount() after the migration error = %v, want ErrMnemonicAlreadyStored", err)
+	}
+	var hashed int
+	store.db.QueryRow("SELECT COUNT(mnemonic_hash) FROM accounts").Scan(&hashed)
+	if hashed != 1 {
+		t.Errorf("%d accounts hashed after the migration, want 1", hashed)
+	}
+}
@@ -0,0 +1,95 @@
+package wallet
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cosmos/go-bip39"
+)
+
+func TestNormalizeMnemonic(t *testing.T) {
+	tests := []struct {
+		name     string
+		mnemonic string
+	}{
+		{"tabs", "abandon\tabandon\tabandon\tabandon\tabandon\tabandon\tabandon\tabandon\tabandon\tabandon\tabandon\tabout"},
+		{"double spaces", "abandon  abandon abandon  abandon abandon abandon abandon abandon abandon abandon abandon  about"},
+		{"trailing newline", testMnemonic + "\n"},
+		{"windows line ending", testMnemonic + "\r\n"},
+		{"surrounding whitespace", " \t" + testMnemonic + " \n\n"},
+		{"capitals", "Abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon ABOUT"},
+	}
+	want, err := ImportAccount(testMnemonic, "", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeMnemonic(tt.mnemonic); got != testMnemonic {
+				t.Errorf("NormalizeMnemonic() = %q, want %q", got, testMnemonic)
+			}
+			account, err := ImportAccount(tt.mnemonic, "", DefaultCoinType)
+			if err != nil {
+				t.Fatalf("ImportAccount() error = %v", err)
+			}
+			if account.Address != want.Address {
+				t.Errorf("ImportAccount() address = %s, want %s", account.Address, want.Address)
+			}
+		})
+	}
+}
+
+func TestCheckMnemonicStrength(t *testing.T) {
+	ascending := strings.Join(bip39.WordList[100:111], " ") + " zoo"
+	reversed := slices.Clone(bip39.WordList[200:211])
+	slices.Reverse(reversed)
+	descending := strings.Join(reversed, " ") + " zoo"
+
+	tests := []struct {
+		name     string
+		mnemonic string
+		wantWeak bool
+		wantErr  bool
+	}{
+		{"same words", testMnemonic, true, true},
+		{"same words, 24", strings.Repeat("zoo ", 23) + "vote", true, true},
+		{"ascending", ascending, true, true},
+		{"descending", descending, true, true},
+		{"real mnemonic", "legal winner thank year wave sausage worth useful legal winner thank yellow", false, false},
+		{"only the checksum word differs", strings.Repeat("abandon ", 10) + "ability about", false, false},
+		{"unknown word", "abandon abandonn " + strings.Repeat("abandon ", 9) + "about", false, true},
+		{"too short to judge", "abandon abandon", false, false},
+	}
+	for _, tt := range tests {
+		err := CheckMnemonicStrength(tt.mnemonic)
+		if (err != nil) != tt.wantErr || errors.Is(err, ErrWeakMnemonic) != tt.wantWeak {
+			t.Errorf("%s: CheckMnemonicStrength() error = %v, want error %v, weak %v", tt.name, err, tt.wantErr, tt.wantWeak)
+		}
+	}
+}
+
+func TestMnemonicBits(t *testing.T) {
+	tests := []struct {
+		words   int
+		want    int
+		wantErr bool
+	}{
+		{12, 128, false},
+		{15, 160, false},
+		{18, 192, false},
+		{21, 224, false},
+		{24, 256, false},
+		{0, 0, true},
+		{13, 0, true},
+	}
+	for _, tt := range tests {
+		account := &Account{Address: "sei1test", Mnemonic: strings.TrimSpace(strings.Repeat("abandon ", tt.words))}
+		got, err := account.MnemonicBits()
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("MnemonicBits() of %d words = %d, %v, want %d (error %v)", tt.words, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+)
+
+// Optimize rebuilds the indexes and refreshes the query planner statistics.
+// With vacuum set it also rewrites the database file to release the space left
+// by deleted accounts, which SQLite otherwise keeps for reuse and never returns
+// to the filesystem. Vacuuming needs free disk space of about the file's size
+// and blocks other writers while it runs.
+func (s *AccountStore) Optimize(vacuum bool) error {
+	return s.OptimizeContext(context.Background(), vacuum)
+}
+
+// OptimizeContext is like Optimize but honors cancellation and deadlines from ctx
+func (s *AccountStore) OptimizeContext(ctx context.Context, vacuum bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db == nil {
+		return fmt.Errorf("database connection not established")
+	}
+
+	statements := []string{"REINDEX", "ANALYZE"}
+	if vacuum {
+		// In WAL mode VACUUM writes the new pages to the log, so checkpoint and
+		// truncate it for the main file to actually shrink
+		statements = append(statements, "VACUUM", "PRAGMA wal_checkpoint(TRUNCATE)")
+	}
+
+	for _, statement := range statements {
+		s.logger.Debug("optimizing database", "statement", statement)
+		err := s.withBusyRetry(ctx, func() error {
+			_, err := s.db.ExecContext(ctx, statement)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to run %s: %w", statement, err)
+		}
+	}
+
+	return nil
+}
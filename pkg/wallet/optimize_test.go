@@ -0,0 +1,61 @@
+package wallet
+
+import (
+	"os"
+	"testing"
+)
+
+func TestOptimizeVacuumShrinksFile(t *testing.T) {
+	store := newTestStore(t)
+	accounts := make([]*Account, 200)
+	for i := range accounts {
+		accounts[i] = newTestAccount(t)
+	}
+	if _, err := store.SaveAccounts(accounts); err != nil {
+		t.Fatalf("SaveAccounts() error = %v", err)
+	}
+	for _, account := range accounts[1:] {
+		if err := store.PurgeAccount(account.Address); err != nil {
+			t.Fatalf("PurgeAccount() error = %v", err)
+		}
+	}
+
+	// Without vacuum the freed pages stay in the file
+	if err := store.Optimize(false); err != nil {
+		t.Fatalf("Optimize(false) error = %v", err)
+	}
+	before := fileSize(t, store.dbPath)
+
+	if err := store.Optimize(true); err != nil {
+		t.Fatalf("Optimize(true) error = %v", err)
+	}
+	if after := fileSize(t, store.dbPath); after >= before {
+		t.Errorf("database file is %d bytes after vacuuming, want less than %d", after, before)
+	}
+
+	got, err := store.GetAccountByAddress(accounts[0].Address)
+	if err != nil {
+		t.Fatalf("GetAccountByAddress() after vacuuming error = %v", err)
+	}
+	if got.PrivateKey != accounts[0].PrivateKey {
+		t.Error("GetAccountByAddress() after vacuuming returned another private key")
+	}
+}
+
+func TestOptimizeClosedStore(t *testing.T) {
+	store := newTestStore(t)
+	store.Close()
+	if err := store.Optimize(false); err == nil {
+		t.Error("Optimize() on a closed store succeeded, want an error")
+	}
+}
+
+// fileSize returns the size of the file at path
+func fileSize(t *testing.T, path string) int64 {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Size()
+}
@@ -0,0 +1,108 @@
+package wallet
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+const (
+	// DefaultFileMode is the permission mode of the database and exported files
+	DefaultFileMode os.FileMode = 0600
+	// DefaultDirMode is the permission mode of directories the store creates
+	DefaultDirMode os.FileMode = 0700
+)
+
+// validateModes checks that file and directory modes keep the owner's access
+// and grant nothing to other users; group access is allowed for shared setups
+func validateModes(fileMode, dirMode os.FileMode) error {
+	if fileMode&^os.ModePerm != 0 || fileMode&0600 != 0600 || fileMode&0007 != 0 {
+		return fmt.Errorf("invalid file mode %#o: must include owner read and write and grant nothing to others", fileMode)
+	}
+	if dirMode&^os.ModePerm != 0 || dirMode&0700 != 0700 || dirMode&0007 != 0 {
+		return fmt.Errorf("invalid directory mode %#o: must include owner access and grant nothing to others", dirMode)
+	}
+	return nil
+}
+
+// fileMode returns the configured file mode, or DefaultFileMode when unset
+func (c StoreConfig) fileMode() os.FileMode {
+	if c.FileMode == 0 {
+		return DefaultFileMode
+	}
+	return c.FileMode
+}
+
+// dirMode returns the configured directory mode, or DefaultDirMode when unset
+func (c StoreConfig) dirMode() os.FileMode {
+	if c.DirMode == 0 {
+		return DefaultDirMode
+	}
+	return c.DirMode
+}
+
+// ensureDir creates dir with the configured mode if it does not exist and
+// reports whether it did. The umask can strip bits from the mode given to
+// MkdirAll, so a new directory is chmodded to the exact mode. An existing
+// directory is left alone.
+func (s *AccountStore) ensureDir(dir string) (bool, error) {
+	info, err := os.Stat(dir)
+	if err == nil {
+		if !info.IsDir() {
+			return false, fmt.Errorf("%s is not a directory", dir)
+		}
+		return false, nil
+	}
+	if !os.IsNotExist(err) {
+		return false, err
+	}
+
+	mode := s.config.dirMode()
+	if err := os.MkdirAll(dir, mode); err != nil {
+		return false, err
+	}
+	return true, chmod(dir, mode)
+}
+
+// checkDirMode logs a warning if the existing directory dir is more permissive
+// than the configured mode, for example after being created by hand
+func (s *AccountStore) checkDirMode(dir string) {
+	if !unixPermissions {
+		return
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return
+	}
+
+	mode := s.config.dirMode()
+	if info.Mode().Perm()&^mode != 0 {
+		s.logger.Warn("storage directory is more permissive than configured", "path", dir,
+			"mode", fmt.Sprintf("%#o", info.Mode().Perm()), "expected", fmt.Sprintf("%#o", mode))
+	}
+}
+
+// writeFile writes data to path with the configured file mode. os.WriteFile
+// keeps the mode of an existing file and applies the umask to new ones, so the
+// mode is set explicitly afterwards.
+func (s *AccountStore) writeFile(path string, data []byte) error {
+	if err := os.WriteFile(path, data, s.config.fileMode()); err != nil {
+		return err
+	}
+	return chmod(path, s.config.fileMode())
+}
+
+// unixPermissions reports whether the platform has Unix permission bits.
+// On Windows os.Chmod only toggles the read-only attribute and directories
+// always report mode 0777, while access is governed by ACLs, which new files
+// inherit from the user's profile directory.
+const unixPermissions = runtime.GOOS != "windows"
+
+// chmod sets the permission mode of path, or does nothing on platforms
+// without Unix permission bits
+func chmod(path string, mode os.FileMode) error {
+	if !unixPermissions {
+		return nil
+	}
+	return os.Chmod(path, mode)
+}
@@ -0,0 +1,48 @@
+package wallet
+
+import (
+	"os"
+	"testing"
+)
+
+func TestValidateModes(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileMode os.FileMode
+		dirMode  os.FileMode
+		wantErr  bool
+	}{
+		{name: "defaults", fileMode: DefaultFileMode, dirMode: DefaultDirMode},
+		{name: "group access", fileMode: 0640, dirMode: 0750},
+		{name: "owner executable file", fileMode: 0700, dirMode: 0700},
+		{name: "file readable by others", fileMode: 0644, dirMode: 0700, wantErr: true},
+		{name: "file without owner write", fileMode: 0400, dirMode: 0700, wantErr: true},
+		{name: "file with setuid", fileMode: os.ModeSetuid | 0600, dirMode: 0700, wantErr: true},
+		{name: "directory listable by others", fileMode: 0600, dirMode: 0701, wantErr: true},
+		{name: "directory without owner execute", fileMode: 0600, dirMode: 0600, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateModes(tt.fileMode, tt.dirMode); (err != nil) != tt.wantErr {
+				t.Errorf("validateModes(%#o, %#o) error = %v, wantErr %v", tt.fileMode, tt.dirMode, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStoreConfigModeDefaults(t *testing.T) {
+	var config StoreConfig
+	if got := config.fileMode(); got != DefaultFileMode {
+		t.Errorf("fileMode() of an unset config = %#o, want %#o", got, DefaultFileMode)
+	}
+	if got := config.dirMode(); got != DefaultDirMode {
+		t.Errorf("dirMode() of an unset config = %#o, want %#o", got, DefaultDirMode)
+	}
+
+	config = testConfig()
+	config.FileMode = 0644
+	if _, err := NewAccountStoreWithConfig(t.TempDir(), testPassword, config); err == nil {
+		t.Error("NewAccountStoreWithConfig() with a world-readable file mode succeeded")
+	}
+}
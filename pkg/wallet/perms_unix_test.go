@@ -0,0 +1,47 @@
+//go:build !windows
+
+package wallet
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestStoreAppliesConfiguredModes(t *testing.T) {
+	// A restrictive umask must not strip the group bits asked for
+	old := syscall.Umask(0077)
+	t.Cleanup(func() { syscall.Umask(old) })
+
+	dir := filepath.Join(t.TempDir(), "accounts")
+	config := testConfig()
+	config.FileMode = 0640
+	config.DirMode = 0750
+	store := openTestStore(t, dir, config)
+	if _, err := store.SaveAccount(newTestAccount(t)); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+
+	checkFileMode(t, dir, 0750)
+	checkFileMode(t, store.dbPath, 0640)
+
+	backupPath := filepath.Join(dir, "backups", "backup.db")
+	if err := store.BackupTo(backupPath); err != nil {
+		t.Fatalf("BackupTo() error = %v", err)
+	}
+	checkFileMode(t, filepath.Dir(backupPath), 0750)
+	checkFileMode(t, backupPath, 0640)
+}
+
+// checkFileMode fails the test unless the file or directory at path has mode
+func checkFileMode(t *testing.T, path string, mode os.FileMode) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if got := info.Mode().Perm(); got != mode {
+		t.Errorf("mode of %s = %#o, want %#o", path, got, mode)
+	}
+}
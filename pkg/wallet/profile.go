@@ -0,0 +1,79 @@
+package wallet
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile names the database stored as DBFileName. Any other profile is
+// kept in its own file next to it, so separate account sets (for example
+// mainnet, testnet and dev) never mix.
+const DefaultProfile = "default"
+
+// profileFilePrefix and profileFileSuffix surround the name of a non-default
+// profile in its database file name
+const (
+	profileFilePrefix = "sei_accounts_"
+	profileFileSuffix = ".db"
+)
+
+// profileNamePattern restricts profile names to characters that are safe in a file name
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ValidateProfileName checks that name can be used as a profile. An empty name
+// selects DefaultProfile.
+func ValidateProfileName(name string) error {
+	if name == "" || profileNamePattern.MatchString(name) {
+		return nil
+	}
+	return fmt.Errorf("invalid profile name %q: use only letters, digits, '-' and '_'", name)
+}
+
+// ProfileFileName returns the database file name for a profile:
+// DBFileName for DefaultProfile (or an empty name) and sei_accounts_{name}.db otherwise
+func ProfileFileName(profile string) string {
+	if profile == "" || profile == DefaultProfile {
+		return DBFileName
+	}
+	return profileFilePrefix + profile + profileFileSuffix
+}
+
+// ListProfiles returns the names of the profiles with a database in dbDir,
+// sorted alphabetically. A missing directory has no profiles.
+func ListProfiles(dbDir string) ([]string, error) {
+	entries, err := os.ReadDir(dbDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read database directory: %w", err)
+	}
+
+	var profiles []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			continue
+		}
+		if name == DBFileName {
+			profiles = append(profiles, DefaultProfile)
+			continue
+		}
+
+		profile, ok := strings.CutPrefix(name, profileFilePrefix)
+		if !ok {
+			continue
+		}
+		profile, ok = strings.CutSuffix(profile, profileFileSuffix)
+		if !ok || !profileNamePattern.MatchString(profile) || profile == DefaultProfile {
+			continue
+		}
+		profiles = append(profiles, profile)
+	}
+
+	sort.Strings(profiles)
+	return profiles, nil
+}
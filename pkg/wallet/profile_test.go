@@ -0,0 +1,100 @@
+package wallet
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestValidateProfileName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: ""},
+		{name: DefaultProfile},
+		{name: "testnet"},
+		{name: "dev_2-b"},
+		{name: "../escape", wantErr: true},
+		{name: "a/b", wantErr: true},
+		{name: "main net", wantErr: true},
+		{name: "prod.db", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateProfileName(tt.name); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateProfileName(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestProfileFileName(t *testing.T) {
+	tests := []struct {
+		profile string
+		want    string
+	}{
+		{profile: "", want: DBFileName},
+		{profile: DefaultProfile, want: DBFileName},
+		{profile: "testnet", want: "sei_accounts_testnet.db"},
+	}
+
+	for _, tt := range tests {
+		if got := ProfileFileName(tt.profile); got != tt.want {
+			t.Errorf("ProfileFileName(%q) = %q, want %q", tt.profile, got, tt.want)
+		}
+	}
+}
+
+func TestListProfiles(t *testing.T) {
+	if profiles, err := ListProfiles(filepath.Join(t.TempDir(), "missing")); profiles != nil || err != nil {
+		t.Errorf("ListProfiles() of a missing directory = %v, %v, want nil, nil", profiles, err)
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{
+		DBFileName,
+		"sei_accounts_testnet.db",
+		"sei_accounts_dev.db",
+		"sei_accounts_default.db",     // would shadow the default profile
+		"sei_accounts_bad name.db",    // not a valid profile name
+		"sei_accounts_testnet.db-wal", // SQLite sidecar file
+		"notes.txt",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sei_accounts_dir.db"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := ListProfiles(dir)
+	if err != nil {
+		t.Fatalf("ListProfiles() error = %v", err)
+	}
+	if want := []string{DefaultProfile, "dev", "testnet"}; !slices.Equal(profiles, want) {
+		t.Errorf("ListProfiles() = %v, want %v", profiles, want)
+	}
+}
+
+func TestProfilesUseSeparateDatabases(t *testing.T) {
+	dir := t.TempDir()
+	config := testConfig()
+	config.Profile = "testnet"
+	testnet := openTestStore(t, dir, config)
+	if _, err := testnet.SaveAccount(newTestAccount(t)); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+
+	defaultStore := openTestStore(t, dir, testConfig())
+	if count, err := defaultStore.CountAccounts(); count != 0 || err != nil {
+		t.Errorf("CountAccounts() of the default profile = %d, %v, want 0, nil", count, err)
+	}
+	if count, err := testnet.CountAccounts(); count != 1 || err != nil {
+		t.Errorf("CountAccounts() of the testnet profile = %d, %v, want 1, nil", count, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sei_accounts_testnet.db")); err != nil {
+		t.Errorf("testnet profile database: %v", err)
+	}
+}
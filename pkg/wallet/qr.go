@@ -0,0 +1,23 @@
+package wallet
+
+import (
+	"fmt"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// AddressQR renders the account's address as a QR code drawn with Unicode
+// block characters, for scanning into a mobile wallet. Only the public address
+// is encoded, never the mnemonic or private key.
+func (a *Account) AddressQR() (string, error) {
+	if a.Address == "" {
+		return "", fmt.Errorf("account has no address")
+	}
+
+	qr, err := qrcode.New(a.Address, qrcode.Medium)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode address as QR code: %w", err)
+	}
+	// Each character covers two rows of modules, keeping the code small enough for a terminal
+	return qr.ToSmallString(false), nil
+}
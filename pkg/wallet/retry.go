@@ -0,0 +1,61 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	sqlite3 "github.com/mutecomm/go-sqlcipher/v4"
+)
+
+const (
+	// DefaultBusyRetries is how many times a write is retried after SQLITE_BUSY
+	DefaultBusyRetries = 5
+	// DefaultBusyRetryDelay is the wait before the first retry; it doubles on each attempt
+	DefaultBusyRetryDelay = 50 * time.Millisecond
+	// MinBusyRetryDelay is the shortest wait before a retry. Smaller delays,
+	// including zero, are raised to it so a busy database is not hammered.
+	MinBusyRetryDelay = 5 * time.Millisecond
+)
+
+// SetBusyRetry configures how writes that fail because another process holds
+// the database lock are retried. maxRetries of 0 disables retrying. A
+// baseDelay below MinBusyRetryDelay is raised to it.
+func (s *AccountStore) SetBusyRetry(maxRetries int, baseDelay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.busyRetries = maxRetries
+	s.busyRetryDelay = max(baseDelay, MinBusyRetryDelay)
+}
+
+// withBusyRetry runs fn, retrying with exponential backoff while it fails with
+// SQLITE_BUSY or SQLITE_LOCKED. The caller must hold s.mu.
+func (s *AccountStore) withBusyRetry(ctx context.Context, fn func() error) error {
+	delay := max(s.busyRetryDelay, MinBusyRetryDelay)
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || !isBusy(err) || attempt >= s.busyRetries {
+			return err
+		}
+
+		s.logger.Debug("database busy, retrying", "attempt", attempt+1, "delay", delay, "error", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// isBusy reports whether err was caused by another connection holding a lock
+func isBusy(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+}
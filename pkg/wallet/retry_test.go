@@ -0,0 +1,46 @@
+package wallet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sqlite3 "github.com/mutecomm/go-sqlcipher/v4"
+)
+
+func TestBusyRetryEnforcesMinimumDelay(t *testing.T) {
+	store := newTestStore(t)
+	store.SetBusyRetry(3, 0)
+
+	attempts := 0
+	began := time.Now()
+	err := store.withBusyRetry(context.Background(), func() error {
+		attempts++
+		return sqlite3.Error{Code: sqlite3.ErrBusy}
+	})
+	elapsed := time.Since(began)
+
+	if !isBusy(err) {
+		t.Errorf("withBusyRetry() error = %v, want the busy error after the last retry", err)
+	}
+	if attempts != 4 {
+		t.Errorf("fn ran %d times, want 4", attempts)
+	}
+	// The delay doubles from the minimum: 1, 2 and 4 times MinBusyRetryDelay
+	if want := 7 * MinBusyRetryDelay; elapsed < want {
+		t.Errorf("retries took %v, want at least %v", elapsed, want)
+	}
+}
+
+func TestBusyRetryStopsOnOtherErrors(t *testing.T) {
+	store := newTestStore(t)
+
+	attempts := 0
+	err := store.withBusyRetry(context.Background(), func() error {
+		attempts++
+		return sqlite3.Error{Code: sqlite3.ErrConstraint}
+	})
+	if err == nil || attempts != 1 {
+		t.Errorf("withBusyRetry() = %v after %d attempts, want the error after 1", err, attempts)
+	}
+}
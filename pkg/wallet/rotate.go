@@ -0,0 +1,126 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+)
+
+// RotateAccount replaces a compromised account: it generates a fresh account
+// with the same coin type, stores it with newLabel (or the old account's label
+// when newLabel is empty), and archives the old account with its Successor set
+// to the new address. Both changes happen in one transaction, so the old
+// account is never archived without a stored replacement. The new account is
+// returned with its secrets; it returns ErrAccountNotFound if no active account
+// has oldAddress.
+func (s *AccountStore) RotateAccount(oldAddress, newLabel string) (*Account, error) {
+	return s.RotateAccountContext(context.Background(), oldAddress, newLabel)
+}
+
+// RotateAccountContext is like RotateAccount but honors cancellation and deadlines from ctx
+func (s *AccountStore) RotateAccountContext(ctx context.Context, oldAddress, newLabel string) (*Account, error) {
+	if err := ValidateSeiAddress(oldAddress); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection not established")
+	}
+
+	defer s.config.Metrics.observe(opRotateAccount, time.Now())
+
+	var successor *Account
+	err := s.withBusyRetry(ctx, func() error {
+		if err := s.prepareWrite(ctx); err != nil {
+			return err
+		}
+		var err error
+		successor, err = s.rotateAccount(ctx, oldAddress, newLabel)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The new account replaces the archived one, so the total is unchanged
+	s.config.Metrics.added(1)
+	s.config.Metrics.removed()
+	s.logger.Debug("rotated account", "address", oldAddress, "successor", successor.Address)
+	return successor, nil
+}
+
+// rotateAccount performs a single RotateAccount transaction. The caller must hold s.mu.
+func (s *AccountStore) rotateAccount(ctx context.Context, oldAddress, newLabel string) (successor *Account, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	old, err := scanAccount(tx.QueryRowContext(ctx, "SELECT "+accountColumns+" FROM accounts WHERE address = ? AND NOT archived", oldAddress))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, oldAddress)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	if successor, err = newSuccessor(old, newLabel); err != nil {
+		return nil, err
+	}
+	mnemonicHash, holder, err := s.claimMnemonicHash(ctx, tx, successor.Mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	if holder != nil {
+		return nil, fmt.Errorf("successor of %s not saved: %w", oldAddress, ErrMnemonicAlreadyStored)
+	}
+	sealed, err := s.sealAccount(successor)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = tx.ExecContext(ctx, insertAccountSQL, insertAccountArgs(sealed, mnemonicHash)...); err != nil {
+		return nil, fmt.Errorf("failed to save successor account: %w", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, "UPDATE accounts SET archived = 1, successor = ? WHERE address = ?", successor.Address, oldAddress); err != nil {
+		return nil, fmt.Errorf("failed to archive account: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit rotation: %w", err)
+	}
+	return successor, nil
+}
+
+// newSuccessor generates the replacement for old with its key algorithm and the
+// coin type of its derivation path, falling back to DefaultCoinType for
+// accounts without one
+func newSuccessor(old *Account, newLabel string) (*Account, error) {
+	coinType := DefaultCoinType
+	if params, err := hd.NewParamsFromPath(old.DerivationPath); err == nil {
+		coinType = params.CoinType
+	}
+
+	successor, err := GenerateAccountWithAlgorithm(old.algorithm(), coinType, DefaultMnemonicWords, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate successor account: %w", err)
+	}
+
+	successor.Label = newLabel
+	if successor.Label == "" {
+		successor.Label = old.Label
+	}
+	return successor, nil
+}
@@ -0,0 +1,56 @@
+package wallet
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRotateAccount(t *testing.T) {
+	store := newTestStore(t)
+	old := newTestAccount(t)
+	old.Label = "hot wallet"
+	if _, err := store.SaveAccount(old); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+
+	// Make inserting the successor fail: the old account must stay active
+	if _, err := store.db.Exec(`CREATE TRIGGER refuse_insert BEFORE INSERT ON accounts
+		BEGIN SELECT RAISE(ABORT, 'insert refused'); END`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.RotateAccount(old.Address, ""); err == nil {
+		t.Fatal("RotateAccount() succeeded although the insert failed")
+	}
+	if archived, err := store.ListArchived(); err != nil || len(archived) != 0 {
+		t.Errorf("ListArchived() after a failed rotation = %d accounts, %v, want none", len(archived), err)
+	}
+	if count, err := store.CountAccounts(); err != nil || count != 1 {
+		t.Errorf("CountAccounts() after a failed rotation = %d, %v, want 1", count, err)
+	}
+	if _, err := store.db.Exec("DROP TRIGGER refuse_insert"); err != nil {
+		t.Fatal(err)
+	}
+
+	successor, err := store.RotateAccount(old.Address, "")
+	if err != nil {
+		t.Fatalf("RotateAccount() error = %v", err)
+	}
+	if successor.Label != old.Label || successor.Mnemonic == "" {
+		t.Errorf("successor = label %q with mnemonic %v, want label %q with a mnemonic", successor.Label, successor.Mnemonic != "", old.Label)
+	}
+	archived, err := store.ListArchived()
+	if err != nil || len(archived) != 1 {
+		t.Fatalf("ListArchived() = %d accounts, %v, want 1", len(archived), err)
+	}
+	if archived[0].Address != old.Address || archived[0].Successor != successor.Address {
+		t.Errorf("archived account %s has Successor %q, want %s with %s", archived[0].Address, archived[0].Successor, old.Address, successor.Address)
+	}
+	if _, err := store.GetAccountByAddress(successor.Address); err != nil {
+		t.Errorf("GetAccountByAddress() of the successor error = %v", err)
+	}
+
+	// An archived account cannot be rotated again
+	if _, err := store.RotateAccount(old.Address, ""); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("RotateAccount() of an archived account error = %v, want ErrAccountNotFound", err)
+	}
+}
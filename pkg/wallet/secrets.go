@@ -0,0 +1,350 @@
+package wallet
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Account secrets encryption. With StoreConfig.SecretsPassphrase set, a master
+// key is derived from the passphrase with scrypt and a random salt kept in the
+// store_settings table. Each account's mnemonic and private key are sealed with
+// AES-256-GCM under a key derived from the master key and the account's address
+// with HKDF, and the address is bound as additional data, so a sealed value
+// copied to another row does not decrypt.
+const (
+	// sealedPrefix marks a column value sealed by sealSecret; the rest is base64(nonce || ciphertext)
+	sealedPrefix = "sealed1:"
+
+	secretsSaltSetting  = "secrets_salt"
+	secretsCheckSetting = "secrets_check"
+	secretsCheckMessage = "sei-wallet secrets passphrase check"
+	secretsKeyInfo      = "sei-wallet account secrets "
+)
+
+// ErrWrongSecretsPassphrase is returned when StoreConfig.SecretsPassphrase does
+// not match the passphrase the store's secrets were first encrypted with
+var ErrWrongSecretsPassphrase = errors.New("incorrect secrets passphrase")
+
+// ErrSecretsLocked is returned when an account's secrets are encrypted but the
+// store was opened without StoreConfig.SecretsPassphrase, both when reading
+// them and when saving a new account, whose secrets would otherwise be stored
+// as plaintext
+var ErrSecretsLocked = errors.New("account secrets are encrypted; open the store with StoreConfig.SecretsPassphrase")
+
+// unlockSecrets derives the master key for the configured secrets passphrase
+// if the store's secrets are already encrypted. Otherwise it only keeps the
+// passphrase: encryption is enabled by prepareSecrets before the first write,
+// so opening a store with a passphrase only to read it never rewrites it.
+func (s *AccountStore) unlockSecrets(passphrase string) error {
+	key, err := storedSecretsKey(context.Background(), s.db, passphrase)
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		s.pendingSecretsPassphrase = passphrase
+		return nil
+	}
+	s.secretsKey = key
+	return nil
+}
+
+// storedSecretsKey derives the master key for passphrase from the stored salt
+// and checks it, returning ErrWrongSecretsPassphrase on a mismatch. It returns
+// a nil key if the store's secrets are not encrypted.
+func storedSecretsKey(ctx context.Context, q rowQuerier, passphrase string) ([]byte, error) {
+	var salt, check []byte
+	err := q.QueryRowContext(ctx, "SELECT value FROM store_settings WHERE name = ?", secretsSaltSetting).Scan(&salt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets salt: %w", err)
+	}
+
+	if err := q.QueryRowContext(ctx, "SELECT value FROM store_settings WHERE name = ?", secretsCheckSetting).Scan(&check); err != nil {
+		return nil, fmt.Errorf("failed to read secrets check: %w", err)
+	}
+	key, err := deriveSecretsKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(check, secretsCheck(key)) {
+		return nil, ErrWrongSecretsPassphrase
+	}
+	return key, nil
+}
+
+// prepareSecrets enables secrets encryption with the passphrase kept by
+// unlockSecrets: it stores a new salt and passphrase check and seals the
+// secrets of the accounts saved before. If another connection enabled it
+// meanwhile, the passphrase is checked against that salt instead. The caller
+// must hold s.mu exclusively.
+func (s *AccountStore) prepareSecrets(ctx context.Context) (err error) {
+	if s.pendingSecretsPassphrase == "" {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			s.secretsKey = nil
+		}
+	}()
+
+	if s.secretsKey, err = storedSecretsKey(ctx, tx, s.pendingSecretsPassphrase); err != nil {
+		return err
+	}
+	if s.secretsKey != nil {
+		s.pendingSecretsPassphrase = ""
+		return tx.Rollback()
+	}
+
+	salt := make([]byte, scryptSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	if s.secretsKey, err = deriveSecretsKey(s.pendingSecretsPassphrase, salt); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO store_settings (name, value) VALUES (?, ?), (?, ?)",
+		secretsSaltSetting, salt, secretsCheckSetting, secretsCheck(s.secretsKey)); err != nil {
+		return fmt.Errorf("failed to store secrets settings: %w", err)
+	}
+
+	sealed, err := s.sealExistingSecrets(tx)
+	if err != nil {
+		return err
+	}
+	// The mnemonic hashes are recomputed under a key derived from the new
+	// master key, so the stored hash key is no longer needed
+	if _, err := tx.ExecContext(ctx, "UPDATE accounts SET mnemonic_hash = NULL"); err != nil {
+		return fmt.Errorf("failed to reset mnemonic hashes: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM store_settings WHERE name = ?", mnemonicHashKeySetting); err != nil {
+		return fmt.Errorf("failed to remove mnemonic hash key: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit secrets settings: %w", err)
+	}
+
+	s.pendingSecretsPassphrase = ""
+	s.mnemonicHashKey = nil
+	s.logger.Debug("enabled account secrets encryption", "sealed", sealed)
+	return nil
+}
+
+// prepareWrite runs the setup an opened store defers until its first write:
+// prepareSecrets, then prepareMnemonicHashes, whose key is derived from the
+// secrets key when there is one. The caller must hold s.mu exclusively.
+func (s *AccountStore) prepareWrite(ctx context.Context) error {
+	if err := s.prepareSecrets(ctx); err != nil {
+		return err
+	}
+	return s.prepareMnemonicHashes(ctx)
+}
+
+// checkSecretsLocked records whether the store's secrets are encrypted although
+// it was opened without StoreConfig.SecretsPassphrase. Such a store still reads
+// addresses and public keys but refuses to save secrets it cannot seal.
+func (s *AccountStore) checkSecretsLocked() error {
+	var salt []byte
+	err := s.db.QueryRow("SELECT value FROM store_settings WHERE name = ?", secretsSaltSetting).Scan(&salt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read secrets salt: %w", err)
+	}
+	s.secretsLocked = true
+	return nil
+}
+
+// sealExistingSecrets seals the plaintext secrets of every stored account and
+// returns how many accounts were updated
+func (s *AccountStore) sealExistingSecrets(tx *sql.Tx) (int, error) {
+	rows, err := tx.Query("SELECT address, mnemonic, private_key FROM accounts")
+	if err != nil {
+		return 0, fmt.Errorf("failed to query account secrets: %w", err)
+	}
+
+	type secrets struct{ address, mnemonic, privateKey string }
+	var plaintext []secrets
+	for rows.Next() {
+		var row secrets
+		if err := rows.Scan(&row.address, &row.mnemonic, &row.privateKey); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan account secrets: %w", err)
+		}
+		if !isSealed(row.mnemonic) || !isSealed(row.privateKey) {
+			plaintext = append(plaintext, row)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating account secrets: %w", err)
+	}
+
+	for _, row := range plaintext {
+		mnemonic, err := s.sealSecret(row.address, row.mnemonic)
+		if err != nil {
+			return 0, err
+		}
+		privateKey, err := s.sealSecret(row.address, row.privateKey)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec("UPDATE accounts SET mnemonic = ?, private_key = ? WHERE address = ?", mnemonic, privateKey, row.address); err != nil {
+			return 0, fmt.Errorf("failed to seal secrets of account %s: %w", row.address, err)
+		}
+	}
+
+	return len(plaintext), nil
+}
+
+// deriveSecretsKey derives the master secrets key from passphrase and salt
+func deriveSecretsKey(passphrase string, salt []byte) ([]byte, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive secrets key: %w", err)
+	}
+	return key, nil
+}
+
+// secretsCheck returns the value stored to recognize the right passphrase
+// without keeping anything that decrypts the secrets
+func secretsCheck(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(secretsCheckMessage))
+	return mac.Sum(nil)
+}
+
+// accountCipher returns the AEAD for the account with the given address
+func (s *AccountStore) accountCipher(address string) (cipher.AEAD, error) {
+	key := make([]byte, scryptKeyLen)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, s.secretsKey, nil, []byte(secretsKeyInfo+address)), key); err != nil {
+		return nil, fmt.Errorf("failed to derive account key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// isSealed reports whether a column value was sealed by sealSecret. Empty
+// values, such as the mnemonic of a key-only account, are never sealed.
+func isSealed(value string) bool {
+	return value == "" || strings.HasPrefix(value, sealedPrefix)
+}
+
+// sealSecret encrypts value for the account with the given address. Without a
+// secrets key, or for values that are already sealed, it returns value
+// unchanged, except that a locked store refuses plaintext with ErrSecretsLocked.
+func (s *AccountStore) sealSecret(address, value string) (string, error) {
+	if isSealed(value) {
+		return value, nil
+	}
+	if s.secretsKey == nil {
+		if s.secretsLocked {
+			return "", ErrSecretsLocked
+		}
+		return value, nil
+	}
+
+	aead, err := s.accountCipher(address)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(address))
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// openSecret decrypts a value sealed by sealSecret. Plaintext values from
+// before encryption was enabled are returned unchanged.
+func (s *AccountStore) openSecret(address, value string) (string, error) {
+	if !strings.HasPrefix(value, sealedPrefix) {
+		return value, nil
+	}
+	if s.secretsKey == nil {
+		return "", ErrSecretsLocked
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, sealedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode secrets of account %s: %w", address, err)
+	}
+	aead, err := s.accountCipher(address)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("secrets of account %s are truncated", address)
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(address))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secrets of account %s: %w", address, err)
+	}
+	return string(plaintext), nil
+}
+
+// sealAccount returns a copy of account with its secrets sealed for storage
+func (s *AccountStore) sealAccount(account *Account) (*Account, error) {
+	sealed := *account
+	var err error
+	if sealed.Mnemonic, err = s.sealSecret(account.Address, account.Mnemonic); err != nil {
+		return nil, err
+	}
+	if sealed.PrivateKey, err = s.sealSecret(account.Address, account.PrivateKey); err != nil {
+		return nil, err
+	}
+	return &sealed, nil
+}
+
+// revealSecrets decrypts the sealed secrets of account in place
+func (s *AccountStore) revealSecrets(account *Account) error {
+	var err error
+	if account.Mnemonic, err = s.openSecret(account.Address, account.Mnemonic); err != nil {
+		return err
+	}
+	if account.PrivateKey, err = s.openSecret(account.Address, account.PrivateKey); err != nil {
+		return err
+	}
+	return nil
+}
+
+// hideSecrets clears sealed secrets of accounts returned by bulk reads, so
+// they are not passed around as if they were plaintext
+func hideSecrets(accounts []*Account) {
+	for _, account := range accounts {
+		if strings.HasPrefix(account.Mnemonic, sealedPrefix) {
+			account.Mnemonic = ""
+		}
+		if strings.HasPrefix(account.PrivateKey, sealedPrefix) {
+			account.PrivateKey = ""
+		}
+	}
+}
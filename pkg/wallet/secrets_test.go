@@ -0,0 +1,170 @@
+package wallet
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSecretsSealedAtRest(t *testing.T) {
+	dir := t.TempDir()
+	config := testConfig()
+	config.SecretsPassphrase = "secrets-passphrase"
+	store := openTestStore(t, dir, config)
+
+	account := newTestAccount(t)
+	if _, err := store.SaveAccount(account); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+
+	var mnemonic, privateKey string
+	if err := store.db.QueryRow("SELECT mnemonic, private_key FROM accounts WHERE address = ?", account.Address).Scan(&mnemonic, &privateKey); err != nil {
+		t.Fatalf("reading stored secrets: %v", err)
+	}
+	if !strings.HasPrefix(mnemonic, sealedPrefix) || !strings.HasPrefix(privateKey, sealedPrefix) {
+		t.Fatalf("secrets stored unsealed: mnemonic %q, private key %q", mnemonic, privateKey)
+	}
+
+	got, err := store.GetAccountByAddress(account.Address)
+	if err != nil {
+		t.Fatalf("GetAccountByAddress() error = %v", err)
+	}
+	if got.Mnemonic != account.Mnemonic || got.PrivateKey != account.PrivateKey {
+		t.Error("GetAccountByAddress() did not decrypt the secrets")
+	}
+}
+
+func TestSecretsLockedStoreRefusesWrites(t *testing.T) {
+	dir := t.TempDir()
+	config := testConfig()
+	config.SecretsPassphrase = "secrets-passphrase"
+	sealed := openTestStore(t, dir, config)
+	if _, err := sealed.SaveAccount(newTestAccount(t)); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+	sealed.Close()
+
+	locked := openTestStore(t, dir, testConfig())
+	account := newTestAccount(t)
+
+	if _, err := locked.SaveAccount(account); !errors.Is(err, ErrSecretsLocked) {
+		t.Errorf("SaveAccount() on a locked store error = %v, want ErrSecretsLocked", err)
+	}
+	if _, err := locked.SaveAccounts([]*Account{account}); !errors.Is(err, ErrSecretsLocked) {
+		t.Errorf("SaveAccounts() on a locked store error = %v, want ErrSecretsLocked", err)
+	}
+
+	var plaintext int
+	if err := locked.db.QueryRow("SELECT COUNT(*) FROM accounts WHERE mnemonic NOT LIKE ? OR private_key NOT LIKE ?",
+		sealedPrefix+"%", sealedPrefix+"%").Scan(&plaintext); err != nil {
+		t.Fatalf("counting plaintext secrets: %v", err)
+	}
+	if plaintext != 0 {
+		t.Errorf("%d accounts stored with plaintext secrets", plaintext)
+	}
+
+	count, err := locked.CountAccounts()
+	if err != nil {
+		t.Fatalf("CountAccounts() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("CountAccounts() = %d, want 1", count)
+	}
+}
+
+func TestSecretsEncryptedOnFirstWrite(t *testing.T) {
+	dir := t.TempDir()
+	plain := openTestStore(t, dir, testConfig())
+	existing := newTestAccount(t)
+	if _, err := plain.SaveAccount(existing); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+	plain.Close()
+
+	config := testConfig()
+	config.SecretsPassphrase = "secrets-passphrase"
+	store := openTestStore(t, dir, config)
+
+	// countSealed returns how many accounts have sealed secrets and whether a salt is stored
+	countSealed := func() (sealed int, salted bool) {
+		store.db.QueryRow("SELECT COUNT(*) FROM accounts WHERE mnemonic LIKE ? AND private_key LIKE ?",
+			sealedPrefix+"%", sealedPrefix+"%").Scan(&sealed)
+		store.db.QueryRow("SELECT EXISTS (SELECT 1 FROM store_settings WHERE name = ?)", secretsSaltSetting).Scan(&salted)
+		return sealed, salted
+	}
+
+	// Reading with the passphrase leaves the store as it was
+	if _, err := store.GetAccounts(); err != nil {
+		t.Fatalf("GetAccounts() error = %v", err)
+	}
+	got, err := store.GetAccountByAddress(existing.Address)
+	if err != nil {
+		t.Fatalf("GetAccountByAddress() error = %v", err)
+	}
+	if got.PrivateKey != existing.PrivateKey {
+		t.Error("GetAccountByAddress() before encryption returned another private key")
+	}
+	if sealed, salted := countSealed(); sealed != 0 || salted {
+		t.Fatalf("reading sealed %d accounts (salt stored: %v), want no change", sealed, salted)
+	}
+
+	if _, err := store.SaveAccount(newTestAccount(t)); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+	if sealed, salted := countSealed(); sealed != 2 || !salted {
+		t.Errorf("after the first write %d accounts are sealed (salt stored: %v), want 2", sealed, salted)
+	}
+	if got, err := store.GetAccountByAddress(existing.Address); err != nil || got.PrivateKey != existing.PrivateKey {
+		t.Errorf("GetAccountByAddress() after encryption = %v, %v, want the original keys", got, err)
+	}
+	store.Close()
+
+	config.SecretsPassphrase = "another-passphrase"
+	if other, err := NewAccountStoreWithConfig(dir, testPassword, config); !errors.Is(err, ErrWrongSecretsPassphrase) {
+		if other != nil {
+			other.Close()
+		}
+		t.Errorf("NewAccountStoreWithConfig() with another secrets passphrase error = %v, want ErrWrongSecretsPassphrase", err)
+	}
+}
+
+func TestSealedSecretsChecksNeedThePassphrase(t *testing.T) {
+	plain, err := ImportAccount(testMnemonic, "", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+	withPassphrase, err := ImportAccount(testMnemonic, "TREZOR", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() with passphrase error = %v", err)
+	}
+
+	dir := t.TempDir()
+	config := testConfig()
+	config.SecretsPassphrase = "secrets-passphrase"
+	config.AllowSharedMnemonics = true
+	sealed := openTestStore(t, dir, config)
+	if _, err := sealed.SaveAccounts([]*Account{plain, withPassphrase}); err != nil {
+		t.Fatalf("SaveAccounts() error = %v", err)
+	}
+
+	// Each mnemonic is sealed with its own nonce, so only the decrypted ones match
+	groups, err := sealed.FindDuplicateMnemonics()
+	if err != nil {
+		t.Fatalf("FindDuplicateMnemonics() error = %v", err)
+	}
+	if len(groups) != 1 || len(groups[0]) != 2 {
+		t.Errorf("FindDuplicateMnemonics() = %v, want one group of %s and %s", groups, plain.Address, withPassphrase.Address)
+	}
+	if failures, err := sealed.VerifyAll(); err != nil || len(failures) != 0 {
+		t.Errorf("VerifyAll() = %v, %v, want no failures", failures, err)
+	}
+	sealed.Close()
+
+	locked := openTestStore(t, dir, testConfig())
+	if _, err := locked.FindDuplicateMnemonics(); !errors.Is(err, ErrSecretsLocked) {
+		t.Errorf("FindDuplicateMnemonics() on a locked store error = %v, want ErrSecretsLocked", err)
+	}
+	if _, err := locked.VerifyAll(); !errors.Is(err, ErrSecretsLocked) {
+		t.Errorf("VerifyAll() on a locked store error = %v, want ErrSecretsLocked", err)
+	}
+}
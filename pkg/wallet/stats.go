@@ -0,0 +1,96 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+)
+
+// StoreStats summarizes the contents of an AccountStore
+type StoreStats struct {
+	// Accounts is the number of active accounts; Archived counts archived ones separately
+	Accounts int
+	Archived int
+	// Labels counts active accounts per label, most common first. Accounts
+	// without a label are counted under the empty label.
+	Labels []LabelCount
+	// Earliest and Latest are the oldest and newest creation times of active
+	// accounts, or zero when there are none
+	Earliest time.Time
+	Latest   time.Time
+	// FileSize is the size in bytes of the database file and its WAL on disk
+	FileSize int64
+}
+
+// LabelCount is the number of accounts sharing a label
+type LabelCount struct {
+	Label string
+	Count int
+}
+
+// Stats returns aggregate statistics about the store. The counts are computed
+// by the database, so no account rows or secrets are loaded.
+func (s *AccountStore) Stats() (StoreStats, error) {
+	return s.StatsContext(context.Background())
+}
+
+// StatsContext is like Stats but honors cancellation and deadlines from ctx
+func (s *AccountStore) StatsContext(ctx context.Context) (StoreStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var stats StoreStats
+	if s.db == nil {
+		return stats, fmt.Errorf("database connection not established")
+	}
+
+	var earliest, latest sqliteTime
+	err := s.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE NOT archived),
+			COUNT(*) FILTER (WHERE archived),
+			MIN(created_at) FILTER (WHERE NOT archived),
+			MAX(created_at) FILTER (WHERE NOT archived)
+		FROM accounts`).Scan(&stats.Accounts, &stats.Archived, &earliest, &latest)
+	if err != nil {
+		return stats, fmt.Errorf("failed to count accounts: %w", err)
+	}
+	stats.Earliest, stats.Latest = earliest.Time, latest.Time
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT label, COUNT(*) FROM accounts WHERE NOT archived
+		GROUP BY label ORDER BY COUNT(*) DESC, label IS NULL, label`)
+	if err != nil {
+		return stats, fmt.Errorf("failed to count labels: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			label sql.NullString
+			count int
+		)
+		if err := rows.Scan(&label, &count); err != nil {
+			return stats, fmt.Errorf("failed to scan label count: %w", err)
+		}
+		stats.Labels = append(stats.Labels, LabelCount{Label: label.String, Count: count})
+	}
+	if err := rows.Err(); err != nil {
+		return stats, fmt.Errorf("error iterating label counts: %w", err)
+	}
+
+	for _, path := range []string{s.dbPath, s.dbPath + "-wal"} {
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return stats, fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+		stats.FileSize += info.Size()
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,67 @@
+package wallet
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	store := newTestStore(t)
+	empty, err := store.Stats()
+	if err != nil {
+		t.Fatalf("Stats() of an empty store error = %v", err)
+	}
+	if empty.Accounts != 0 || empty.Archived != 0 || len(empty.Labels) != 0 || !empty.Earliest.IsZero() || !empty.Latest.IsZero() {
+		t.Errorf("Stats() of an empty store = %+v, want no accounts and zero times", empty)
+	}
+	if empty.FileSize <= 0 {
+		t.Errorf("Stats().FileSize = %d, want the size of the database file", empty.FileSize)
+	}
+
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	var accounts []*Account
+	for i, label := range []string{"hot", "", "cold", "hot", "", "hot", "old"} {
+		account := newTestAccount(t)
+		account.Label = label
+		account.CreatedAt = base.Add(time.Duration(i) * time.Hour)
+		accounts = append(accounts, account)
+	}
+	if _, err := store.SaveAccounts(accounts); err != nil {
+		t.Fatalf("SaveAccounts() error = %v", err)
+	}
+	// Archiving the oldest and newest accounts moves both time bounds
+	for _, account := range []*Account{accounts[0], accounts[6]} {
+		if err := store.DeleteAccount(account.Address); err != nil {
+			t.Fatalf("DeleteAccount() error = %v", err)
+		}
+	}
+
+	stats, err := store.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if stats.Accounts != 5 || stats.Archived != 2 {
+		t.Errorf("Stats() = %d active, %d archived, want 5 and 2", stats.Accounts, stats.Archived)
+	}
+	if want := base.Add(time.Hour); !stats.Earliest.Equal(want) {
+		t.Errorf("Stats().Earliest = %v, want %v", stats.Earliest, want)
+	}
+	if want := base.Add(5 * time.Hour); !stats.Latest.Equal(want) {
+		t.Errorf("Stats().Latest = %v, want %v", stats.Latest, want)
+	}
+
+	// Most common first, with unlabeled accounts last among ties
+	want := []LabelCount{{Label: "hot", Count: 2}, {Label: "", Count: 2}, {Label: "cold", Count: 1}}
+	if !slices.Equal(stats.Labels, want) {
+		t.Errorf("Stats().Labels = %v, want %v", stats.Labels, want)
+	}
+}
+
+func TestStatsClosedStore(t *testing.T) {
+	store := newTestStore(t)
+	store.Close()
+	if _, err := store.Stats(); err == nil {
+		t.Error("Stats() on a closed store succeeded, want an error")
+	}
+}
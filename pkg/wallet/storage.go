@@ -0,0 +1,1823 @@
+package wallet
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/cosmos/go-bip39"
+	sqlite3 "github.com/mutecomm/go-sqlcipher/v4"
+)
+
+const (
+	// DBFileName is the name of the encrypted database file of the default profile
+	DBFileName = "sei_accounts.db"
+	// DefaultDBPassword is the default password for the encrypted database
+	// In production, this should be securely provided, not hardcoded
+	DefaultDBPassword = "change-me-in-production"
+	// MaxPageSize is the largest number of accounts returned by a single page query
+	MaxPageSize = 1000
+)
+
+// ErrAccountNotFound is returned when no stored account matches the requested address
+var ErrAccountNotFound = errors.New("account not found")
+
+// ErrWrongPassword is returned when the database cannot be decrypted with the
+// given password. SQLCipher cannot tell a wrong key from other encryption
+// settings, so a mismatched StoreConfig produces the same error.
+var ErrWrongPassword = errors.New("incorrect database password")
+
+// ErrDefaultPassword is returned when StoreConfig.RefuseDefaultPassword is set
+// and the store would be encrypted with DefaultDBPassword
+var ErrDefaultPassword = errors.New("refusing to use the default database password")
+
+// ErrForeignPrefix is returned when saving an account whose address belongs to
+// another chain than the one configured, see StoreConfig.AllowForeignPrefix
+var ErrForeignPrefix = errors.New("address prefix does not match the configured chain")
+
+// ErrStoreBusy is returned by PingContext while an operation that needs the
+// store to itself, such as Rekey or Close, is running or waiting to run
+var ErrStoreBusy = errors.New("store is busy with an exclusive operation")
+
+// AccountStore manages secure storage of SEI accounts
+type AccountStore struct {
+	db       *sql.DB
+	dbPath   string
+	password string
+	config   StoreConfig
+	logger   *slog.Logger
+	// mu is held exclusively for writes and for replacing db, and shared by
+	// reads, which SQLite's WAL mode lets run concurrently
+	mu sync.RWMutex
+
+	// Retry policy for writes that hit SQLITE_BUSY, see SetBusyRetry
+	busyRetries    int
+	busyRetryDelay time.Duration
+
+	// secretsKey is the master key for account secrets encryption, or nil when
+	// StoreConfig.SecretsPassphrase is not set or encryption is still pending
+	secretsKey []byte
+	// pendingSecretsPassphrase is the configured secrets passphrase of a store
+	// whose secrets are not encrypted yet, until prepareSecrets encrypts them
+	pendingSecretsPassphrase string
+	// secretsLocked is set when the store's secrets are encrypted but it was
+	// opened without the passphrase, so new secrets cannot be sealed
+	secretsLocked bool
+	// mnemonicHashKey keys the mnemonic_hash column, or is nil when the
+	// store's secrets are encrypted and it was opened without the passphrase
+	mnemonicHashKey []byte
+}
+
+// NewAccountStore creates a new account store encrypted with the given password.
+// An empty password selects DefaultDBPassword.
+func NewAccountStore(dbDir, password string) (*AccountStore, error) {
+	return NewAccountStoreWithConfig(dbDir, password, DefaultStoreConfig())
+}
+
+// NewAccountStoreWithConfig is like NewAccountStore but opens the database with
+// the given SQLCipher settings, such as a higher KDF iteration count
+func NewAccountStoreWithConfig(dbDir, password string, config StoreConfig) (*AccountStore, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return openAccountStore(dbDir, filepath.Join(dbDir, ProfileFileName(config.Profile)), password, config)
+}
+
+// OpenAccountStoreFile opens the existing database at dbPath whatever its file
+// name, such as a backup or a database copied from another machine, with the
+// given settings. config.Profile is ignored. Unlike NewAccountStoreWithConfig
+// it never creates a database, so a mistyped path fails instead.
+func OpenAccountStoreFile(dbPath, password string, config StoreConfig) (*AccountStore, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return openAccountStore(filepath.Dir(dbPath), dbPath, password, config)
+}
+
+// openAccountStore opens or creates the database at dbPath in dbDir and
+// brings its schema up to date, or with config.ReadOnly opens the existing one
+// as it is
+func openAccountStore(dbDir, dbPath, password string, config StoreConfig) (*AccountStore, error) {
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	if password == "" {
+		password = DefaultDBPassword
+	}
+
+	// The default key is public, so such a database is effectively unencrypted
+	if password == DefaultDBPassword {
+		if config.RefuseDefaultPassword {
+			return nil, ErrDefaultPassword
+		}
+		config.Logger.Warn("the database is encrypted with the built-in default password; anyone with the file can decrypt it",
+			"path", dbPath)
+	}
+	store := &AccountStore{
+		dbPath:         dbPath,
+		password:       password,
+		config:         config,
+		logger:         config.Logger,
+		busyRetries:    DefaultBusyRetries,
+		busyRetryDelay: DefaultBusyRetryDelay,
+	}
+
+	if config.ReadOnly {
+		// A read-only store never creates the database or its directory
+		if _, err := os.Stat(dbPath); err != nil {
+			return nil, fmt.Errorf("failed to open database: %w", err)
+		}
+		store.checkDirMode(dbDir)
+	} else {
+		// Create directory if it doesn't exist, otherwise make sure it is not exposed
+		created, err := store.ensureDir(dbDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create database directory: %w", err)
+		}
+		if !created {
+			store.checkDirMode(dbDir)
+		}
+	}
+
+	// Initialize the database
+	if err := store.openDB(); err != nil {
+		return nil, err
+	}
+
+	// Create or upgrade the schema to the latest version; a read-only store
+	// can only be checked
+	if config.ReadOnly {
+		if err := store.checkSchemaVersion(); err != nil {
+			store.Close()
+			return nil, err
+		}
+	} else if err := store.migrate(); err != nil {
+		return nil, fmt.Errorf("failed to initialize database schema: %w", err)
+	}
+
+	if config.SecretsPassphrase != "" {
+		if err := store.unlockSecrets(config.SecretsPassphrase); err != nil {
+			store.Close()
+			return nil, err
+		}
+	} else if err := store.checkSecretsLocked(); err != nil {
+		store.Close()
+		return nil, err
+	}
+
+	// Seed the account total so metrics reflect accounts saved by earlier runs
+	if config.Metrics != nil {
+		count, err := store.CountAccounts()
+		if err != nil {
+			store.Close()
+			return nil, err
+		}
+		config.Metrics.setTotal(count)
+	}
+
+	return store, nil
+}
+
+// openDB opens the encrypted database
+func (s *AccountStore) openDB() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db != nil {
+		return nil
+	}
+
+	// Determine if the database already exists
+	_, err := os.Stat(s.dbPath)
+	dbExists := !os.IsNotExist(err)
+	s.logger.Debug("opening database", "path", s.dbPath, "exists", dbExists,
+		"cipher_page_size", s.config.CipherPageSize, "kdf_iter", s.config.KDFIterations)
+
+	uri, err := databaseURI(s.dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve database path: %w", err)
+	}
+
+	// Create connection string with encryption options
+	connStr := fmt.Sprintf(
+		"%s?_pragma_key=%s&_pragma_cipher_page_size=%d",
+		uri,
+		escapeDSNKey(s.password),
+		s.config.CipherPageSize,
+	)
+	if s.config.ReadOnly {
+		connStr += "&mode=ro"
+	}
+
+	// Open the database connection
+	db := sql.OpenDB(s.config.newConnector(connStr))
+	s.config.configurePool(db)
+
+	// Test the connection
+	if err := db.Ping(); err != nil {
+		s.logger.Debug("database ping failed", "path", s.dbPath, "error", err)
+		db.Close()
+		if isWrongKey(err) {
+			return fmt.Errorf("failed to decrypt %s: %w", s.dbPath, ErrWrongPassword)
+		}
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	s.db = db
+
+	// If this is a new database, initialize with some optimization settings
+	if !dbExists {
+		if _, err := db.Exec("PRAGMA journal_mode=WAL;"); err != nil {
+			return fmt.Errorf("failed to set journal mode: %w", err)
+		}
+		if _, err := db.Exec("PRAGMA synchronous=NORMAL;"); err != nil {
+			return fmt.Errorf("failed to set synchronous mode: %w", err)
+		}
+
+		// SQLite creates the file subject to the umask and gives the WAL
+		// and shared-memory files the same mode, so fix it up front
+		if err := chmod(s.dbPath, s.config.fileMode()); err != nil {
+			return fmt.Errorf("failed to set database permissions: %w", err)
+		}
+		for _, suffix := range []string{"-wal", "-shm"} {
+			if err := chmod(s.dbPath+suffix, s.config.fileMode()); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to set database permissions: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isWrongKey reports whether err is how SQLCipher reports a key that does not
+// decrypt the file: SQLITE_NOTADB ("file is not a database"), or on some
+// versions a failed open with the misleading message "not an error"
+func isWrongKey(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.Code == sqlite3.ErrNotADB || sqliteErr.Error() == "not an error"
+}
+
+// databaseURI returns path as an absolute SQLite file: URI. A plain path would
+// be cut at its first '?' by the driver, and a Windows path such as
+// C:\Users\Jane Doe\... needs forward slashes, a slash before the drive letter
+// and escaped spaces to be a valid URI, which url.URL takes care of.
+func databaseURI(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	slashed := filepath.ToSlash(abs)
+	if !strings.HasPrefix(slashed, "/") {
+		slashed = "/" + slashed
+	}
+	return (&url.URL{Scheme: "file", Path: slashed}).String(), nil
+}
+
+// escapeDSNKey escapes a key for the _pragma_key DSN parameter. The driver
+// interpolates the key into PRAGMA key = "...", so embedded double quotes are
+// doubled, and the result is URL-escaped to survive DSN query parsing.
+func escapeDSNKey(key string) string {
+	return url.QueryEscape(strings.ReplaceAll(key, `"`, `""`))
+}
+
+// SaveAccount stores an account in the encrypted database. It reports true if the
+// account was inserted and false if an account with the same address already
+// existed. An account whose mnemonic is already stored under another address
+// is not saved either, unless it is an HD sibling of that account (see
+// mnemonic_hash.go), and reported with a SharedMnemonicError, unless
+// StoreConfig.AllowSharedMnemonics is set.
+func (s *AccountStore) SaveAccount(account *Account) (bool, error) {
+	return s.SaveAccountContext(context.Background(), account)
+}
+
+// SaveAccountContext is like SaveAccount but honors cancellation and deadlines from ctx
+func (s *AccountStore) SaveAccountContext(ctx context.Context, account *Account) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db == nil {
+		return false, fmt.Errorf("database connection not established")
+	}
+
+	defer s.config.Metrics.observe(opSaveAccount, time.Now())
+
+	var inserted bool
+	err := s.withBusyRetry(ctx, func() error {
+		if err := s.prepareWrite(ctx); err != nil {
+			return err
+		}
+		var err error
+		inserted, err = s.saveAccount(ctx, account)
+		return err
+	})
+	if inserted {
+		s.config.Metrics.added(1)
+	}
+	return inserted, err
+}
+
+// saveAccount performs a single SaveAccount attempt. The caller must hold s.mu.
+func (s *AccountStore) saveAccount(ctx context.Context, account *Account) (bool, error) {
+	// Check if the account already exists
+	var count int
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM accounts WHERE address = ?", account.Address).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if account exists: %w", err)
+	}
+
+	if count > 0 {
+		// Account already exists, so we'll skip saving it
+		s.logger.Debug("account already stored, skipping", "address", account.Address)
+		return false, nil
+	}
+
+	if err := s.checkPrefix(account); err != nil {
+		return false, err
+	}
+	mnemonicHash, refused, err := s.mnemonicHashForSave(ctx, s.db, account, nil)
+	if err != nil {
+		return false, err
+	}
+	if refused {
+		return false, &SharedMnemonicError{Addresses: []string{account.Address}}
+	}
+	sealed, err := s.sealAccount(account)
+	if err != nil {
+		return false, err
+	}
+
+	// Insert the new account
+	_, err = s.db.ExecContext(ctx, insertAccountSQL, insertAccountArgs(sealed, mnemonicHash)...)
+	if err != nil {
+		return false, fmt.Errorf("failed to save account: %w", err)
+	}
+
+	s.logger.Debug("inserted account", "address", account.Address)
+	return true, nil
+}
+
+// SaveAccounts stores a batch of accounts in a single transaction and returns
+// how many were inserted. Accounts whose address already exists, either in the
+// database or earlier in the batch, are skipped. Accounts whose mnemonic is
+// already stored under another address are left out too, unless they are HD
+// siblings of that account or StoreConfig.AllowSharedMnemonics is set; the
+// rest of the batch is still saved and a SharedMnemonicError lists them.
+// Accounts of one mnemonic at different paths within the batch count as HD
+// siblings. Any other error rolls back the whole batch.
+func (s *AccountStore) SaveAccounts(accounts []*Account) (int, error) {
+	return s.SaveAccountsContext(context.Background(), accounts)
+}
+
+// SaveAccountsContext is like SaveAccounts but honors cancellation and deadlines from ctx
+func (s *AccountStore) SaveAccountsContext(ctx context.Context, accounts []*Account) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db == nil {
+		return 0, fmt.Errorf("database connection not established")
+	}
+
+	defer s.config.Metrics.observe(opSaveAccounts, time.Now())
+
+	// A busy error rolls back the whole batch, so each retry starts a fresh transaction
+	var (
+		inserted int
+		refused  []string
+	)
+	err := s.withBusyRetry(ctx, func() error {
+		if err := s.prepareWrite(ctx); err != nil {
+			return err
+		}
+		var err error
+		inserted, refused, err = s.saveAccounts(ctx, accounts)
+		return err
+	})
+	if err != nil {
+		return inserted, err
+	}
+	s.config.Metrics.added(inserted)
+	if len(refused) > 0 {
+		return inserted, &SharedMnemonicError{Addresses: refused}
+	}
+	return inserted, nil
+}
+
+// saveAccounts performs a single SaveAccounts transaction and also returns the
+// addresses refused for a shared mnemonic. The caller must hold s.mu.
+func (s *AccountStore) saveAccounts(ctx context.Context, accounts []*Account) (inserted int, refused []string, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	existsStmt, err := tx.PrepareContext(ctx, "SELECT COUNT(*) FROM accounts WHERE address = ?")
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to prepare existence check: %w", err)
+	}
+	defer existsStmt.Close()
+
+	insertStmt, err := tx.PrepareContext(ctx, insertAccountSQL)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer insertStmt.Close()
+
+	batch := make(map[string]bool, len(accounts))
+	for _, account := range accounts {
+		// The transaction sees its own inserts, so this also catches duplicates within the batch
+		var count int
+		if err = existsStmt.QueryRowContext(ctx, account.Address).Scan(&count); err != nil {
+			return 0, nil, fmt.Errorf("failed to check if account %s exists: %w", account.Address, err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		if err = s.checkPrefix(account); err != nil {
+			return 0, nil, err
+		}
+		mnemonicHash, shared, err := s.mnemonicHashForSave(ctx, tx, account, batch)
+		if err != nil {
+			return 0, nil, err
+		}
+		if shared {
+			refused = append(refused, account.Address)
+			continue
+		}
+		sealed, err := s.sealAccount(account)
+		if err != nil {
+			return 0, nil, err
+		}
+		if _, err = insertStmt.ExecContext(ctx, insertAccountArgs(sealed, mnemonicHash)...); err != nil {
+			return 0, nil, fmt.Errorf("failed to save account %s: %w", account.Address, err)
+		}
+		batch[account.Address] = true
+		inserted++
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, nil, fmt.Errorf("failed to commit accounts: %w", err)
+	}
+
+	s.logger.Debug("saved account batch", "accounts", len(accounts), "inserted", inserted)
+	return inserted, refused, nil
+}
+
+// checkPrefix rejects accounts whose address prefix is not the configured
+// account prefix, unless StoreConfig.AllowForeignPrefix is set
+func (s *AccountStore) checkPrefix(account *Account) error {
+	return checkAccountPrefix(account, s.config.AllowForeignPrefix)
+}
+
+// checkAccountPrefix rejects an account whose address prefix is not the
+// configured account prefix with ErrForeignPrefix, unless allowForeign is set
+func checkAccountPrefix(account *Account, allowForeign bool) error {
+	if allowForeign {
+		return nil
+	}
+
+	hrp, _, err := bech32.DecodeAndConvert(account.Address)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", account.Address, err)
+	}
+	if want := sdk.GetConfig().GetBech32AccountAddrPrefix(); hrp != want {
+		return fmt.Errorf("%w: account %s has prefix %q, expected %q", ErrForeignPrefix, account.Address, hrp, want)
+	}
+	return nil
+}
+
+// GetAccounts retrieves all stored accounts that are not archived
+func (s *AccountStore) GetAccounts() ([]*Account, error) {
+	return s.GetAccountsContext(context.Background())
+}
+
+// GetAccountsContext is like GetAccounts but honors cancellation and deadlines from ctx
+func (s *AccountStore) GetAccountsContext(ctx context.Context) ([]*Account, error) {
+	return s.getAccounts(ctx, false)
+}
+
+// getAccounts retrieves all accounts that are not archived. Encrypted secrets
+// are decrypted when reveal is set and left out otherwise.
+func (s *AccountStore) getAccounts(ctx context.Context, reveal bool) ([]*Account, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection not established")
+	}
+
+	defer s.config.Metrics.observe(opGetAccounts, time.Now())
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+accountColumns+" FROM accounts WHERE NOT archived")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts, err := scanAccounts(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if !reveal {
+		hideSecrets(accounts)
+		return accounts, nil
+	}
+	for _, account := range accounts {
+		if err := s.revealSecrets(account); err != nil {
+			return nil, err
+		}
+	}
+	return accounts, nil
+}
+
+// GetAccountsPage retrieves up to limit accounts starting at offset, ordered by
+// insertion. limit must be positive and is capped at MaxPageSize.
+func (s *AccountStore) GetAccountsPage(limit, offset int) ([]*Account, error) {
+	return s.GetAccountsPageContext(context.Background(), limit, offset)
+}
+
+// GetAccountsPageContext is like GetAccountsPage but honors cancellation and deadlines from ctx
+func (s *AccountStore) GetAccountsPageContext(ctx context.Context, limit, offset int) ([]*Account, error) {
+	return s.GetAccountsSortedPageContext(ctx, SortByID, limit, offset)
+}
+
+// Sort orders accepted by GetAccountsSortedPage
+const (
+	SortByID      = "id"
+	SortByAddress = "address"
+	SortByCreated = "created"
+	SortByLabel   = "label"
+)
+
+// accountSortClauses maps each sort order to its ORDER BY clause. The id
+// tiebreaker keeps paging stable when sort keys are equal.
+var accountSortClauses = map[string]string{
+	SortByID:      "id",
+	SortByAddress: "address, id",
+	SortByCreated: "created_at DESC, id DESC",
+	SortByLabel:   "label IS NULL, label, id",
+}
+
+// GetAccountsSortedPage is like GetAccountsPage but orders accounts by sortBy:
+// SortByID (insertion order), SortByAddress, SortByCreated (newest first) or
+// SortByLabel (labelled accounts first, alphabetically)
+func (s *AccountStore) GetAccountsSortedPage(sortBy string, limit, offset int) ([]*Account, error) {
+	return s.GetAccountsSortedPageContext(context.Background(), sortBy, limit, offset)
+}
+
+// GetAccountsSortedPageContext is like GetAccountsSortedPage but honors cancellation and deadlines from ctx
+func (s *AccountStore) GetAccountsSortedPageContext(ctx context.Context, sortBy string, limit, offset int) ([]*Account, error) {
+	orderBy, ok := accountSortClauses[sortBy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort order %q", sortBy)
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("page limit must be positive, got %d", limit)
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("page offset must not be negative, got %d", offset)
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection not established")
+	}
+
+	defer s.config.Metrics.observe(opGetAccountsPage, time.Now())
+
+	// orderBy comes from the fixed accountSortClauses table, never from user input
+	s.logger.Debug("querying accounts", "sort", sortBy, "limit", limit, "offset", offset)
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+accountColumns+" FROM accounts WHERE NOT archived ORDER BY "+orderBy+" LIMIT ? OFFSET ?",
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query accounts: %w", err)
+	}
+	defer rows.Close()
+
+	return hiddenAccounts(scanAccounts(rows))
+}
+
+// accountColumns lists the columns scanned by scanAccount, in order
+const accountColumns = "address, mnemonic, public_key, private_key, label, created_at, derivation_path, successor, key_algorithm"
+
+// insertAccountSQL inserts one account; a missing creation time falls back to the current time
+const insertAccountSQL = `INSERT INTO accounts (address, mnemonic, public_key, private_key, label, created_at, derivation_path, key_algorithm, mnemonic_hash)
+	VALUES (?, ?, ?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP), ?, ?, ?)`
+
+// insertAccountArgs returns the arguments for insertAccountSQL, with
+// mnemonicHash as returned by mnemonicHashForSave
+func insertAccountArgs(account *Account, mnemonicHash []byte) []any {
+	var createdAt any
+	if !account.CreatedAt.IsZero() {
+		createdAt = account.CreatedAt.UTC().Format(sqliteTimeLayout)
+	}
+	return []any{
+		account.Address,
+		account.Mnemonic,
+		account.PubKey,
+		account.PrivateKey,
+		nullString(account.Label),
+		createdAt,
+		nullString(account.DerivationPath),
+		nullString(string(account.KeyAlgorithm)),
+		mnemonicHash,
+	}
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanAccount reads one account selected with accountColumns. Accounts without
+// a key algorithm get DefaultKeyAlgorithm. A missing derivation path stays
+// empty: it is unknown, see backfillDerivationPaths.
+func scanAccount(row rowScanner) (*Account, error) {
+	account := &Account{}
+	var (
+		label     sql.NullString
+		createdAt sqliteTime
+		path      sql.NullString
+		successor sql.NullString
+		algo      sql.NullString
+	)
+	if err := row.Scan(&account.Address, &account.Mnemonic, &account.PubKey, &account.PrivateKey, &label, &createdAt, &path, &successor, &algo); err != nil {
+		return nil, err
+	}
+	account.KeyAlgorithm = DefaultKeyAlgorithm
+	if algo.Valid {
+		account.KeyAlgorithm = KeyAlgorithm(algo.String)
+	}
+	account.Label = label.String
+	account.Successor = successor.String
+	account.CreatedAt = createdAt.Time
+	account.DerivationPath = path.String
+	return account, nil
+}
+
+// hiddenAccounts passes on the result of scanAccounts with encrypted secrets
+// left out, for queries that list accounts rather than fetch one
+func hiddenAccounts(accounts []*Account, err error) ([]*Account, error) {
+	if err != nil {
+		return nil, err
+	}
+	hideSecrets(accounts)
+	return accounts, nil
+}
+
+// scanAccounts reads every remaining row selected with accountColumns
+func scanAccounts(rows *sql.Rows) ([]*Account, error) {
+	var accounts []*Account
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan account row: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating account rows: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// GetAccountByAddress retrieves a single account by its address.
+// It returns an error wrapping ErrAccountNotFound and sql.ErrNoRows if the address isn't stored.
+func (s *AccountStore) GetAccountByAddress(address string) (*Account, error) {
+	return s.GetAccountByAddressContext(context.Background(), address)
+}
+
+// GetAccountByAddressContext is like GetAccountByAddress but honors cancellation and deadlines from ctx
+func (s *AccountStore) GetAccountByAddressContext(ctx context.Context, address string) (*Account, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection not established")
+	}
+
+	if err := ValidateSeiAddress(address); err != nil {
+		return nil, err
+	}
+
+	account, err := scanAccount(s.db.QueryRowContext(ctx,
+		"SELECT "+accountColumns+" FROM accounts WHERE address = ? AND NOT archived",
+		address,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		if s.isArchived(ctx, address) {
+			return nil, fmt.Errorf("%w: %s is archived (restore it first): %w", ErrAccountNotFound, address, err)
+		}
+		return nil, fmt.Errorf("%w: %s: %w", ErrAccountNotFound, address, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query account: %w", err)
+	}
+
+	if err := s.revealSecrets(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// FindByPubKeyPrefix returns the stored accounts whose hex-encoded public key
+// starts with prefix, in insertion order. prefix must be non-empty hex; case is ignored.
+func (s *AccountStore) FindByPubKeyPrefix(prefix string) ([]*Account, error) {
+	return s.FindByPubKeyPrefixContext(context.Background(), prefix)
+}
+
+// FindByPubKeyPrefixContext is like FindByPubKeyPrefix but honors cancellation and deadlines from ctx
+func (s *AccountStore) FindByPubKeyPrefixContext(ctx context.Context, prefix string) ([]*Account, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("public key prefix must not be empty")
+	}
+	// Hex digits contain no LIKE wildcards, so the prefix needs no escaping once validated
+	for _, r := range prefix {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return nil, fmt.Errorf("public key prefix %q is not hex", prefix)
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection not established")
+	}
+
+	s.logger.Debug("searching accounts by public key prefix", "prefix", prefix)
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+accountColumns+" FROM accounts WHERE public_key LIKE ? AND NOT archived ORDER BY id",
+		strings.ToLower(prefix)+"%",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query accounts by public key prefix: %w", err)
+	}
+	defer rows.Close()
+
+	return hiddenAccounts(scanAccounts(rows))
+}
+
+// FindByMnemonic returns the stored account derived from mnemonic at the
+// standard path for DefaultCoinType without a BIP39 passphrase. The mnemonic
+// is normalized first, and the account is looked up by the derived address
+// rather than by comparing phrases, so spacing differences do not matter.
+// It returns an error wrapping ErrAccountNotFound if no account matches.
+func (s *AccountStore) FindByMnemonic(mnemonic string) (*Account, error) {
+	return s.FindByMnemonicForCoinType(mnemonic, DefaultCoinType)
+}
+
+// FindByMnemonicForCoinType is like FindByMnemonic for accounts derived with
+// coinType. The secp256k1 account is looked for first, then those of the other
+// key algorithms, which accounts stored before only secp256k1 keys were
+// derived from mnemonics may have.
+func (s *AccountStore) FindByMnemonicForCoinType(mnemonic string, coinType uint32) (*Account, error) {
+	mnemonic = NormalizeMnemonic(mnemonic)
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+
+	// Compute the seed once and look up every candidate address in one query
+	seed := bip39.NewSeed(mnemonic, "")
+	master, ch := hd.ComputeMastersFromSeed(seed)
+	candidates := make([]any, len(keyAlgorithms))
+	for i, algo := range keyAlgorithms {
+		derived, err := deriveAccount(algo, mnemonic, master, ch, DerivationPath(coinType, 0))
+		if err != nil {
+			return nil, err
+		}
+		candidates[i] = derived.Address
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection not established")
+	}
+
+	rows, err := s.db.Query("SELECT "+accountColumns+" FROM accounts WHERE address IN (?"+strings.Repeat(", ?", len(candidates)-1)+") AND NOT archived", candidates...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts, err := scanAccounts(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range candidates {
+		for _, account := range accounts {
+			if account.Address != candidate {
+				continue
+			}
+			if err := s.revealSecrets(account); err != nil {
+				return nil, err
+			}
+			return account, nil
+		}
+	}
+
+	// A lookup error would suggest an unrelated stored address, so report the miss plainly
+	return nil, fmt.Errorf("%w: no stored account matches the mnemonic (it derives %s)", ErrAccountNotFound, candidates[0])
+}
+
+// DerivedAddress is an address derived for a stored account by
+// DeriveExtraAddresses and the like, with the path it was derived at
+type DerivedAddress struct {
+	Path    string
+	Address string
+}
+
+// DeriveExtraAddresses derives count additional receive addresses for the stored
+// account with the given address, at address indices start, start+1, ... of the
+// account's derivation path. The addresses are not stored. It fails if that path
+// is not for coinType or does not reproduce the account without a BIP39 passphrase.
+// Accounts whose path is unknown are tried at DerivationPath(coinType, 0).
+func (s *AccountStore) DeriveExtraAddresses(address string, coinType uint32, start, count int) ([]DerivedAddress, error) {
+	return s.deriveStoredAddresses(address, coinType, start, count, func(base *hd.BIP44Params, index uint32) string {
+		return hd.NewParams(base.Purpose, base.CoinType, base.Account, base.Change, index).String()
+	})
+}
+
+// DeriveChangeAddresses is like DeriveExtraAddresses but derives addresses on
+// the change chain of the account's derivation path, so m/44'/118'/0'/0/0
+// gives m/44'/118'/0'/1/start and on. Accounting tools that track change
+// separately expect these.
+func (s *AccountStore) DeriveChangeAddresses(address string, coinType uint32, start, count int) ([]DerivedAddress, error) {
+	return s.deriveStoredAddresses(address, coinType, start, count, func(base *hd.BIP44Params, index uint32) string {
+		return hd.NewParams(base.Purpose, base.CoinType, base.Account, true, index).String()
+	})
+}
+
+// DeriveLedgerAddresses is like DeriveExtraAddresses but walks the account level
+// the way a Ledger does (see LedgerDerivationPath), so the addresses can be
+// compared by hand with those a Ledger shows for the same seed.
+func (s *AccountStore) DeriveLedgerAddresses(address string, coinType uint32, start, count int) ([]DerivedAddress, error) {
+	return s.deriveStoredAddresses(address, coinType, start, count, func(base *hd.BIP44Params, account uint32) string {
+		return LedgerDerivationPath(base.CoinType, account)
+	})
+}
+
+// deriveStoredAddresses derives addresses for the stored account with the given
+// address at the paths pathFor builds from its derivation path, after checking
+// that the stored path reproduces the account
+func (s *AccountStore) deriveStoredAddresses(address string, coinType uint32, start, count int, pathFor func(base *hd.BIP44Params, i uint32) string) ([]DerivedAddress, error) {
+	account, err := s.GetAccountByAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	if account.Mnemonic == "" {
+		return nil, fmt.Errorf("account %s has no mnemonic to derive addresses from", address)
+	}
+	if err := account.algorithm().checkMnemonicKeys(); err != nil {
+		return nil, fmt.Errorf("cannot derive addresses for %s: %w", address, err)
+	}
+
+	path := account.DerivationPath
+	if path == "" {
+		// The check below tells whether the guess is right
+		path = DerivationPath(coinType, 0)
+	}
+	base, err := hd.NewParamsFromPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("account %s has an invalid derivation path %q: %w", address, path, err)
+	}
+	if base.CoinType != coinType {
+		return nil, fmt.Errorf("account %s was derived at %s, not with coin type %d", address, path, coinType)
+	}
+
+	// The stored path must reproduce the account, or the derived addresses would belong to another wallet
+	first, err := deriveFromMnemonic(account.algorithm(), account.Mnemonic, "", path)
+	if err != nil {
+		return nil, err
+	}
+	if first.Address != account.Address {
+		if account.DerivationPath == "" {
+			return nil, fmt.Errorf("account %s has an unknown derivation path and was not derived at %s without a passphrase", address, path)
+		}
+		return nil, fmt.Errorf("account %s was not derived at %s without a passphrase", address, path)
+	}
+
+	accounts, err := derivePathRange(account.algorithm(), account.Mnemonic, "", start, count, func(i uint32) string {
+		return pathFor(base, i)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	derived := make([]DerivedAddress, len(accounts))
+	for i, account := range accounts {
+		derived[i] = DerivedAddress{Path: account.DerivationPath, Address: account.Address}
+	}
+	return derived, nil
+}
+
+// VerificationFailure describes a stored account whose keys failed verification
+type VerificationFailure struct {
+	Address string
+	Err     error
+}
+
+// VerifyAll checks every stored account, archived ones included, with
+// Account.Verify and returns the accounts that failed. An empty result means
+// all accounts are consistent. It fails with ErrSecretsLocked if the secrets
+// are encrypted and the store was opened without StoreConfig.SecretsPassphrase,
+// since the keys cannot be checked then.
+func (s *AccountStore) VerifyAll() ([]VerificationFailure, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection not established")
+	}
+
+	rows, err := s.db.Query("SELECT " + accountColumns + " FROM accounts ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts, err := scanAccounts(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	var failures []VerificationFailure
+	for _, account := range accounts {
+		err := s.revealSecrets(account)
+		if errors.Is(err, ErrSecretsLocked) {
+			return nil, err
+		}
+		if err == nil {
+			err = account.Verify()
+		}
+		if err != nil {
+			failures = append(failures, VerificationFailure{Address: account.Address, Err: err})
+		}
+	}
+
+	return failures, nil
+}
+
+// FindDuplicateMnemonics returns groups of addresses whose accounts share the
+// same mnemonic at the same derivation path. Independently generated accounts
+// should never collide, so any group points to an entropy failure or an
+// accidental re-import, for example with another passphrase. Accounts of one
+// mnemonic at different paths, such as the address indices of an HD wallet,
+// are expected and not reported. Accounts without a mnemonic and archived
+// accounts are ignored, so an account that was deleted and imported again is
+// not reported. Encrypted mnemonics (see StoreConfig.SecretsPassphrase) are
+// compared once decrypted, so this fails with ErrSecretsLocked on a store
+// opened without the passphrase.
+func (s *AccountStore) FindDuplicateMnemonics() ([][]string, error) {
+	return s.FindDuplicateMnemonicsContext(context.Background())
+}
+
+// FindDuplicateMnemonicsContext is like FindDuplicateMnemonics but honors cancellation and deadlines from ctx
+func (s *AccountStore) FindDuplicateMnemonicsContext(ctx context.Context) ([][]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection not established")
+	}
+
+	// Accounts with an unknown path have a NULL one and are compared as if
+	// derived at DefaultDerivationPath
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT address, mnemonic, COALESCE(derivation_path, ?) FROM accounts
+		WHERE NOT archived AND mnemonic <> ''
+		ORDER BY id`, DefaultDerivationPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query duplicate mnemonics: %w", err)
+	}
+	defer rows.Close()
+
+	// Sealed mnemonics only compare equal once decrypted, so they are grouped here
+	type mnemonicAtPath struct{ mnemonic, path string }
+	members := make(map[mnemonicAtPath][]string)
+	for rows.Next() {
+		var address, mnemonic, path string
+		if err := rows.Scan(&address, &mnemonic, &path); err != nil {
+			return nil, fmt.Errorf("failed to scan duplicate mnemonic: %w", err)
+		}
+		if mnemonic, err = s.openSecret(address, mnemonic); err != nil {
+			return nil, err
+		}
+		key := mnemonicAtPath{mnemonic, path}
+		members[key] = append(members[key], address)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read duplicate mnemonics: %w", err)
+	}
+
+	// Order the groups by mnemonic and path, with addresses in insertion order
+	var shared []mnemonicAtPath
+	for key, addresses := range members {
+		if len(addresses) > 1 {
+			shared = append(shared, key)
+		}
+	}
+	sort.Slice(shared, func(i, j int) bool {
+		if shared[i].mnemonic != shared[j].mnemonic {
+			return shared[i].mnemonic < shared[j].mnemonic
+		}
+		return shared[i].path < shared[j].path
+	})
+
+	var groups [][]string
+	for _, key := range shared {
+		groups = append(groups, members[key])
+	}
+	return groups, nil
+}
+
+// Ping checks that the database is open and readable with the store's key.
+// It is cheap enough for frequent health checks, unlike CheckIntegrity.
+func (s *AccountStore) Ping() error {
+	return s.PingContext(context.Background())
+}
+
+// PingContext is like Ping but honors cancellation and deadlines from ctx. It
+// never waits for the store: while it is held exclusively it returns
+// ErrStoreBusy at once, so a health check does not hang behind a rekey.
+func (s *AccountStore) PingContext(ctx context.Context) error {
+	if !s.mu.TryRLock() {
+		return ErrStoreBusy
+	}
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return fmt.Errorf("database connection not established")
+	}
+
+	// Reading the schema decrypts the first page, so a lost file or key fails
+	// here, where a bare connection ping would not notice
+	var tables int
+	if err := s.db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master").Scan(&tables); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	return nil
+}
+
+// CheckIntegrity runs SQLCipher's page HMAC check and SQLite's structural
+// integrity check, returning an error that lists any problems reported
+func (s *AccountStore) CheckIntegrity() error {
+	return s.CheckIntegrityContext(context.Background())
+}
+
+// CheckIntegrityContext is like CheckIntegrity but honors cancellation and deadlines from ctx
+func (s *AccountStore) CheckIntegrityContext(ctx context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return fmt.Errorf("database connection not established")
+	}
+
+	// cipher_integrity_check reports nothing for a healthy file, while
+	// integrity_check reports a single "ok" row. Damaged pages also make
+	// integrity_check itself fail, so stop after the first check with findings.
+	for _, pragma := range []string{"cipher_integrity_check", "integrity_check"} {
+		messages, err := s.pragmaMessages(ctx, pragma)
+		if err != nil {
+			return fmt.Errorf("failed to run %s: %w", pragma, err)
+		}
+
+		var problems []string
+		for _, message := range messages {
+			if message != "ok" {
+				problems = append(problems, message)
+			}
+		}
+		if len(problems) > 0 {
+			return fmt.Errorf("%s reported problems: %s", pragma, strings.Join(problems, "; "))
+		}
+	}
+
+	return nil
+}
+
+// pragmaMessages runs a diagnostic PRAGMA and collects its single-column result rows
+func (s *AccountStore) pragmaMessages(ctx context.Context, pragma string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, "PRAGMA "+pragma)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []string
+	for rows.Next() {
+		var message string
+		if err := rows.Scan(&message); err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	return messages, rows.Err()
+}
+
+// CountAccounts returns the number of accounts stored in the database, not counting archived ones
+func (s *AccountStore) CountAccounts() (int, error) {
+	return s.CountAccountsContext(context.Background())
+}
+
+// CountAccountsContext is like CountAccounts but honors cancellation and deadlines from ctx
+func (s *AccountStore) CountAccountsContext(ctx context.Context) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return 0, fmt.Errorf("database connection not established")
+	}
+
+	var count int
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM accounts WHERE NOT archived").Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count accounts: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetAddresses returns the addresses of all accounts except archived ones, in
+// the order they were stored. No secrets are read, so it suits comparisons and
+// listings that must not expose keys.
+func (s *AccountStore) GetAddresses() ([]string, error) {
+	return s.GetAddressesContext(context.Background())
+}
+
+// GetAddressesContext is like GetAddresses but honors cancellation and deadlines from ctx
+func (s *AccountStore) GetAddressesContext(ctx context.Context) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection not established")
+	}
+
+	rows, err := s.db.QueryContext(ctx, "SELECT address FROM accounts WHERE NOT archived ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query addresses: %w", err)
+	}
+	defer rows.Close()
+
+	var addresses []string
+	for rows.Next() {
+		var address string
+		if err := rows.Scan(&address); err != nil {
+			return nil, fmt.Errorf("failed to scan address: %w", err)
+		}
+		addresses = append(addresses, address)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating address rows: %w", err)
+	}
+
+	return addresses, nil
+}
+
+// DeleteAccount archives the account with the given address. Archived accounts
+// keep their keys but are left out of GetAccounts, lookups, counts and exports
+// until Restore is called; PurgeAccount removes them for good. It returns
+// ErrAccountNotFound if no active account matched.
+func (s *AccountStore) DeleteAccount(address string) error {
+	return s.DeleteAccountContext(context.Background(), address)
+}
+
+// DeleteAccountContext is like DeleteAccount but honors cancellation and deadlines from ctx
+func (s *AccountStore) DeleteAccountContext(ctx context.Context, address string) error {
+	if err := s.setArchived(ctx, address, true); err != nil {
+		return err
+	}
+
+	s.config.Metrics.removed()
+	s.logger.Debug("archived account", "address", address)
+	return nil
+}
+
+// Restore makes an archived account active again. It returns
+// ErrAccountNotFound if no archived account matched.
+func (s *AccountStore) Restore(address string) error {
+	return s.RestoreContext(context.Background(), address)
+}
+
+// RestoreContext is like Restore but honors cancellation and deadlines from ctx
+func (s *AccountStore) RestoreContext(ctx context.Context, address string) error {
+	if err := s.setArchived(ctx, address, false); err != nil {
+		return err
+	}
+
+	s.config.Metrics.restored()
+	s.logger.Debug("restored account", "address", address)
+	return nil
+}
+
+// setArchived flips the archived flag of the account with the given address,
+// failing with ErrAccountNotFound unless it was in the opposite state
+func (s *AccountStore) setArchived(ctx context.Context, address string, archived bool) error {
+	if err := ValidateSeiAddress(address); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db == nil {
+		return fmt.Errorf("database connection not established")
+	}
+
+	result, err := s.db.ExecContext(ctx, "UPDATE accounts SET archived = ? WHERE address = ? AND archived = ?", archived, address, !archived)
+	if err != nil {
+		return fmt.Errorf("failed to update account: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check updated rows: %w", err)
+	}
+	if affected == 0 {
+		if archived {
+			return fmt.Errorf("%w: %s", ErrAccountNotFound, address)
+		}
+		return fmt.Errorf("%w: no archived account %s", ErrAccountNotFound, address)
+	}
+
+	return nil
+}
+
+// PurgeAccount permanently removes the account with the given address from the
+// database, whether it is active or archived. The mnemonic and private key
+// cannot be recovered afterwards except from a backup. It returns
+// ErrAccountNotFound if no account matched.
+func (s *AccountStore) PurgeAccount(address string) error {
+	return s.PurgeAccountContext(context.Background(), address)
+}
+
+// PurgeAccountContext is like PurgeAccount but honors cancellation and deadlines from ctx
+func (s *AccountStore) PurgeAccountContext(ctx context.Context, address string) error {
+	if err := ValidateSeiAddress(address); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db == nil {
+		return fmt.Errorf("database connection not established")
+	}
+
+	// Only active accounts are counted by the metrics gauge
+	var archived bool
+	err := s.db.QueryRowContext(ctx, "SELECT archived FROM accounts WHERE address = ?", address).Scan(&archived)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", ErrAccountNotFound, address)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to query account: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM accounts WHERE address = ?", address); err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+	// Another account of the same mnemonic, such as an HD sibling, takes over
+	// the purged account's mnemonic hash. Without a key yet, the next write does this.
+	if s.mnemonicHashKey != nil {
+		if _, err := s.hashStoredMnemonics(ctx, tx); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit purge: %w", err)
+	}
+
+	if !archived {
+		s.config.Metrics.removed()
+	}
+	s.logger.Debug("purged account", "address", address, "archived", archived)
+	return nil
+}
+
+// ListArchived returns the archived accounts in insertion order
+func (s *AccountStore) ListArchived() ([]*Account, error) {
+	return s.ListArchivedContext(context.Background())
+}
+
+// ListArchivedContext is like ListArchived but honors cancellation and deadlines from ctx
+func (s *AccountStore) ListArchivedContext(ctx context.Context) ([]*Account, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection not established")
+	}
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+accountColumns+" FROM accounts WHERE archived ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query archived accounts: %w", err)
+	}
+	defer rows.Close()
+
+	return hiddenAccounts(scanAccounts(rows))
+}
+
+// isArchived reports whether address belongs to an archived account. Query
+// errors count as not archived, since it only refines a lookup error. The
+// caller must hold s.mu.
+func (s *AccountStore) isArchived(ctx context.Context, address string) bool {
+	var archived bool
+	err := s.db.QueryRowContext(ctx, "SELECT archived FROM accounts WHERE address = ?", address).Scan(&archived)
+	return err == nil && archived
+}
+
+// SetLabel tags the account with the given address with a human-friendly label.
+// An empty label clears it. It returns ErrAccountNotFound if no account matched.
+func (s *AccountStore) SetLabel(address, label string) error {
+	return s.SetLabelContext(context.Background(), address, label)
+}
+
+// SetLabelContext is like SetLabel but honors cancellation and deadlines from ctx
+func (s *AccountStore) SetLabelContext(ctx context.Context, address, label string) error {
+	if err := ValidateSeiAddress(address); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db == nil {
+		return fmt.Errorf("database connection not established")
+	}
+
+	result, err := s.db.ExecContext(ctx, "UPDATE accounts SET label = ? WHERE address = ?", nullString(label), address)
+	if err != nil {
+		return fmt.Errorf("failed to set label: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check updated rows: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: %s", ErrAccountNotFound, address)
+	}
+
+	return nil
+}
+
+// UpdateAccount edits the mutable fields of the stored account with the given
+// address, active or archived. fn receives the account without its mnemonic and
+// private key and may change Label and DerivationPath; those are written back
+// in the same transaction the account was read in, so concurrent updates do not
+// overwrite each other. Changing any other field is an error, as is an invalid
+// derivation path. An error from fn aborts the update and is returned as is.
+// It returns ErrAccountNotFound if no account matched.
+func (s *AccountStore) UpdateAccount(address string, fn func(*Account) error) error {
+	return s.UpdateAccountContext(context.Background(), address, fn)
+}
+
+// UpdateAccountContext is like UpdateAccount but honors cancellation and deadlines from ctx
+func (s *AccountStore) UpdateAccountContext(ctx context.Context, address string, fn func(*Account) error) error {
+	if err := ValidateSeiAddress(address); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db == nil {
+		return fmt.Errorf("database connection not established")
+	}
+
+	return s.withBusyRetry(ctx, func() error {
+		return s.updateAccount(ctx, address, fn)
+	})
+}
+
+// updateAccount performs a single UpdateAccount transaction. The caller must hold s.mu.
+func (s *AccountStore) updateAccount(ctx context.Context, address string, fn func(*Account) error) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	account, err := scanAccount(tx.QueryRowContext(ctx, "SELECT "+accountColumns+" FROM accounts WHERE address = ?", address))
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", ErrAccountNotFound, address)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+
+	updated, err := applyAccountUpdate(account, fn)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE accounts SET label = ?, derivation_path = ? WHERE address = ?",
+		nullString(updated.Label), nullString(updated.DerivationPath), address)
+	if err != nil {
+		return fmt.Errorf("failed to update account: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit account update: %w", err)
+	}
+
+	s.logger.Debug("updated account", "address", address)
+	return nil
+}
+
+// applyAccountUpdate runs an UpdateAccount callback on a copy of stored with its
+// secrets cleared and returns the copy, checking that only the mutable fields
+// were changed
+func applyAccountUpdate(stored *Account, fn func(*Account) error) (*Account, error) {
+	before := *stored
+	before.Mnemonic, before.PrivateKey = "", ""
+	updated := before
+	if err := fn(&updated); err != nil {
+		return nil, err
+	}
+
+	// Put the mutable fields back to compare everything else
+	check := updated
+	check.Label, check.DerivationPath = before.Label, before.DerivationPath
+	if check != before {
+		return nil, fmt.Errorf("account %s: only the label and derivation path can be updated", stored.Address)
+	}
+	if updated.DerivationPath != "" {
+		if err := ValidateDerivationPath(updated.DerivationPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return &updated, nil
+}
+
+// sqliteTimeLayout is the format SQLite uses for CURRENT_TIMESTAMP (always UTC)
+const sqliteTimeLayout = "2006-01-02 15:04:05"
+
+// sqliteTime scans a SQLite timestamp column. Depending on the column's declared
+// type and how the value was written, the driver returns either a time.Time or
+// the raw text, so both are accepted. NULL scans as the zero time.
+type sqliteTime struct {
+	time.Time
+}
+
+// Scan implements sql.Scanner
+func (t *sqliteTime) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		t.Time = time.Time{}
+		return nil
+	case time.Time:
+		t.Time = v.UTC()
+		return nil
+	case []byte:
+		return t.parse(string(v))
+	case string:
+		return t.parse(v)
+	default:
+		return fmt.Errorf("unsupported timestamp type %T", value)
+	}
+}
+
+// parse accepts SQLite's default text layout as well as RFC 3339
+func (t *sqliteTime) parse(value string) error {
+	for _, layout := range []string{sqliteTimeLayout, time.RFC3339Nano} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			t.Time = parsed.UTC()
+			return nil
+		}
+	}
+	return fmt.Errorf("unrecognized timestamp %q", value)
+}
+
+// nullString maps an empty string to SQL NULL
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// ExportAccountsJSON exports all accounts to a JSON file (for backup purposes)
+func (s *AccountStore) ExportAccountsJSON(filePath string) error {
+	accounts, err := s.getAccounts(context.Background(), true)
+	if err != nil {
+		return fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	data, err := json.MarshalIndent(accounts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal accounts to JSON: %w", err)
+	}
+
+	if err := s.writeFile(filePath, data); err != nil {
+		return fmt.Errorf("failed to write accounts to file: %w", err)
+	}
+
+	return nil
+}
+
+// PublicAccount holds the non-secret fields of an account that are safe to share
+type PublicAccount struct {
+	Address string `json:"address"`
+	PubKey  string `json:"public_key"`
+}
+
+// ExportAccountsNDJSON streams all accounts to w as newline-delimited JSON, one
+// object per line in insertion order. Rows are encoded as they are read, so
+// memory use stays flat however many accounts are stored.
+func (s *AccountStore) ExportAccountsNDJSON(w io.Writer) error {
+	return s.ExportAccountsNDJSONContext(context.Background(), w)
+}
+
+// ExportAccountsNDJSONContext is like ExportAccountsNDJSON but honors cancellation and deadlines from ctx
+func (s *AccountStore) ExportAccountsNDJSONContext(ctx context.Context, w io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return fmt.Errorf("database connection not established")
+	}
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+accountColumns+" FROM accounts WHERE NOT archived ORDER BY id")
+	if err != nil {
+		return fmt.Errorf("failed to query accounts: %w", err)
+	}
+	defer rows.Close()
+
+	// Encode appends a newline after each value, which is exactly the NDJSON framing
+	encoder := json.NewEncoder(w)
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan account row: %w", err)
+		}
+		if err := s.revealSecrets(account); err != nil {
+			return err
+		}
+		if err := encoder.Encode(account); err != nil {
+			return fmt.Errorf("failed to write account %s: %w", account.Address, err)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating account rows: %w", err)
+	}
+
+	return nil
+}
+
+// ExportAddressesJSON exports only the addresses and public keys of all accounts
+// to a JSON file, so a fundable address list can be shared without exposing secrets
+func (s *AccountStore) ExportAddressesJSON(filePath string) error {
+	accounts, err := s.GetAccounts()
+	if err != nil {
+		return fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	public := make([]PublicAccount, 0, len(accounts))
+	for _, account := range accounts {
+		public = append(public, PublicAccount{Address: account.Address, PubKey: account.PubKey})
+	}
+
+	data, err := json.MarshalIndent(public, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal addresses to JSON: %w", err)
+	}
+
+	if err := s.writeFile(filePath, data); err != nil {
+		return fmt.Errorf("failed to write addresses to file: %w", err)
+	}
+
+	return nil
+}
+
+// ExportAddressesNDJSON is like ExportAccountsNDJSON but writes only the
+// address and public key of each account
+func (s *AccountStore) ExportAddressesNDJSON(w io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return fmt.Errorf("database connection not established")
+	}
+
+	rows, err := s.db.QueryContext(context.Background(), "SELECT address, public_key FROM accounts WHERE NOT archived ORDER BY id")
+	if err != nil {
+		return fmt.Errorf("failed to query accounts: %w", err)
+	}
+	defer rows.Close()
+
+	encoder := json.NewEncoder(w)
+	for rows.Next() {
+		var account PublicAccount
+		if err := rows.Scan(&account.Address, &account.PubKey); err != nil {
+			return fmt.Errorf("failed to scan account row: %w", err)
+		}
+		if err := encoder.Encode(account); err != nil {
+			return fmt.Errorf("failed to write account %s: %w", account.Address, err)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating account rows: %w", err)
+	}
+
+	return nil
+}
+
+// ExportAccountsCSV exports all accounts to a CSV file with a header row
+func (s *AccountStore) ExportAccountsCSV(filePath string) error {
+	accounts, err := s.getAccounts(context.Background(), true)
+	if err != nil {
+		return fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	records := make([][]string, 0, len(accounts))
+	for _, account := range accounts {
+		records = append(records, []string{account.Address, account.Mnemonic, account.PubKey, account.PrivateKey})
+	}
+	return s.writeCSV(filePath, []string{"address", "mnemonic", "public_key", "private_key"}, records)
+}
+
+// ExportAddressesCSV is like ExportAddressesJSON but writes a CSV file with
+// address and public_key columns
+func (s *AccountStore) ExportAddressesCSV(filePath string) error {
+	accounts, err := s.GetAccounts()
+	if err != nil {
+		return fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	records := make([][]string, 0, len(accounts))
+	for _, account := range accounts {
+		records = append(records, []string{account.Address, account.PubKey})
+	}
+	return s.writeCSV(filePath, []string{"address", "public_key"}, records)
+}
+
+// writeCSV writes a header row and records to filePath with the configured file mode
+func (s *AccountStore) writeCSV(filePath string, header []string, records [][]string) error {
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, s.config.fileMode())
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file: %w", err)
+	}
+	defer file.Close()
+	if err := chmod(filePath, s.config.fileMode()); err != nil {
+		return fmt.Errorf("failed to set CSV file permissions: %w", err)
+	}
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, record := range records {
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write accounts to file: %w", err)
+	}
+
+	return file.Close()
+}
+
+// Close closes the database connection
+func (s *AccountStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.secretsKey)
+	s.secretsKey = nil
+	clear(s.mnemonicHashKey)
+	s.mnemonicHashKey = nil
+
+	if s.db != nil {
+		err := s.db.Close()
+		s.db = nil
+		return err
+	}
+	return nil
+}
+
+// Rekey changes the database encryption password. The rekey runs on a single
+// connection which is verified with the new key, then the connection pool is
+// reopened so every subsequent connection uses newPassword.
+func (s *AccountStore) Rekey(newPassword string) error {
+	if newPassword == "" {
+		return fmt.Errorf("new password must not be empty")
+	}
+
+	s.mu.Lock()
+	if s.db == nil {
+		s.mu.Unlock()
+		return fmt.Errorf("database connection not established")
+	}
+
+	ctx := context.Background()
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	s.logger.Debug("rekeying database", "path", s.dbPath)
+
+	// PRAGMA does not accept bound parameters, so quote the key as a string literal
+	rekey := fmt.Sprintf("PRAGMA rekey = '%s'", strings.ReplaceAll(newPassword, "'", "''"))
+	if _, err := conn.ExecContext(ctx, rekey); err != nil {
+		conn.Close()
+		s.mu.Unlock()
+		return fmt.Errorf("failed to rekey database: %w", err)
+	}
+
+	// The rekeyed connection must still be able to read the data
+	var count int
+	err = conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM accounts").Scan(&count)
+	conn.Close()
+	if err != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("failed to verify rekeyed database: %w", err)
+	}
+
+	// Pooled connections were opened with the old key, so start a fresh pool
+	if err := s.db.Close(); err != nil {
+		s.logger.Warn("error closing database after rekey", "error", err)
+	}
+	s.db = nil
+	s.password = newPassword
+	s.mu.Unlock()
+
+	if err := s.openDB(); err != nil {
+		return fmt.Errorf("failed to reopen database with new password: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteDatabase removes the database file (use with caution)
+func (s *AccountStore) DeleteDatabase() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db != nil {
+		if err := s.db.Close(); err != nil {
+			s.logger.Warn("error closing database before deletion", "error", err)
+		}
+		s.db = nil
+	}
+
+	return os.Remove(s.dbPath)
+}
@@ -0,0 +1,613 @@
+package wallet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpenWithWrongPassword(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewAccountStore(dir, "key-a-123456")
+	if err != nil {
+		t.Fatalf("NewAccountStore() with key A error = %v", err)
+	}
+	if _, err := store.SaveAccount(newTestAccount(t)); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+	store.Close()
+
+	if wrong, err := NewAccountStore(dir, "key-b-123456"); !errors.Is(err, ErrWrongPassword) {
+		if err == nil {
+			wrong.Close()
+		}
+		t.Fatalf("NewAccountStore() with key B error = %v, want ErrWrongPassword", err)
+	}
+
+	// The failed attempt must leave the database readable with the right key
+	store, err = NewAccountStore(dir, "key-a-123456")
+	if err != nil {
+		t.Fatalf("NewAccountStore() with key A again error = %v", err)
+	}
+	defer store.Close()
+	if count, err := store.CountAccounts(); err != nil || count != 1 {
+		t.Errorf("CountAccounts() = %d, %v, want 1", count, err)
+	}
+}
+
+func TestDefaultPassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		refuse   bool
+		wantErr  error
+		wantWarn bool
+	}{
+		{"empty password", "", false, nil, true},
+		{"default password", DefaultDBPassword, false, nil, true},
+		{"empty password refused", "", true, ErrDefaultPassword, false},
+		{"default password refused", DefaultDBPassword, true, ErrDefaultPassword, false},
+		{"own password", testPassword, true, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			config := DefaultStoreConfig()
+			config.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+			config.RefuseDefaultPassword = tt.refuse
+
+			dir := t.TempDir()
+			store, err := NewAccountStoreWithConfig(dir, tt.password, config)
+			if err == nil {
+				store.Close()
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewAccountStoreWithConfig() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, statErr := os.Stat(filepath.Join(dir, ProfileFileName(""))); tt.wantErr != nil && statErr == nil {
+				t.Error("a refused open created the database")
+			}
+			if warned := strings.Contains(logs.String(), "default password"); warned != tt.wantWarn {
+				t.Errorf("warned about the default password = %v, want %v (log: %q)", warned, tt.wantWarn, logs.String())
+			}
+		})
+	}
+}
+
+func TestEncryptedExportRoundTrip(t *testing.T) {
+	accounts := []*Account{newTestAccount(t), newTestAccount(t)}
+	accounts[0].Label = "treasury"
+	store := newTestStore(t)
+	if _, err := store.SaveAccounts(accounts); err != nil {
+		t.Fatalf("SaveAccounts() error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "accounts.enc")
+	if err := store.ExportAccountsEncrypted(path, ""); err == nil {
+		t.Error("ExportAccountsEncrypted() with an empty passphrase succeeded")
+	}
+	if err := store.ExportAccountsEncrypted(path, "export-passphrase"); err != nil {
+		t.Fatalf("ExportAccountsEncrypted() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if bytes.Contains(data, []byte(accounts[0].Mnemonic)) || bytes.Contains(data, []byte(accounts[0].PrivateKey)) {
+		t.Fatal("encrypted export contains a plaintext secret")
+	}
+
+	target := newTestStore(t)
+	if _, err := target.ImportAccountsEncrypted(path, "wrong-passphrase"); !errors.Is(err, ErrDecryptionFailed) {
+		t.Errorf("ImportAccountsEncrypted() with the wrong passphrase error = %v, want ErrDecryptionFailed", err)
+	}
+	if count, err := target.CountAccounts(); err != nil || count != 0 {
+		t.Errorf("CountAccounts() after a failed import = %d, %v, want 0", count, err)
+	}
+
+	if inserted, err := target.ImportAccountsEncrypted(path, "export-passphrase"); err != nil || inserted != 2 {
+		t.Fatalf("ImportAccountsEncrypted() = %d, %v, want 2, nil", inserted, err)
+	}
+	for _, want := range accounts {
+		got, err := target.GetAccountByAddress(want.Address)
+		if err != nil {
+			t.Fatalf("GetAccountByAddress() error = %v", err)
+		}
+		if got.Mnemonic != want.Mnemonic || got.PrivateKey != want.PrivateKey || got.Label != want.Label {
+			t.Errorf("imported account %s does not match the exported one", want.Address)
+		}
+	}
+}
+
+func TestVerifyAllIncludesArchived(t *testing.T) {
+	store := newTestStore(t)
+	active, archived := newTestAccount(t), newTestAccount(t)
+	if _, err := store.SaveAccounts([]*Account{active, archived}); err != nil {
+		t.Fatalf("SaveAccounts() error = %v", err)
+	}
+	if err := store.DeleteAccount(archived.Address); err != nil {
+		t.Fatalf("DeleteAccount() error = %v", err)
+	}
+	if failures, err := store.VerifyAll(); err != nil || len(failures) != 0 {
+		t.Fatalf("VerifyAll() = %v, %v, want no failures", failures, err)
+	}
+
+	// Give the archived account the public key of the active one
+	if _, err := store.db.Exec("UPDATE accounts SET public_key = ? WHERE address = ?", active.PubKey, archived.Address); err != nil {
+		t.Fatal(err)
+	}
+	failures, err := store.VerifyAll()
+	if err != nil {
+		t.Fatalf("VerifyAll() error = %v", err)
+	}
+	if len(failures) != 1 || failures[0].Address != archived.Address {
+		t.Errorf("VerifyAll() = %v, want the archived account %s", failures, archived.Address)
+	}
+}
+
+func TestFindDuplicateMnemonicsIgnoresHDSiblings(t *testing.T) {
+	hd, err := DeriveAccountsFromMnemonic(testMnemonic, DefaultCoinType, 3)
+	if err != nil {
+		t.Fatalf("DeriveAccountsFromMnemonic() error = %v", err)
+	}
+
+	store := newTestStore(t)
+	if _, err := store.SaveAccounts(append(hd, newTestAccount(t))); err != nil {
+		t.Fatalf("SaveAccounts() error = %v", err)
+	}
+
+	if groups, err := store.FindDuplicateMnemonics(); err != nil || len(groups) != 0 {
+		t.Errorf("FindDuplicateMnemonics() = %v, %v, want no groups", groups, err)
+	}
+}
+
+func TestFindDuplicateMnemonicsIgnoresArchived(t *testing.T) {
+	plain, err := ImportAccount(testMnemonic, "", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+	// The same phrase and path with a passphrase is an accidental re-import
+	withPassphrase, err := ImportAccount(testMnemonic, "TREZOR", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() with passphrase error = %v", err)
+	}
+
+	config := testConfig()
+	config.AllowSharedMnemonics = true
+	store := openTestStore(t, t.TempDir(), config)
+	if _, err := store.SaveAccounts([]*Account{plain, withPassphrase, newTestAccount(t)}); err != nil {
+		t.Fatalf("SaveAccounts() error = %v", err)
+	}
+
+	groups, err := store.FindDuplicateMnemonics()
+	if err != nil {
+		t.Fatalf("FindDuplicateMnemonics() error = %v", err)
+	}
+	if len(groups) != 1 || len(groups[0]) != 2 || groups[0][0] != plain.Address || groups[0][1] != withPassphrase.Address {
+		t.Fatalf("FindDuplicateMnemonics() = %v, want one group of the two accounts at %s", groups, DefaultDerivationPath)
+	}
+
+	if err := store.DeleteAccount(withPassphrase.Address); err != nil {
+		t.Fatalf("DeleteAccount() error = %v", err)
+	}
+	if groups, err = store.FindDuplicateMnemonics(); err != nil || len(groups) != 0 {
+		t.Errorf("FindDuplicateMnemonics() after archiving = %v, %v, want no groups", groups, err)
+	}
+}
+
+func TestFindDuplicateMnemonicsTreatsMissingPathAsDefault(t *testing.T) {
+	plain, err := ImportAccount(testMnemonic, "", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+	withPassphrase, err := ImportAccount(testMnemonic, "TREZOR", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() with passphrase error = %v", err)
+	}
+
+	config := testConfig()
+	config.AllowSharedMnemonics = true
+	store := openTestStore(t, t.TempDir(), config)
+	if _, err := store.SaveAccounts([]*Account{plain, withPassphrase}); err != nil {
+		t.Fatalf("SaveAccounts() error = %v", err)
+	}
+	// Accounts whose path is unknown have none
+	if _, err := store.db.Exec("UPDATE accounts SET derivation_path = NULL WHERE address = ?", plain.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	groups, err := store.FindDuplicateMnemonics()
+	if err != nil {
+		t.Fatalf("FindDuplicateMnemonics() error = %v", err)
+	}
+	if len(groups) != 1 || len(groups[0]) != 2 {
+		t.Errorf("FindDuplicateMnemonics() = %v, want one group of %s and %s", groups, plain.Address, withPassphrase.Address)
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	store := newTestStore(t)
+	accounts := []*Account{newTestAccount(t), newTestAccount(t), newTestAccount(t)}
+	for _, account := range accounts {
+		if _, err := store.SaveAccount(account); err != nil {
+			t.Fatalf("SaveAccount() error = %v", err)
+		}
+	}
+
+	if err := store.DeleteAccount(accounts[1].Address); err != nil {
+		t.Fatalf("DeleteAccount() error = %v", err)
+	}
+	if count, err := store.CountAccounts(); err != nil || count != 2 {
+		t.Errorf("CountAccounts() = %d, %v, want 2", count, err)
+	}
+	if _, err := store.GetAccountByAddress(accounts[1].Address); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("GetAccountByAddress() of the deleted account error = %v, want ErrAccountNotFound", err)
+	}
+	if err := store.DeleteAccount(accounts[1].Address); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("DeleteAccount() again error = %v, want ErrAccountNotFound", err)
+	}
+}
+
+func TestUpdateAccountKeepsKeysImmutable(t *testing.T) {
+	type updater interface {
+		Store
+		UpdateAccount(address string, fn func(*Account) error) error
+	}
+	stores := map[string]updater{
+		"sqlcipher": newTestStore(t),
+		"memory":    NewMemoryStore(),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			account := newTestAccount(t)
+			if _, err := store.SaveAccount(account); err != nil {
+				t.Fatalf("SaveAccount() error = %v", err)
+			}
+
+			immutable := map[string]func(*Account){
+				"address":     func(a *Account) { a.Address = newTestAccount(t).Address },
+				"public key":  func(a *Account) { a.PubKey = "AAAA" },
+				"private key": func(a *Account) { a.PrivateKey = "00" },
+				"mnemonic":    func(a *Account) { a.Mnemonic = testMnemonic },
+			}
+			for field, edit := range immutable {
+				err := store.UpdateAccount(account.Address, func(a *Account) error {
+					a.Label = "renamed"
+					edit(a)
+					return nil
+				})
+				if err == nil {
+					t.Errorf("UpdateAccount() changing the %s succeeded", field)
+				}
+			}
+
+			stored, err := store.GetAccountByAddress(account.Address)
+			if err != nil {
+				t.Fatalf("GetAccountByAddress() error = %v", err)
+			}
+			if stored.Label != account.Label || stored.PubKey != account.PubKey ||
+				stored.PrivateKey != account.PrivateKey || stored.Mnemonic != account.Mnemonic {
+				t.Errorf("stored account changed after refused updates: %v, want %v", stored, account)
+			}
+
+			err = store.UpdateAccount(account.Address, func(a *Account) error {
+				a.Label = "renamed"
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("UpdateAccount() of the label error = %v", err)
+			}
+			if stored, err = store.GetAccountByAddress(account.Address); err != nil {
+				t.Fatalf("GetAccountByAddress() error = %v", err)
+			}
+			if stored.Label != "renamed" {
+				t.Errorf("label after UpdateAccount() = %q, want %q", stored.Label, "renamed")
+			}
+		})
+	}
+}
+
+func TestRekey(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewAccountStore(dir, testPassword)
+	if err != nil {
+		t.Fatalf("NewAccountStore() error = %v", err)
+	}
+	account := newTestAccount(t)
+	if _, err := store.SaveAccount(account); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+
+	const newPassword = "rotated-password-456"
+	if err := store.Rekey(newPassword); err != nil {
+		t.Fatalf("Rekey() error = %v", err)
+	}
+	// The open store keeps working with the new key
+	if count, err := store.CountAccounts(); err != nil || count != 1 {
+		t.Errorf("CountAccounts() after Rekey() = %d, %v, want 1", count, err)
+	}
+	store.Close()
+
+	if old, err := NewAccountStore(dir, testPassword); !errors.Is(err, ErrWrongPassword) {
+		if err == nil {
+			old.Close()
+		}
+		t.Errorf("NewAccountStore() with the old password error = %v, want ErrWrongPassword", err)
+	}
+
+	store, err = NewAccountStore(dir, newPassword)
+	if err != nil {
+		t.Fatalf("NewAccountStore() with the new password error = %v", err)
+	}
+	defer store.Close()
+	if got, err := store.GetAccountByAddress(account.Address); err != nil || got.Mnemonic != account.Mnemonic {
+		t.Errorf("GetAccountByAddress() after reopening = %v, %v, want the saved account", got, err)
+	}
+}
+
+// BenchmarkGetAccountByAddressParallel compares concurrent lookups on a single
+// connection with the default pool, where readers run in parallel
+func BenchmarkGetAccountByAddressParallel(b *testing.B) {
+	accounts := make([]*Account, 64)
+	for i := range accounts {
+		accounts[i] = newTestAccount(b)
+	}
+
+	for _, bench := range []struct {
+		name     string
+		maxConns int
+	}{
+		{"one connection", 1},
+		{"pool", 0},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			config := testConfig()
+			config.MaxOpenConns = bench.maxConns
+			store := openTestStore(b, b.TempDir(), config)
+			if _, err := store.SaveAccounts(accounts); err != nil {
+				b.Fatalf("SaveAccounts() error = %v", err)
+			}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					if _, err := store.GetAccountByAddress(accounts[i%len(accounts)].Address); err != nil {
+						b.Error(err)
+						return
+					}
+					i++
+				}
+			})
+		})
+	}
+}
+
+func TestDeriveChangeAddresses(t *testing.T) {
+	account, err := ImportAccount(testMnemonic, "", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+	store := newTestStore(t)
+	if _, err := store.SaveAccount(account); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+
+	receive, err := store.DeriveExtraAddresses(account.Address, DefaultCoinType, 0, 3)
+	if err != nil {
+		t.Fatalf("DeriveExtraAddresses() error = %v", err)
+	}
+	change, err := store.DeriveChangeAddresses(account.Address, DefaultCoinType, 0, 3)
+	if err != nil {
+		t.Fatalf("DeriveChangeAddresses() error = %v", err)
+	}
+	if receive[0].Address != account.Address {
+		t.Errorf("receive address 0 = %s, want the account's %s", receive[0].Address, account.Address)
+	}
+	for i := range change {
+		if change[i].Address == receive[i].Address {
+			t.Errorf("change and receive address %d are both %s", i, change[i].Address)
+		}
+	}
+
+	first, err := deriveFromMnemonic(AlgoSecp256k1, testMnemonic, "", "m/44'/118'/0'/1/0")
+	if err != nil {
+		t.Fatalf("deriveFromMnemonic() error = %v", err)
+	}
+	if change[0] != (DerivedAddress{Path: "m/44'/118'/0'/1/0", Address: first.Address}) {
+		t.Errorf("change address 0 = %+v, want %s derived at m/44'/118'/0'/1/0", change[0], first.Address)
+	}
+}
+
+func TestDeriveExtraAddressesKeepsTheAccountLevel(t *testing.T) {
+	account, err := deriveFromMnemonic(AlgoSecp256k1, testMnemonic, "", "m/44'/118'/2'/0/0")
+	if err != nil {
+		t.Fatalf("deriveFromMnemonic() error = %v", err)
+	}
+	store := newTestStore(t)
+	if _, err := store.SaveAccount(account); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+
+	derived, err := store.DeriveExtraAddresses(account.Address, DefaultCoinType, 1, 2)
+	if err != nil {
+		t.Fatalf("DeriveExtraAddresses() error = %v", err)
+	}
+	for i, d := range derived {
+		path := fmt.Sprintf("m/44'/118'/2'/0/%d", i+1)
+		want, err := deriveFromMnemonic(AlgoSecp256k1, testMnemonic, "", path)
+		if err != nil {
+			t.Fatalf("deriveFromMnemonic() error = %v", err)
+		}
+		if d != (DerivedAddress{Path: path, Address: want.Address}) {
+			t.Errorf("DeriveExtraAddresses()[%d] = %+v, want %s at %s", i, d, want.Address, path)
+		}
+	}
+}
+
+func TestFindByMnemonic(t *testing.T) {
+	store := newTestStore(t)
+	if _, err := store.FindByMnemonic(testMnemonic); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("FindByMnemonic() on an empty store error = %v, want ErrAccountNotFound", err)
+	}
+
+	// An ed25519 account stored when keys of every algorithm came from the mnemonic
+	legacy, err := deriveFromMnemonic(AlgoEd25519, testMnemonic, "", DefaultDerivationPath)
+	if err != nil {
+		t.Fatalf("deriveFromMnemonic() error = %v", err)
+	}
+	if _, err := store.SaveAccount(legacy); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+	if got, err := store.FindByMnemonic("  " + testMnemonic); err != nil || got.Address != legacy.Address {
+		t.Errorf("FindByMnemonic() = %v, %v, want the ed25519 account %s", got, err, legacy.Address)
+	}
+
+	// The secp256k1 account comes first
+	config := testConfig()
+	config.AllowSharedMnemonics = true
+	shared := openTestStore(t, t.TempDir(), config)
+	account, err := ImportAccount(testMnemonic, "", DefaultCoinType)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+	if _, err := shared.SaveAccounts([]*Account{legacy, account}); err != nil {
+		t.Fatalf("SaveAccounts() error = %v", err)
+	}
+	got, err := shared.FindByMnemonic(testMnemonic)
+	if err != nil || got.Address != account.Address || got.PrivateKey != account.PrivateKey {
+		t.Errorf("FindByMnemonic() = %v, %v, want the secp256k1 account %s with its secrets", got, err, account.Address)
+	}
+}
+
+func TestFindByPubKeyPrefix(t *testing.T) {
+	store := newTestStore(t)
+	accounts := []*Account{newTestAccount(t), newTestAccount(t), newTestAccount(t)}
+	if _, err := store.SaveAccounts(accounts); err != nil {
+		t.Fatalf("SaveAccounts() error = %v", err)
+	}
+	if err := store.DeleteAccount(accounts[2].Address); err != nil {
+		t.Fatalf("DeleteAccount() error = %v", err)
+	}
+
+	// Every compressed secp256k1 key starts with 02 or 03
+	all, err := store.FindByPubKeyPrefix("0")
+	if err != nil {
+		t.Fatalf("FindByPubKeyPrefix() error = %v", err)
+	}
+	if len(all) != 2 || all[0].Address != accounts[0].Address || all[1].Address != accounts[1].Address {
+		t.Errorf("FindByPubKeyPrefix(\"0\") returned %d accounts, want the 2 active ones in insertion order", len(all))
+	}
+
+	found, err := store.FindByPubKeyPrefix(strings.ToUpper(accounts[1].PubKey[:20]))
+	if err != nil {
+		t.Fatalf("FindByPubKeyPrefix() error = %v", err)
+	}
+	if len(found) != 1 || found[0].Address != accounts[1].Address {
+		t.Errorf("FindByPubKeyPrefix() with an upper-case prefix = %v, want %s", found, accounts[1].Address)
+	}
+	if found[0].PrivateKey != accounts[1].PrivateKey {
+		t.Error("FindByPubKeyPrefix() did not return the account's private key")
+	}
+
+	if archived, err := store.FindByPubKeyPrefix(accounts[2].PubKey); len(archived) != 0 || err != nil {
+		t.Errorf("FindByPubKeyPrefix() of an archived key = %v, %v, want nothing", archived, err)
+	}
+
+	for _, prefix := range []string{"", "02%", "0x02", "g"} {
+		if _, err := store.FindByPubKeyPrefix(prefix); err == nil {
+			t.Errorf("FindByPubKeyPrefix(%q) succeeded, want an error", prefix)
+		}
+	}
+}
+
+func TestPingDoesNotWaitForExclusiveOperations(t *testing.T) {
+	store := newTestStore(t)
+	if err := store.Ping(); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	// Hold the store the way Rekey and Close do
+	store.mu.Lock()
+	err := store.Ping()
+	store.mu.Unlock()
+	if !errors.Is(err, ErrStoreBusy) {
+		t.Errorf("Ping() of a store held exclusively error = %v, want ErrStoreBusy", err)
+	}
+	if err := store.Ping(); err != nil {
+		t.Errorf("Ping() after the exclusive operation error = %v", err)
+	}
+}
+
+func TestReadOnlyStoreLeavesTheFileUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	store := openTestStore(t, dir, testConfig())
+	account := newTestAccount(t)
+	if _, err := store.SaveAccount(account); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+	store.Close()
+	path := filepath.Join(dir, DBFileName)
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Opening with a secrets passphrase would seal the stored accounts on the first write
+	config := testConfig()
+	config.ReadOnly = true
+	config.SecretsPassphrase = "secrets passphrase"
+	store, err = OpenAccountStoreFile(path, testPassword, config)
+	if err != nil {
+		t.Fatalf("OpenAccountStoreFile() read-only error = %v", err)
+	}
+	if addresses, err := store.GetAddresses(); err != nil || len(addresses) != 1 || addresses[0] != account.Address {
+		t.Errorf("GetAddresses() = %v, %v, want [%s]", addresses, err, account.Address)
+	}
+	if _, err := store.SaveAccount(newTestAccount(t)); err == nil {
+		t.Error("SaveAccount() into a read-only store succeeded")
+	}
+	store.Close()
+
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("opening the database read-only changed the file")
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing")
+	if store, err := NewAccountStoreWithConfig(missing, testPassword, config); err == nil {
+		store.Close()
+		t.Error("NewAccountStoreWithConfig() read-only of a missing database succeeded")
+	}
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Errorf("read-only open created %s: %v", missing, err)
+	}
+}
+
+func TestReadOnlyStoreRefusesOtherSchemaVersions(t *testing.T) {
+	for _, version := range []int{LatestSchemaVersion - 1, LatestSchemaVersion + 1} {
+		dir := t.TempDir()
+		store := openTestStore(t, dir, testConfig())
+		if _, err := store.db.Exec(fmt.Sprintf("PRAGMA user_version = %d", version)); err != nil {
+			t.Fatal(err)
+		}
+		store.Close()
+
+		config := testConfig()
+		config.ReadOnly = true
+		if store, err := OpenAccountStoreFile(filepath.Join(dir, DBFileName), testPassword, config); !errors.Is(err, ErrSchemaVersion) {
+			if err == nil {
+				store.Close()
+			}
+			t.Errorf("OpenAccountStoreFile() read-only at schema version %d error = %v, want ErrSchemaVersion", version, err)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+//go:build windows
+
+package wallet
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDatabaseURIWindowsPath(t *testing.T) {
+	got, err := databaseURI(`C:\Users\Jane Doe\.sei-accounts\sei_accounts.db`)
+	if err != nil {
+		t.Fatalf("databaseURI() error = %v", err)
+	}
+	if want := "file:///C:/Users/Jane%20Doe/.sei-accounts/sei_accounts.db"; got != want {
+		t.Errorf("databaseURI() = %q, want %q", got, want)
+	}
+}
+
+func TestOpenStoreInWindowsPathWithSpaces(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "Jane Doe", "sei accounts")
+	store := openTestStore(t, dir, testConfig())
+	account := newTestAccount(t)
+	if _, err := store.SaveAccount(account); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+	store.Close()
+
+	store = openTestStore(t, dir, testConfig())
+	if _, err := store.GetAccountByAddress(account.Address); err != nil {
+		t.Errorf("GetAccountByAddress() after reopening error = %v", err)
+	}
+}
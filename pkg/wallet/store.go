@@ -0,0 +1,49 @@
+package wallet
+
+// Store is the account storage used by the CLI and library callers. AccountStore
+// is the default, encrypted SQLCipher implementation; MemoryStore keeps accounts
+// in memory only.
+type Store interface {
+	// SaveAccount stores an account, reporting false if its address already
+	// exists. An account whose mnemonic is already stored under another address
+	// is refused with a SharedMnemonicError unless shared mnemonics are allowed.
+	SaveAccount(account *Account) (bool, error)
+	// SaveAccounts stores a batch of accounts, skipping existing addresses, and
+	// returns how many were inserted. Accounts refused for a shared mnemonic are
+	// listed in a SharedMnemonicError while the rest of the batch is saved.
+	SaveAccounts(accounts []*Account) (int, error)
+	// GetAccounts returns every account that is not archived, in insertion order
+	GetAccounts() ([]*Account, error)
+	// GetAccountsPage returns up to limit accounts in insertion order, skipping offset
+	GetAccountsPage(limit, offset int) ([]*Account, error)
+	// GetAccountsSortedPage is like GetAccountsPage but orders by one of the SortBy values
+	GetAccountsSortedPage(sortBy string, limit, offset int) ([]*Account, error)
+	// GetAccountByAddress returns the account with the given address or ErrAccountNotFound
+	GetAccountByAddress(address string) (*Account, error)
+	// SuggestAddress returns the active stored address closest to a mistyped
+	// one, for a "did you mean" hint after a failed lookup
+	SuggestAddress(address string) (string, bool, error)
+	// CountAccounts returns the number of stored accounts
+	CountAccounts() (int, error)
+	// DeleteAccount archives an account or returns ErrAccountNotFound
+	DeleteAccount(address string) error
+	// PurgeAccount permanently removes an active or archived account
+	PurgeAccount(address string) error
+	// ListArchived returns the archived accounts in insertion order
+	ListArchived() ([]*Account, error)
+	// Restore makes an archived account active again
+	Restore(address string) error
+	// RotateAccount archives an account and stores a freshly generated successor
+	RotateAccount(oldAddress, newLabel string) (*Account, error)
+	// SetLabel sets or, when empty, clears an account's label
+	SetLabel(address, label string) error
+	// UpdateAccount edits an account's label and derivation path through fn
+	UpdateAccount(address string, fn func(*Account) error) error
+	// Close releases the store's resources
+	Close() error
+}
+
+var (
+	_ Store = (*AccountStore)(nil)
+	_ Store = (*MemoryStore)(nil)
+)
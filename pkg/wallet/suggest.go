@@ -0,0 +1,92 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+)
+
+// maxSuggestionDistance is the largest edit distance at which a stored address
+// is offered as a correction for a mistyped one
+const maxSuggestionDistance = 3
+
+// SuggestAddress returns the active stored address closest to address by edit
+// distance, if one is within a few characters, so a failed lookup can offer a
+// correction. A stored address, archived ones included, gets no suggestion.
+// Every stored address is compared, so call it only after a lookup misses,
+// and only where the suggestion is shown to a user.
+func (s *AccountStore) SuggestAddress(address string) (string, bool, error) {
+	return s.SuggestAddressContext(context.Background(), address)
+}
+
+// SuggestAddressContext is like SuggestAddress but honors cancellation and deadlines from ctx
+func (s *AccountStore) SuggestAddressContext(ctx context.Context, address string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return "", false, fmt.Errorf("database connection not established")
+	}
+
+	rows, err := s.db.QueryContext(ctx, "SELECT address, archived FROM accounts")
+	if err != nil {
+		return "", false, fmt.Errorf("failed to query addresses: %w", err)
+	}
+	defer rows.Close()
+
+	var addresses []string
+	for rows.Next() {
+		var stored string
+		var archived bool
+		if err := rows.Scan(&stored, &archived); err != nil {
+			return "", false, fmt.Errorf("failed to scan address: %w", err)
+		}
+		if stored == address {
+			return "", false, nil
+		}
+		if !archived {
+			addresses = append(addresses, stored)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", false, fmt.Errorf("failed to read addresses: %w", err)
+	}
+
+	suggestion, ok := closestMatch(address, addresses)
+	return suggestion, ok, nil
+}
+
+// closestMatch returns the candidate nearest to target by Levenshtein distance,
+// if one is within maxSuggestionDistance
+func closestMatch(target string, candidates []string) (string, bool) {
+	best, bestDistance := "", maxSuggestionDistance+1
+	for _, candidate := range candidates {
+		if d := levenshtein(target, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best, bestDistance <= maxSuggestionDistance
+}
+
+// levenshtein returns the number of single-byte insertions, deletions and
+// substitutions needed to turn a into b. Addresses are ASCII, so bytes suffice.
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,80 @@
+package wallet
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"sei1abc", "sei1abc", 0},
+		{"sei1abc", "sei1abd", 1},
+		{"sei1abc", "sei1ab", 1},
+		{"sei1abc", "sei1xabc", 1},
+		{"kitten", "sitting", 3},
+		{"", "sei", 3},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// typo replaces the character of address at i with another bech32 character
+func typo(address string, i int) string {
+	replacement := "q"
+	if address[i] == 'q' {
+		replacement = "p"
+	}
+	return address[:i] + replacement + address[i+1:]
+}
+
+func TestSuggestAddress(t *testing.T) {
+	stores := map[string]Store{
+		"sqlcipher": newTestStore(t),
+		"memory":    NewMemoryStore(),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			active, archived := newTestAccount(t), newTestAccount(t)
+			if _, err := store.SaveAccounts([]*Account{active, archived}); err != nil {
+				t.Fatalf("SaveAccounts() error = %v", err)
+			}
+			if err := store.DeleteAccount(archived.Address); err != nil {
+				t.Fatalf("DeleteAccount() error = %v", err)
+			}
+
+			tests := []struct {
+				name    string
+				address string
+				want    string
+			}{
+				{"one character off", typo(active.Address, 10), active.Address},
+				{"character missing", active.Address[:len(active.Address)-1], active.Address},
+				{"unrelated", newTestAccount(t).Address, ""},
+				{"exact active address", active.Address, ""},
+				{"exact archived address", archived.Address, ""},
+				{"near an archived address only", typo(archived.Address, 10), ""},
+			}
+			for _, tt := range tests {
+				got, ok, err := store.SuggestAddress(tt.address)
+				if err != nil {
+					t.Fatalf("%s: SuggestAddress() error = %v", tt.name, err)
+				}
+				if got != tt.want || ok != (tt.want != "") {
+					t.Errorf("%s: SuggestAddress() = %q, %v, want %q", tt.name, got, ok, tt.want)
+				}
+			}
+
+			// Lookups no longer compute suggestions themselves
+			if _, err := store.GetAccountByAddress(typo(active.Address, 10)); err == nil || strings.Contains(err.Error(), "did you mean") {
+				t.Errorf("GetAccountByAddress() of a typo error = %v, want one without a suggestion", err)
+			}
+		})
+	}
+}
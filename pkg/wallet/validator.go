@@ -0,0 +1,85 @@
+package wallet
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	sdked25519 "github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// PrivValidatorKeyFileName is the file a CometBFT node reads its consensus key from
+const PrivValidatorKeyFileName = "priv_validator_key.json"
+
+// Amino type names CometBFT uses for ed25519 keys in priv_validator_key.json
+const (
+	pubKeyEd25519Type  = "tendermint/PubKeyEd25519"
+	privKeyEd25519Type = "tendermint/PrivKeyEd25519"
+)
+
+// ValidatorKey is an ed25519 consensus key for running a validator
+type ValidatorKey struct {
+	// ConsAddress is the bech32 consensus address, e.g. seivalcons1...
+	ConsAddress string
+	// PrivValidatorKey is the key in the priv_validator_key.json format
+	PrivValidatorKey PrivValidatorKey
+}
+
+// PrivValidatorKey mirrors CometBFT's priv_validator_key.json
+type PrivValidatorKey struct {
+	Address string       `json:"address"`
+	PubKey  aminoJSONKey `json:"pub_key"`
+	PrivKey aminoJSONKey `json:"priv_key"`
+}
+
+// aminoJSONKey is a key encoded as CometBFT's amino JSON: a type name and base64 bytes
+type aminoJSONKey struct {
+	Type  string `json:"type"`
+	Value []byte `json:"value"`
+}
+
+// GenerateValidatorKey creates a new random ed25519 consensus key. The
+// consensus address uses the configured chain's valcons prefix.
+func GenerateValidatorKey() (*ValidatorKey, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate consensus key: %w", err)
+	}
+
+	privKey := &sdked25519.PrivKey{Key: priv}
+	pubKey := privKey.PubKey()
+	address := pubKey.Address()
+
+	return &ValidatorKey{
+		ConsAddress: sdk.ConsAddress(address).String(),
+		PrivValidatorKey: PrivValidatorKey{
+			Address: strings.ToUpper(address.String()),
+			PubKey:  aminoJSONKey{Type: pubKeyEd25519Type, Value: pubKey.Bytes()},
+			PrivKey: aminoJSONKey{Type: privKeyEd25519Type, Value: privKey.Bytes()},
+		},
+	}, nil
+}
+
+// WriteFile writes the key as priv_validator_key.json to filePath with 0600
+// permissions. An existing file is never overwritten, since replacing a live
+// validator's key would lose it.
+func (k *ValidatorKey) WriteFile(filePath string) error {
+	data, err := json.MarshalIndent(k.PrivValidatorKey, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal validator key: %w", err)
+	}
+
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create validator key file: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write validator key file: %w", err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,107 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Positions accepted by GenerateVanityAccount
+const (
+	VanityPrefix = "prefix"
+	VanitySuffix = "suffix"
+)
+
+// bech32Charset is the set of characters that can appear in the data part of a
+// bech32 string. It excludes 1, b, i and o.
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// GenerateVanityAccount generates accounts until the data part of the address
+// (after "sei1") starts or ends with pattern, depending on position. The search
+// runs on one goroutine per CPU and stops as soon as any of them finds a match.
+// It returns the matching account and the total number of attempts made.
+func GenerateVanityAccount(pattern, position string, coinType uint32, words int, passphrase string) (*Account, int, error) {
+	return GenerateVanityAccountContext(context.Background(), pattern, position, coinType, words, passphrase)
+}
+
+// GenerateVanityAccountContext is like GenerateVanityAccount but stops searching
+// when ctx is cancelled, returning ctx's error
+func GenerateVanityAccountContext(ctx context.Context, pattern, position string, coinType uint32, words int, passphrase string) (*Account, int, error) {
+	pattern = strings.ToLower(pattern)
+	if err := ValidateVanityPattern(pattern); err != nil {
+		return nil, 0, err
+	}
+	if position != VanityPrefix && position != VanitySuffix {
+		return nil, 0, fmt.Errorf("invalid vanity position %q: must be %q or %q", position, VanityPrefix, VanitySuffix)
+	}
+
+	// Addresses look like <hrp>1<data>, so prefix matches start after the separator
+	hrp := sdk.GetConfig().GetBech32AccountAddrPrefix() + "1"
+	matches := func(address string) bool {
+		data := strings.TrimPrefix(address, hrp)
+		if position == VanityPrefix {
+			return strings.HasPrefix(data, pattern)
+		}
+		return strings.HasSuffix(data, pattern)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		attempts atomic.Int64
+		once     sync.Once
+		found    *Account
+		firstErr error
+		wg       sync.WaitGroup
+	)
+
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				account, err := GenerateAccount(coinType, words, passphrase)
+				attempts.Add(1)
+				if err != nil {
+					once.Do(func() { firstErr = err })
+					cancel()
+					return
+				}
+				if matches(account.Address) {
+					once.Do(func() { found = account })
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, int(attempts.Load()), fmt.Errorf("failed to generate vanity account: %w", firstErr)
+	}
+	if found == nil {
+		return nil, int(attempts.Load()), fmt.Errorf("vanity search stopped: %w", ctx.Err())
+	}
+	return found, int(attempts.Load()), nil
+}
+
+// ValidateVanityPattern rejects patterns that can never appear in a bech32 address
+func ValidateVanityPattern(pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("vanity pattern must not be empty")
+	}
+	for _, c := range pattern {
+		if !strings.ContainsRune(bech32Charset, c) {
+			return fmt.Errorf("invalid vanity pattern %q: character %q is not in the bech32 charset (1, b, i and o are excluded)", pattern, c)
+		}
+	}
+	return nil
+}
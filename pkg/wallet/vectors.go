@@ -0,0 +1,111 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/cosmos/go-bip39"
+)
+
+// derivationVector is a mnemonic with the keys it is known to derive. Fields
+// left empty were not published with the vector and are not compared.
+type derivationVector struct {
+	mnemonic   string
+	passphrase string
+	path       string
+	// address is given with the cosmos prefix; only its data part is compared,
+	// so the vectors hold whichever chain is configured
+	address    string
+	pubKey     string
+	privateKey string
+}
+
+// derivationVectors pin the output of deriveFromMnemonic with independently
+// published results for the "abandon ... about" BIP39 test mnemonic: its
+// Cosmos account, and its first Ethereum key (address
+// 0x9858EfFD232B4033E47d90003D41EC34EcaEda94), which exercises another
+// coin type.
+var derivationVectors = []derivationVector{
+	{
+		mnemonic:   "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		path:       "m/44'/118'/0'/0/0",
+		address:    "cosmos19rl4cm2hmr8afy4kldpxz3fka4jguq0auqdal4",
+		pubKey:     "024f4e2ad99c34d60b9ba6283c9431a8418af8673212961f97a77b6377fcd05b62",
+		privateKey: "c4a48e2fce1481cd3294b4490f6678090ea98d3d0e5cd984558ab0968741b104",
+	},
+	{
+		mnemonic:   "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		path:       "m/44'/60'/0'/0/0",
+		privateKey: "1ab42cc412b618bdea3a599e3c9bae199ebf030895b039e9db1e30dafb12b727",
+	},
+}
+
+// seedVector is a mnemonic and passphrase with the BIP39 seed they stretch to
+type seedVector struct {
+	mnemonic   string
+	passphrase string
+	seed       string
+}
+
+// seedVectors are taken from the BIP39 reference test vectors, which all use
+// the passphrase "TREZOR", so the passphrase handling is pinned as well
+var seedVectors = []seedVector{
+	{
+		mnemonic:   "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		passphrase: "TREZOR",
+		seed:       "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+	},
+	{
+		mnemonic:   "legal winner thank year wave sausage worth useful legal winner thank yellow",
+		passphrase: "TREZOR",
+		seed:       "2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+	},
+	{
+		mnemonic:   "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		passphrase: "TREZOR",
+		seed:       "ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+	},
+}
+
+// VerifyDerivation derives a fixed set of known mnemonics and checks that they
+// still produce the expected addresses and keys, so a regression in the
+// derivation code or its dependencies is caught before any account is trusted
+func VerifyDerivation() error {
+	for i, v := range seedVectors {
+		if seed := hex.EncodeToString(bip39.NewSeed(v.mnemonic, v.passphrase)); seed != v.seed {
+			return fmt.Errorf("seed vector %d: derived seed %s, expected %s", i+1, seed, v.seed)
+		}
+	}
+
+	for i, v := range derivationVectors {
+		account, err := deriveFromMnemonic(AlgoSecp256k1, v.mnemonic, v.passphrase, v.path)
+		if err != nil {
+			return fmt.Errorf("derivation vector %d: %w", i+1, err)
+		}
+
+		if v.address != "" {
+			_, want, err := bech32.DecodeAndConvert(v.address)
+			if err != nil {
+				return fmt.Errorf("derivation vector %d: invalid expected address: %w", i+1, err)
+			}
+			got, err := sdk.AccAddressFromBech32(account.Address)
+			if err != nil {
+				return fmt.Errorf("derivation vector %d: %w", i+1, err)
+			}
+			if !bytes.Equal(got, want) {
+				return fmt.Errorf("derivation vector %d at %s: derived address %s, expected %s", i+1, v.path, account.Address, sdk.AccAddress(want))
+			}
+		}
+
+		switch {
+		case v.pubKey != "" && account.PubKey != v.pubKey:
+			return fmt.Errorf("derivation vector %d at %s: derived public key %s, expected %s", i+1, v.path, account.PubKey, v.pubKey)
+		case account.PrivateKey != v.privateKey:
+			return fmt.Errorf("derivation vector %d at %s: derived private key does not match", i+1, v.path)
+		}
+	}
+	return nil
+}
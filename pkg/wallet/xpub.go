@@ -0,0 +1,246 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/cosmos/btcutil/base58"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/go-bip39"
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// xpubVersion is the BIP32 version prefix of mainnet extended public keys ("xpub")
+var xpubVersion = []byte{0x04, 0x88, 0xb2, 0x1e}
+
+const (
+	// hardenedOffset is added to a BIP32 child index to select hardened derivation
+	hardenedOffset = 0x80000000
+	// extendedKeyLen is the length of a serialized extended key before its checksum
+	extendedKeyLen = 78
+)
+
+// extendedPubKey is a BIP32 extended public key: a public key, its chain code
+// and its position in the derivation tree
+type extendedPubKey struct {
+	depth       byte
+	fingerprint [4]byte
+	childNumber uint32
+	chainCode   [32]byte
+	pubKey      *secp.PublicKey
+}
+
+// ExtendedPubKey returns the account-level extended public key at
+// m/44'/118'/0' for the account's mnemonic, see ExtendedPubKeyForCoinType
+func (a *Account) ExtendedPubKey() (string, error) {
+	return a.ExtendedPubKeyForCoinType(DefaultCoinType)
+}
+
+// ExtendedPubKeyForCoinType returns the BIP32 extended public key ("xpub") at
+// the account level m/44'/{coinType}'/0'. A watch-only system can derive every
+// receive address m/44'/{coinType}'/0'/0/i from it with AddressFromExtendedPubKey
+// without access to any private key. It fails for accounts without a mnemonic
+// and for accounts that were not derived at index 0 of that path without a
+// BIP39 passphrase, since the xpub would then not match the stored address.
+func (a *Account) ExtendedPubKeyForCoinType(coinType uint32) (string, error) {
+	if algo := a.algorithm(); algo != AlgoSecp256k1 {
+		return "", fmt.Errorf("account %s uses %s keys; extended public keys only exist for secp256k1", a.Address, algo)
+	}
+	if a.Mnemonic == "" {
+		return "", fmt.Errorf("account %s has no mnemonic to derive an extended public key from", a.Address)
+	}
+	if err := ValidateMnemonic(a.Mnemonic); err != nil {
+		return "", err
+	}
+
+	key, chainCode := hd.ComputeMastersFromSeed(bip39.NewSeed(a.Mnemonic, ""))
+	var parentPub *secp.PublicKey
+	path := []uint32{44 + hardenedOffset, coinType + hardenedOffset, hardenedOffset}
+	for _, index := range path {
+		parentPub = secp.PrivKeyFromBytes(key[:]).PubKey()
+		var err error
+		if key, chainCode, err = deriveChildPrivate(key, chainCode, index); err != nil {
+			return "", err
+		}
+	}
+
+	xpub := &extendedPubKey{
+		depth:       byte(len(path)),
+		fingerprint: pubKeyFingerprint(parentPub),
+		childNumber: path[len(path)-1],
+		chainCode:   chainCode,
+		pubKey:      secp.PrivKeyFromBytes(key[:]).PubKey(),
+	}
+
+	// Refuse an xpub that would lead watch-only systems to the wrong addresses
+	first, err := xpub.receiveAddress(0)
+	if err != nil {
+		return "", err
+	}
+	if first != a.Address {
+		return "", fmt.Errorf("account %s was not derived at %s without a passphrase; the extended public key would derive %s",
+			a.Address, DerivationPath(coinType, 0), first)
+	}
+
+	return xpub.String(), nil
+}
+
+// AddressFromExtendedPubKey derives the receive address m/.../0/index below an
+// account-level extended public key such as one returned by ExtendedPubKey,
+// using the configured Bech32 prefix
+func AddressFromExtendedPubKey(xpub string, index uint32) (string, error) {
+	key, err := parseExtendedPubKey(xpub)
+	if err != nil {
+		return "", err
+	}
+	return key.receiveAddress(index)
+}
+
+// receiveAddress derives the address at external chain 0, address index index
+func (k *extendedPubKey) receiveAddress(index uint32) (string, error) {
+	if index >= hardenedOffset {
+		return "", fmt.Errorf("address index %d is out of range for public derivation", index)
+	}
+
+	external, err := k.child(0)
+	if err != nil {
+		return "", err
+	}
+	child, err := external.child(index)
+	if err != nil {
+		return "", err
+	}
+
+	pubKey := &secp256k1.PubKey{Key: child.pubKey.SerializeCompressed()}
+	return sdk.AccAddress(pubKey.Address()).String(), nil
+}
+
+// child derives the non-hardened child public key at index (BIP32 CKDpub)
+func (k *extendedPubKey) child(index uint32) (*extendedPubKey, error) {
+	data := make([]byte, 0, 37)
+	data = append(data, k.pubKey.SerializeCompressed()...)
+	data = binary.BigEndian.AppendUint32(data, index)
+	il, chainCode := hmacSHA512(k.chainCode, data)
+
+	var tweak secp.ModNScalar
+	if overflow := tweak.SetBytes(&il); overflow != 0 {
+		return nil, fmt.Errorf("invalid child key at index %d", index)
+	}
+
+	// The child key is IL*G + K
+	var point, parent, sum secp.JacobianPoint
+	secp.ScalarBaseMultNonConst(&tweak, &point)
+	k.pubKey.AsJacobian(&parent)
+	secp.AddNonConst(&point, &parent, &sum)
+	if (sum.X.IsZero() && sum.Y.IsZero()) || sum.Z.IsZero() {
+		return nil, fmt.Errorf("invalid child key at index %d", index)
+	}
+	sum.ToAffine()
+
+	return &extendedPubKey{
+		depth:       k.depth + 1,
+		fingerprint: pubKeyFingerprint(k.pubKey),
+		childNumber: index,
+		chainCode:   chainCode,
+		pubKey:      secp.NewPublicKey(&sum.X, &sum.Y),
+	}, nil
+}
+
+// String serializes the key in the standard Base58Check xpub format
+func (k *extendedPubKey) String() string {
+	payload := make([]byte, 0, extendedKeyLen+4)
+	payload = append(payload, xpubVersion...)
+	payload = append(payload, k.depth)
+	payload = append(payload, k.fingerprint[:]...)
+	payload = binary.BigEndian.AppendUint32(payload, k.childNumber)
+	payload = append(payload, k.chainCode[:]...)
+	payload = append(payload, k.pubKey.SerializeCompressed()...)
+	payload = append(payload, doubleSHA256(payload)[:4]...)
+	return base58.Encode(payload)
+}
+
+// parseExtendedPubKey decodes a Base58Check xpub string
+func parseExtendedPubKey(xpub string) (*extendedPubKey, error) {
+	payload := base58.Decode(xpub)
+	if len(payload) != extendedKeyLen+4 {
+		return nil, fmt.Errorf("invalid extended public key: wrong length")
+	}
+
+	data, checksum := payload[:extendedKeyLen], payload[extendedKeyLen:]
+	if !bytes.Equal(doubleSHA256(data)[:4], checksum) {
+		return nil, fmt.Errorf("invalid extended public key: checksum mismatch")
+	}
+	if !bytes.Equal(data[:4], xpubVersion) {
+		return nil, fmt.Errorf("invalid extended public key: not an xpub")
+	}
+
+	pubKey, err := secp.ParsePubKey(data[45:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid extended public key: %w", err)
+	}
+
+	key := &extendedPubKey{
+		depth:       data[4],
+		childNumber: binary.BigEndian.Uint32(data[9:13]),
+		pubKey:      pubKey,
+	}
+	copy(key.fingerprint[:], data[5:9])
+	copy(key.chainCode[:], data[13:45])
+	return key, nil
+}
+
+// deriveChildPrivate derives the child private key and chain code at index (BIP32 CKDpriv)
+func deriveChildPrivate(key, chainCode [32]byte, index uint32) ([32]byte, [32]byte, error) {
+	data := make([]byte, 0, 37)
+	if index >= hardenedOffset {
+		data = append(data, 0)
+		data = append(data, key[:]...)
+	} else {
+		data = append(data, secp.PrivKeyFromBytes(key[:]).PubKey().SerializeCompressed()...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+	il, childChainCode := hmacSHA512(chainCode, data)
+
+	// The child key is IL + k mod n
+	var tweak, parent secp.ModNScalar
+	if overflow := tweak.SetBytes(&il); overflow != 0 {
+		return [32]byte{}, [32]byte{}, fmt.Errorf("invalid child key at index %d", index)
+	}
+	parent.SetBytes(&key)
+	tweak.Add(&parent)
+	if tweak.IsZero() {
+		return [32]byte{}, [32]byte{}, fmt.Errorf("invalid child key at index %d", index)
+	}
+
+	return tweak.Bytes(), childChainCode, nil
+}
+
+// hmacSHA512 splits HMAC-SHA512(chainCode, data) into its left and right halves
+func hmacSHA512(chainCode [32]byte, data []byte) (left, right [32]byte) {
+	mac := hmac.New(sha512.New, chainCode[:])
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	copy(left[:], sum[:32])
+	copy(right[:], sum[32:])
+	return left, right
+}
+
+// pubKeyFingerprint returns the first four bytes of HASH160 of the compressed key
+func pubKeyFingerprint(pubKey *secp.PublicKey) [4]byte {
+	var fingerprint [4]byte
+	copy(fingerprint[:], (&secp256k1.PubKey{Key: pubKey.SerializeCompressed()}).Address())
+	return fingerprint
+}
+
+// doubleSHA256 returns SHA-256(SHA-256(data)), the Base58Check checksum hash
+func doubleSHA256(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:]
+}
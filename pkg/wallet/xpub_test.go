@@ -0,0 +1,92 @@
+package wallet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cosmos/btcutil/base58"
+)
+
+// bip44Xpub is the published account-level xpub of testMnemonic at m/44'/0'/0'
+const bip44Xpub = "xpub6BosfCnifzxcFwrSzQiqu2DBVTshkCXacvNsWGYJVVhhawA7d4R5WSWGFNbi8Aw6ZRc1brxMyWMzG3DSSSSoekkudhUd9yLb6qx39T9nMdj"
+
+func TestExtendedPubKeyVector(t *testing.T) {
+	account, err := ImportAccount(testMnemonic, "", 0)
+	if err != nil {
+		t.Fatalf("ImportAccount() error = %v", err)
+	}
+	xpub, err := account.ExtendedPubKeyForCoinType(0)
+	if err != nil {
+		t.Fatalf("ExtendedPubKeyForCoinType() error = %v", err)
+	}
+	if xpub != bip44Xpub {
+		t.Errorf("ExtendedPubKeyForCoinType(0) = %s, want %s", xpub, bip44Xpub)
+	}
+
+	// Public derivation below the xpub reaches the same addresses as the mnemonic
+	for index := uint32(0); index < 3; index++ {
+		got, err := AddressFromExtendedPubKey(xpub, index)
+		if err != nil {
+			t.Fatalf("AddressFromExtendedPubKey() error = %v", err)
+		}
+		want, err := deriveFromMnemonic(AlgoSecp256k1, testMnemonic, "", DerivationPath(0, index))
+		if err != nil {
+			t.Fatalf("deriveFromMnemonic() error = %v", err)
+		}
+		if got != want.Address {
+			t.Errorf("AddressFromExtendedPubKey() at index %d = %s, want %s", index, got, want.Address)
+		}
+	}
+
+	// The xpub is only given for the coin type the account was derived with
+	if _, err := account.ExtendedPubKeyForCoinType(DefaultCoinType); err == nil {
+		t.Error("ExtendedPubKeyForCoinType() with another coin type succeeded")
+	}
+}
+
+func TestParseExtendedPubKeyRoundTrip(t *testing.T) {
+	key, err := parseExtendedPubKey(bip44Xpub)
+	if err != nil {
+		t.Fatalf("parseExtendedPubKey() error = %v", err)
+	}
+	if key.depth != 3 || key.childNumber != hardenedOffset {
+		t.Errorf("parseExtendedPubKey() depth %d, child %#x, want 3 and %#x", key.depth, key.childNumber, hardenedOffset)
+	}
+	if got := key.String(); got != bip44Xpub {
+		t.Errorf("String() after parsing = %s, want %s", got, bip44Xpub)
+	}
+}
+
+func TestParseExtendedPubKeyRejects(t *testing.T) {
+	payload := base58.Decode(bip44Xpub)
+
+	// reencode replaces the version and private/public key data of the vector
+	// and appends a valid checksum
+	reencode := func(version []byte, keyData []byte) string {
+		data := append(append(append([]byte{}, version...), payload[4:45]...), keyData...)
+		return base58.Encode(append(data, doubleSHA256(data)[:4]...))
+	}
+	badChecksum := append([]byte{}, payload...)
+	badChecksum[len(badChecksum)-1] ^= 0xff
+
+	tests := []struct {
+		name string
+		xpub string
+		want string
+	}{
+		{name: "bad checksum", xpub: base58.Encode(badChecksum), want: "checksum"},
+		{name: "testnet version", xpub: reencode([]byte{0x04, 0x35, 0x87, 0xcf}, payload[45:78]), want: "not an xpub"},
+		{name: "xprv", xpub: reencode([]byte{0x04, 0x88, 0xad, 0xe4}, append([]byte{0}, make([]byte, 32)...)), want: "not an xpub"},
+		{name: "truncated", xpub: bip44Xpub[:len(bip44Xpub)-4], want: "length"},
+		{name: "invalid point", xpub: reencode(xpubVersion, append([]byte{0x02}, make([]byte, 32)...)), want: "invalid extended public key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseExtendedPubKey(tt.xpub)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseExtendedPubKey() error = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
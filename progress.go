@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// progressInterval is the shortest time between two redraws of the progress line
+const progressInterval = 100 * time.Millisecond
+
+// progress draws a single "done/total" line with an ETA on stderr, redrawing it
+// in place. It is disabled unless both stdout and stderr are terminals, so
+// redirected output and logs never contain it.
+type progress struct {
+	w       io.Writer
+	total   int
+	start   time.Time
+	enabled bool
+	drawn   bool
+	last    time.Time
+}
+
+// newProgress returns a progress line for total steps, starting the ETA clock now
+func newProgress(total int) *progress {
+	stdoutTTY := term.IsTerminal(int(os.Stdout.Fd()))
+	stderrTTY := term.IsTerminal(int(os.Stderr.Fd()))
+	return &progress{
+		w:       os.Stderr,
+		total:   total,
+		start:   time.Now(),
+		enabled: stdoutTTY && stderrTTY && total > 1,
+	}
+}
+
+// update redraws the line after done steps, at most every progressInterval
+// except for the last step. The ETA assumes the remaining steps take as long
+// on average as the ones so far.
+func (p *progress) update(done int) {
+	if !p.enabled || (done < p.total && time.Since(p.last) < progressInterval) {
+		return
+	}
+	p.last = time.Now()
+
+	elapsed := time.Since(p.start)
+	eta := time.Duration(0)
+	if done > 0 {
+		eta = elapsed / time.Duration(done) * time.Duration(p.total-done)
+	}
+	fmt.Fprintf(p.w, "\r\033[K%d/%d accounts (%d%%), ETA %s", done, p.total, done*100/p.total, eta.Round(time.Second))
+	p.drawn = true
+}
+
+// clear erases the line so other output can be printed; the next update redraws it
+func (p *progress) clear() {
+	if !p.drawn {
+		return
+	}
+	fmt.Fprint(p.w, "\r\033[K")
+	p.drawn = false
+	// Redraw on the next update even if it comes soon
+	p.last = time.Time{}
+}
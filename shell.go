@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"sei-account-generator/pkg/wallet"
+)
+
+// shellPrompt is printed before each command read by the shell
+const shellPrompt = "sei> "
+
+// shellHelp lists the commands the shell understands
+const shellHelp = `Commands:
+  list                        list the stored accounts
+  show [-secrets] <address>   print one account, with its mnemonic and private key if -secrets is given
+  generate [n]                generate and store n new accounts (default 1)
+  delete <address>            archive an account
+  balance [address]           query the balance of one account, or of all of them
+  help                        print this help
+  exit                        close the store and leave (or press Ctrl-D)`
+
+// shellSession holds what the shell's commands share while the store is open
+type shellSession struct {
+	store    *wallet.AccountStore
+	coinType uint32
+	balances wallet.BalanceQuerier
+}
+
+// runShell opens the store once and reads commands from stdin until exit or
+// end of input (Ctrl-D), so several operations need only one password prompt
+// and key derivation. The store is closed on the way out.
+func runShell(args []string) {
+	fs := flag.NewFlagSet("shell", flag.ExitOnError)
+	var opts storeOptions
+	opts.register(fs)
+	var balanceOpts balanceOptions
+	balanceOpts.register(fs)
+	coinTypeFlag := fs.Int("coin-type", -1, "BIP44 coin type for generated accounts (default: coin_type from the config file, else the chain's)")
+	cfg := opts.parse(fs, args)
+
+	chain := opts.configureChain()
+	coinType, err := resolveCoinType(*coinTypeFlag, cfg, chain)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	store, storageDir := opts.openStore()
+	defer store.Close()
+
+	balances, closeBalances := balanceOpts.client()
+	defer closeBalances()
+
+	session := &shellSession{store: store, coinType: coinType, balances: balances}
+	fmt.Printf("Opened %s; type help for commands, exit or Ctrl-D to leave\n", opts.dbPath(storageDir))
+
+	in := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print(shellPrompt)
+		line, err := in.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			fmt.Printf("\nError reading input: %v\n", err)
+			return
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) > 0 {
+			if fields[0] == "exit" || fields[0] == "quit" {
+				return
+			}
+			session.run(fields[0], fields[1:])
+		}
+
+		if errors.Is(err, io.EOF) {
+			fmt.Println()
+			return
+		}
+	}
+}
+
+// run executes one shell command, printing errors instead of exiting
+func (s *shellSession) run(name string, args []string) {
+	var err error
+	switch name {
+	case "help":
+		fmt.Println(shellHelp)
+	case "list":
+		err = s.list()
+	case "show":
+		err = s.show(args)
+	case "generate":
+		err = s.generate(args)
+	case "delete":
+		err = s.delete(args)
+	case "balance":
+		err = s.balance(args)
+	default:
+		err = fmt.Errorf("unknown command %q; type help for a list", name)
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	}
+}
+
+// list prints the stored accounts like the list command
+func (s *shellSession) list() error {
+	accounts, err := s.store.GetAccounts()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tLABEL\tADDRESS\tCREATED")
+	for i, account := range accounts {
+		printListRow(w, i+1, account, false)
+	}
+	return w.Flush()
+}
+
+// show prints one account like the show command
+func (s *shellSession) show(args []string) error {
+	secrets := len(args) > 0 && args[0] == "-secrets"
+	if secrets {
+		args = args[1:]
+	}
+	if len(args) != 1 {
+		return errors.New("usage: show [-secrets] <address>")
+	}
+
+	account, err := s.store.GetAccountByAddress(args[0])
+	if err != nil {
+		return withAddressSuggestion(s.store, args[0], err)
+	}
+	printAccountDetails(account, secrets)
+	return nil
+}
+
+// generate creates and stores new accounts, printing only their addresses
+func (s *shellSession) generate(args []string) error {
+	n := 1
+	switch len(args) {
+	case 0:
+	case 1:
+		var err error
+		if n, err = strconv.Atoi(args[0]); err != nil || n <= 0 {
+			return fmt.Errorf("account count must be a positive number, got %q", args[0])
+		}
+	default:
+		return errors.New("usage: generate [n]")
+	}
+
+	stored := 0
+	for i := 0; i < n; i++ {
+		account, err := wallet.GenerateAccount(s.coinType, wallet.DefaultMnemonicWords, "")
+		if err != nil {
+			return fmt.Errorf("failed to generate account: %w", err)
+		}
+		inserted, err := s.store.SaveAccount(account)
+		if err != nil {
+			return fmt.Errorf("failed to save account: %w", err)
+		}
+		if inserted {
+			stored++
+			fmt.Println(account.Address)
+		}
+	}
+	fmt.Printf("Stored %d new accounts; use show -secrets to see their mnemonics\n", stored)
+	return nil
+}
+
+// delete archives one account
+func (s *shellSession) delete(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: delete <address>")
+	}
+	if err := s.store.DeleteAccount(args[0]); err != nil {
+		return withAddressSuggestion(s.store, args[0], err)
+	}
+	fmt.Printf("Archived %s\n", args[0])
+	return nil
+}
+
+// balance queries the balance of the given account, or of every stored account
+func (s *shellSession) balance(args []string) error {
+	var addresses []string
+	switch len(args) {
+	case 0:
+		accounts, err := s.store.GetAccounts()
+		if err != nil {
+			return err
+		}
+		for _, account := range accounts {
+			addresses = append(addresses, account.Address)
+		}
+	case 1:
+		if err := wallet.ValidateSeiAddress(args[0]); err != nil {
+			return err
+		}
+		addresses = args
+	default:
+		return errors.New("usage: balance [address]")
+	}
+
+	for _, address := range addresses {
+		amount, err := s.balances.BalanceContext(context.Background(), address, wallet.BaseDenom)
+		if err != nil {
+			fmt.Printf("%s  error: %v\n", address, err)
+			continue
+		}
+		fmt.Printf("%s  %s%s\n", address, amount, wallet.BaseDenom)
+	}
+	return nil
+}
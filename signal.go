@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+)
+
+// shutdownContext returns a context that is cancelled when the process receives
+// SIGINT or SIGTERM, so long-running work can stop and close the store cleanly.
+// exitCode reports the conventional 128+n exit status for the signal received.
+// Only the first signal is caught; a second one terminates the process at once.
+func shutdownContext() (ctx context.Context, exitCode func() int, stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	var received atomic.Int32
+	go func() {
+		select {
+		case sig := <-signals:
+			signal.Stop(signals)
+			if s, ok := sig.(syscall.Signal); ok {
+				received.Store(int32(s))
+			}
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	exitCode = func() int {
+		return 128 + int(received.Load())
+	}
+	stop = func() {
+		signal.Stop(signals)
+		cancel()
+	}
+	return ctx, exitCode, stop
+}
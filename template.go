@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"sei-account-generator/pkg/wallet"
+)
+
+// defaultAccountTemplate reproduces the text block printed for each account
+const defaultAccountTemplate = `Account #{{.Number}}
+{{if .Label}}Label: {{.Label}}
+{{end}}Address: {{.Address}}
+{{if .Sealed}}Mnemonic: (sealed){{else if .Mnemonic}}Mnemonic: {{.Mnemonic}}{{else}}Mnemonic: (none, imported from private key){{end}}
+{{if and .Verbose .MnemonicBits}}Mnemonic Strength: {{.MnemonicBits}} bits
+{{end}}{{if ne .KeyAlgorithm "` + string(wallet.DefaultKeyAlgorithm) + `"}}Key Algorithm: {{.KeyAlgorithm}}
+{{end}}Public Key: {{.PubKey}}
+{{if not .Sealed}}Private Key: {{.PrivateKey}}
+{{end}}{{if .CreatedAt}}Created At: {{.CreatedAt}}
+{{end}}{{.QR}}=======================
+`
+
+// quietAccountTemplate is the default template with -quiet
+const quietAccountTemplate = "{{.Address}}\n{{.QR}}"
+
+// Parsed forms of the default templates
+var (
+	defaultTemplate = template.Must(template.New("account").Parse(defaultAccountTemplate))
+	quietTemplate   = template.Must(template.New("account").Parse(quietAccountTemplate))
+)
+
+// accountView is what a -template is evaluated against for each account. The
+// secret fields are spelled out so a template only shows them when asked to:
+// Mnemonic and PrivateKey are the full secrets and are empty with -quiet,
+// while MnemonicRedacted and PrivateKeyRedacted are shortened like the
+// library's String method and safe to log.
+type accountView struct {
+	// Number is the account's 1-based position in the output
+	Number         int
+	Address        string
+	Label          string
+	PubKey         string
+	KeyAlgorithm   string
+	DerivationPath string
+	// CreatedAt is in RFC 3339 form, or empty if the time is unknown
+	CreatedAt          string
+	Mnemonic           string
+	MnemonicRedacted   string
+	PrivateKey         string
+	PrivateKeyRedacted string
+	// Sealed reports that the secrets are encrypted and the store was opened
+	// without SEI_SECRETS_PASSPHRASE, so Mnemonic and PrivateKey are empty
+	Sealed bool
+	// MnemonicBits is the mnemonic's entropy strength, or 0 without one
+	MnemonicBits int
+	// Verbose reports whether -verbose was given
+	Verbose bool
+	// QR is a QR code of the address, ending in a newline, when -qr is set
+	QR string
+}
+
+// parseAccountTemplate parses a -template value. A newline is added if the
+// template does not end with one, so each account starts on its own line.
+// The template is tried against a sample account, so unknown fields are
+// reported before anything is generated.
+func parseAccountTemplate(text string) (*template.Template, error) {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	tmpl, err := template.New("account").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -template: %w", err)
+	}
+	if err := tmpl.Execute(io.Discard, accountView{}); err != nil {
+		return nil, fmt.Errorf("invalid -template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// newAccountView fills in the template data for the nth account. Secrets are
+// left out in quiet mode, and the QR code is only rendered when requested.
+func (o accountOutput) newAccountView(n int, account *wallet.Account) (accountView, error) {
+	view := accountView{
+		Number:             n,
+		Address:            account.Address,
+		Label:              account.Label,
+		PubKey:             account.PubKey,
+		KeyAlgorithm:       string(account.KeyAlgorithm),
+		DerivationPath:     account.DerivationPath,
+		MnemonicRedacted:   wallet.Redact(account.Mnemonic),
+		PrivateKeyRedacted: wallet.Redact(account.PrivateKey),
+		Sealed:             isSealed(account),
+		Verbose:            o.verbose,
+	}
+	if view.KeyAlgorithm == "" {
+		view.KeyAlgorithm = string(wallet.DefaultKeyAlgorithm)
+	}
+	if !account.CreatedAt.IsZero() {
+		view.CreatedAt = account.CreatedAt.Format(time.RFC3339)
+	}
+	if bits, err := account.MnemonicBits(); err == nil {
+		view.MnemonicBits = bits
+	}
+	if !o.quiet {
+		view.Mnemonic = account.Mnemonic
+		view.PrivateKey = account.PrivateKey
+	}
+	if o.qr {
+		qr, err := account.AddressQR()
+		if err != nil {
+			return view, fmt.Errorf("failed to render QR code: %w", err)
+		}
+		view.QR = qr
+	}
+	return view, nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"sei-account-generator/pkg/wallet"
+)
+
+// verifyCheck is one check run by the verify command. It returns the problems
+// found, or an error if the check itself could not be run.
+type verifyCheck struct {
+	name        string
+	description string
+	run         func(store *wallet.AccountStore) ([]string, error)
+}
+
+// verifyChecks lists the checks of the verify command in the order they run
+var verifyChecks = []verifyCheck{
+	{name: "integrity", description: "SQLCipher page HMACs and SQLite structure", run: checkIntegrity},
+	{name: "keys", description: "every account's keys derive its address", run: checkKeys},
+	{name: "mnemonics", description: "no two accounts share a mnemonic and path", run: checkMnemonics},
+}
+
+// verifyCheckNames returns the names of the verify checks in order
+func verifyCheckNames() []string {
+	names := make([]string, len(verifyChecks))
+	for i, check := range verifyChecks {
+		names[i] = check.name
+	}
+	return names
+}
+
+// runVerify runs the integrity, key and mnemonic checks against the store and
+// prints one report. Unlike doctor it keeps going after a failed check, so the
+// report covers everything, and -check limits it to the named checks. It exits
+// with status 1 if any check fails.
+func runVerify(args []string) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	var opts storeOptions
+	opts.register(fs)
+	checkFlag := fs.String("check", strings.Join(verifyCheckNames(), ","), "comma-separated checks to run ("+strings.Join(verifyCheckNames(), ", ")+")")
+	opts.parse(fs, args)
+
+	selected, err := selectVerifyChecks(*checkFlag)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	opts.configureChain()
+	store, storageDir := opts.openStore()
+	defer store.Close()
+
+	fmt.Printf("Verifying %s\n\n", opts.dbPath(storageDir))
+
+	failed := 0
+	for _, check := range selected {
+		problems, err := check.run(store)
+		if err != nil {
+			problems = append(problems, "check could not run: "+err.Error())
+		}
+		if len(problems) == 0 {
+			fmt.Printf("%-10s ok    %s\n", check.name, check.description)
+			continue
+		}
+
+		failed++
+		fmt.Printf("%-10s FAIL  %s\n", check.name, check.description)
+		for _, problem := range problems {
+			fmt.Printf("             %s\n", problem)
+		}
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		fmt.Printf("%d of %d checks failed\n", failed, len(selected))
+		closeAndExit(1, store)
+	}
+	fmt.Println("All checks passed")
+}
+
+// selectVerifyChecks returns the checks named in the comma-separated list, in
+// their usual order
+func selectVerifyChecks(list string) ([]verifyCheck, error) {
+	wanted := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			wanted[name] = true
+		}
+	}
+	if len(wanted) == 0 {
+		return nil, fmt.Errorf("-check needs at least one of: %s", strings.Join(verifyCheckNames(), ", "))
+	}
+
+	var selected []verifyCheck
+	for _, check := range verifyChecks {
+		if wanted[check.name] {
+			selected = append(selected, check)
+			delete(wanted, check.name)
+		}
+	}
+	for name := range wanted {
+		return nil, fmt.Errorf("unknown check %q (supported: %s)", name, strings.Join(verifyCheckNames(), ", "))
+	}
+	return selected, nil
+}
+
+// checkIntegrity runs the database's own integrity checks
+func checkIntegrity(store *wallet.AccountStore) ([]string, error) {
+	if err := store.CheckIntegrity(); err != nil {
+		return []string{err.Error()}, nil
+	}
+	return nil, nil
+}
+
+// lockedSecretsProblem is reported by checks that need the account secrets
+// when they are encrypted and the store was opened without their passphrase,
+// so a sealed store does not pass them without being checked
+const lockedSecretsProblem = "account secrets are encrypted; set " + SecretsPassphraseEnvVar + " to check them"
+
+// checkKeys re-derives every stored account, archived ones included
+func checkKeys(store *wallet.AccountStore) ([]string, error) {
+	failures, err := store.VerifyAll()
+	if errors.Is(err, wallet.ErrSecretsLocked) {
+		return []string{lockedSecretsProblem}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	for _, failure := range failures {
+		problems = append(problems, fmt.Sprintf("%s: %v", failure.Address, failure.Err))
+	}
+	return problems, nil
+}
+
+// checkMnemonics reports groups of accounts that share a mnemonic at the same
+// derivation path
+func checkMnemonics(store *wallet.AccountStore) ([]string, error) {
+	duplicates, err := store.FindDuplicateMnemonics()
+	if errors.Is(err, wallet.ErrSecretsLocked) {
+		return []string{lockedSecretsProblem}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	for _, group := range duplicates {
+		problems = append(problems, "shared mnemonic: "+strings.Join(group, ", "))
+	}
+	return problems, nil
+}
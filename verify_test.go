@@ -0,0 +1,40 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"sei-account-generator/pkg/wallet"
+)
+
+func TestVerifyChecksFailOnLockedSecrets(t *testing.T) {
+	dir := t.TempDir()
+	config := wallet.DefaultStoreConfig()
+	config.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	config.SecretsPassphrase = "secrets-passphrase"
+	sealed, err := wallet.NewAccountStoreWithConfig(dir, testPassword, config)
+	if err != nil {
+		t.Fatalf("NewAccountStoreWithConfig() error = %v", err)
+	}
+	account, err := wallet.GenerateAccount(wallet.DefaultCoinType, wallet.DefaultMnemonicWords, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sealed.SaveAccount(account); err != nil {
+		t.Fatalf("SaveAccount() error = %v", err)
+	}
+	sealed.Close()
+
+	// Without the secrets passphrase neither check can look at the secrets
+	store := openTestStore(t, dir)
+	for _, check := range verifyChecks {
+		if check.name == "integrity" {
+			continue
+		}
+		problems, err := check.run(store)
+		if err != nil || len(problems) != 1 || problems[0] != lockedSecretsProblem {
+			t.Errorf("%s check on a locked store = %v, %v, want %q", check.name, problems, err, lockedSecretsProblem)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/term"
+
+	"sei-account-generator/pkg/wallet"
+)
+
+// ANSI escape sequences used by the watch display on a terminal
+const (
+	clearScreen = "\033[H\033[2J"
+	colorGreen  = "\033[32m"
+	colorRed    = "\033[31m"
+	colorReset  = "\033[0m"
+)
+
+// watchedBalance is the last known balance of one account and how it last changed
+type watchedBalance struct {
+	amount string
+	change string
+}
+
+// runWatch polls the balance of every stored account until interrupted,
+// redrawing a table that marks the balances which changed since the last poll.
+// A failed query ends the round early and doubles the wait before the next
+// one, up to -max-backoff, so a rate-limiting node is not hammered.
+func runWatch(args []string) {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	var opts storeOptions
+	opts.register(fs)
+	var balanceOpts balanceOptions
+	balanceOpts.register(fs)
+	intervalFlag := fs.Duration("interval", 10*time.Second, "time between polls")
+	maxBackoffFlag := fs.Duration("max-backoff", 5*time.Minute, "longest wait between polls after failed queries")
+	opts.parse(fs, args)
+
+	if *intervalFlag <= 0 {
+		fmt.Println("Error: -interval must be positive")
+		os.Exit(1)
+	}
+
+	opts.configureChain()
+	store, _ := opts.openStore()
+	defer store.Close()
+
+	ctx, _, stop := shutdownContext()
+	defer stop()
+
+	client, closeClient := balanceOpts.client()
+	defer closeClient()
+	terminal := term.IsTerminal(int(os.Stdout.Fd()))
+	balances := make(map[string]*watchedBalance)
+
+	ticker := time.NewTicker(*intervalFlag)
+	defer ticker.Stop()
+
+	wait := *intervalFlag
+	for {
+		// Re-read the accounts each round so ones generated meanwhile show up
+		accounts, err := store.GetAccounts()
+		if err != nil {
+			fmt.Printf("Error retrieving accounts: %v\n", err)
+			return
+		}
+
+		pollErr := pollBalances(ctx, client, accounts, balances)
+		if ctx.Err() != nil {
+			break
+		}
+
+		if pollErr != nil {
+			wait = max(min(wait*2, *maxBackoffFlag), *intervalFlag)
+		} else {
+			wait = *intervalFlag
+		}
+		ticker.Reset(wait)
+
+		if terminal {
+			fmt.Print(clearScreen)
+		}
+		printBalances(os.Stdout, accounts, balances, terminal)
+		fmt.Printf("\nUpdated %s from %s; next poll in %s (Ctrl-C to stop)\n", time.Now().Format(time.TimeOnly), balanceOpts.endpoint(), wait)
+		if pollErr != nil {
+			fmt.Printf("Query failed, backing off: %v\n", pollErr)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	fmt.Println("Stopped watching balances.")
+}
+
+// pollBalances queries the balance of each account and records it in balances,
+// noting how much it changed. It stops at the first failed query so the caller
+// can back off, leaving the remaining balances as they were.
+func pollBalances(ctx context.Context, client wallet.BalanceQuerier, accounts []*wallet.Account, balances map[string]*watchedBalance) error {
+	for _, account := range accounts {
+		amount, err := client.BalanceContext(ctx, account.Address, wallet.BaseDenom)
+		if err != nil {
+			return fmt.Errorf("%s: %w", account.Address, err)
+		}
+
+		previous, seen := balances[account.Address]
+		current := &watchedBalance{amount: amount}
+		if seen && previous.amount != amount {
+			current.change = balanceChange(previous.amount, amount)
+		}
+		balances[account.Address] = current
+	}
+	return nil
+}
+
+// balanceChange formats the difference between two base-denom amounts, such
+// as +1000000usei. Amounts that are not integers are shown as old -> new.
+func balanceChange(previous, current string) string {
+	oldAmount, okOld := new(big.Int).SetString(previous, 10)
+	newAmount, okNew := new(big.Int).SetString(current, 10)
+	if !okOld || !okNew {
+		return previous + " -> " + current
+	}
+
+	delta := new(big.Int).Sub(newAmount, oldAmount)
+	sign := ""
+	if delta.Sign() > 0 {
+		sign = "+"
+	}
+	return sign + delta.String() + wallet.BaseDenom
+}
+
+// printBalances writes the ADDRESS/BALANCE/CHANGE table to w. On a terminal,
+// increases are shown in green and decreases in red.
+func printBalances(w io.Writer, accounts []*wallet.Account, balances map[string]*watchedBalance, color bool) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ADDRESS\tBALANCE\tCHANGE")
+	for _, account := range accounts {
+		balance, ok := balances[account.Address]
+		if !ok {
+			fmt.Fprintf(tw, "%s\t?\t\n", account.Address)
+			continue
+		}
+
+		// CHANGE is the last column, so escape codes do not upset the alignment
+		change := balance.change
+		if color && change != "" {
+			code := colorGreen
+			if change[0] == '-' {
+				code = colorRed
+			}
+			change = code + change + colorReset
+		}
+		fmt.Fprintf(tw, "%s\t%s%s\t%s\n", account.Address, balance.amount, wallet.BaseDenom, change)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"sei-account-generator/pkg/wallet"
+)
+
+func TestBalanceChange(t *testing.T) {
+	tests := []struct {
+		previous, current string
+		want              string
+	}{
+		{previous: "100", current: "250", want: "+150usei"},
+		{previous: "250", current: "100", want: "-150usei"},
+		{previous: "0", current: "123456789012345678901234567890", want: "+123456789012345678901234567890usei"},
+		{previous: "5", current: "5", want: "0usei"},
+		{previous: "1.5", current: "2", want: "1.5 -> 2"},
+		{previous: "7", current: "", want: "7 -> "},
+	}
+
+	for _, tt := range tests {
+		if got := balanceChange(tt.previous, tt.current); got != tt.want {
+			t.Errorf("balanceChange(%q, %q) = %q, want %q", tt.previous, tt.current, got, tt.want)
+		}
+	}
+}
+
+// fakeBalances serves fixed base-denom balances and fails for unknown addresses
+type fakeBalances map[string]string
+
+func (f fakeBalances) BalancesContext(ctx context.Context, address string) ([]wallet.Coin, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f fakeBalances) BalanceContext(ctx context.Context, address, denom string) (string, error) {
+	amount, ok := f[address]
+	if !ok {
+		return "", errors.New("rate limited")
+	}
+	return amount, nil
+}
+
+func TestPollBalances(t *testing.T) {
+	accounts := []*wallet.Account{{Address: "sei1a"}, {Address: "sei1b"}, {Address: "sei1c"}}
+	client := fakeBalances{"sei1a": "100", "sei1b": "200", "sei1c": "300"}
+	balances := make(map[string]*watchedBalance)
+
+	if err := pollBalances(context.Background(), client, accounts, balances); err != nil {
+		t.Fatalf("pollBalances() error = %v", err)
+	}
+	for _, account := range accounts {
+		if balances[account.Address].change != "" {
+			t.Errorf("first poll of %s has change %q, want none", account.Address, balances[account.Address].change)
+		}
+	}
+
+	// The failed query stops the round and leaves later balances as they were
+	client["sei1a"] = "150"
+	delete(client, "sei1b")
+	client["sei1c"] = "0"
+	err := pollBalances(context.Background(), client, accounts, balances)
+	if err == nil || !strings.Contains(err.Error(), "sei1b") {
+		t.Fatalf("pollBalances() error = %v, want the failing address", err)
+	}
+	if got := balances["sei1a"]; got.amount != "150" || got.change != "+50usei" {
+		t.Errorf("sei1a = %+v, want 150 changed by +50usei", got)
+	}
+	if got := balances["sei1c"]; got.amount != "300" || got.change != "" {
+		t.Errorf("sei1c = %+v, want the unchanged 300", got)
+	}
+
+	// An unchanged balance clears the previous change
+	client["sei1b"] = "200"
+	if err := pollBalances(context.Background(), client, accounts, balances); err != nil {
+		t.Fatalf("pollBalances() error = %v", err)
+	}
+	if got := balances["sei1a"]; got.change != "" {
+		t.Errorf("sei1a change = %q after an unchanged poll, want none", got.change)
+	}
+	if got := balances["sei1c"]; got.change != "-300usei" {
+		t.Errorf("sei1c change = %q, want -300usei", got.change)
+	}
+}
+
+func TestPrintBalances(t *testing.T) {
+	accounts := []*wallet.Account{{Address: "sei1a"}, {Address: "sei1b"}, {Address: "sei1c"}}
+	balances := map[string]*watchedBalance{
+		"sei1a": {amount: "150", change: "+50usei"},
+		"sei1b": {amount: "0", change: "-300usei"},
+	}
+
+	var plain bytes.Buffer
+	printBalances(&plain, accounts, balances, false)
+	want := "ADDRESS  BALANCE  CHANGE\n" +
+		"sei1a    150usei  +50usei\n" +
+		"sei1b    0usei    -300usei\n" +
+		"sei1c    ?        \n"
+	if plain.String() != want {
+		t.Errorf("printBalances() = %q, want %q", plain.String(), want)
+	}
+
+	var colored bytes.Buffer
+	printBalances(&colored, accounts, balances, true)
+	if out := colored.String(); !strings.Contains(out, colorGreen+"+50usei"+colorReset) || !strings.Contains(out, colorRed+"-300usei"+colorReset) {
+		t.Errorf("printBalances() with color = %q, want green increases and red decreases", out)
+	}
+}